@@ -19,6 +19,8 @@ func main() {
 
 		// 初始化 zap.Logger
 		ioc.LoggerFxOpt,
+		// 初始化 OpenTelemetry
+		ioc.OtelFxOpt,
 		// 初始化 id 生成器
 		ioc.IdGeneratorFxOpt,
 		// 初始化 go cache
@@ -33,12 +35,26 @@ func main() {
 		ioc.EtcdFxOpt,
 		// 初始化 grpc registry
 		ioc.RegistryFxOpt,
+		// 初始化 kafka
+		ioc.KafkaFxOpt,
 		// 初始化 repository
 		ioc.RepoFxOpt,
 		// 初始化 service
 		ioc.ServiceFxOpt,
+		// 初始化消息发送
+		ioc.SenderFxOpt,
 		// 初始化 grpc
 		ioc.GrpcFxOpt,
+		// 初始化 http
+		ioc.HttpFxOpt,
+		// 初始化模板供应商同步任务
+		ioc.TplSyncFxOpt,
+		// 初始化回调定时任务
+		ioc.CallbackSchedulerFxOpt,
+		// 初始化回调 outbox 转发任务
+		ioc.CallbackOutboxRelayFxOpt,
+		// 初始化异步发送
+		ioc.AsyncSendFxOpt,
 
 		// 初始化 ioc.App
 		ioc.AppFxOpt,
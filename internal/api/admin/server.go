@@ -0,0 +1,130 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/pprof"
+	"runtime/debug"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/zap"
+)
+
+// Version 构建版本，构建时通过 -ldflags "-X github.com/JrMarcco/kuryr/internal/api/admin.Version=<version>" 注入。
+var Version = "dev"
+
+// Health 应用就绪状态，由 App 在服务启动完成后设置为就绪，停止时设置为未就绪。
+type Health struct {
+	ready atomic.Bool
+}
+
+func (h *Health) SetReady(ready bool) {
+	h.ready.Store(ready)
+}
+
+func (h *Health) Ready() bool {
+	return h.ready.Load()
+}
+
+func NewHealth() *Health {
+	return &Health{}
+}
+
+// BuildInfo 构建信息。
+type BuildInfo struct {
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	GoVersion string `json:"go_version"`
+	Revision  string `json:"revision,omitempty"`
+	BuildTime string `json:"build_time,omitempty"`
+	Modified  bool   `json:"modified"`
+}
+
+// Server 运维管理入口。
+//
+// 路由：
+//
+//	GET /metrics       prometheus 指标
+//	GET /debug/pprof/  pprof 性能分析
+//	GET /healthz       存活检查，进程存活即返回 200
+//	GET /readyz        就绪检查，服务启动完成且未开始停止时返回 200，否则返回 503
+//	GET /buildinfo     构建信息
+type Server struct {
+	gatherer  prometheus.Gatherer
+	health    *Health
+	buildInfo BuildInfo
+
+	logger *zap.Logger
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", promhttp.HandlerFor(s.gatherer, promhttp.HandlerOpts{}))
+
+	mux.HandleFunc("GET /debug/pprof/", pprof.Index)
+	mux.HandleFunc("GET /debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("GET /debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("GET /debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("POST /debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("GET /debug/pprof/trace", pprof.Trace)
+
+	mux.HandleFunc("GET /healthz", s.handleHealthz)
+	mux.HandleFunc("GET /readyz", s.handleReadyz)
+	mux.HandleFunc("GET /buildinfo", s.handleBuildInfo)
+	return mux
+}
+
+func (s *Server) handleHealthz(w http.ResponseWriter, _ *http.Request) {
+	_, _ = w.Write([]byte("ok"))
+}
+
+func (s *Server) handleReadyz(w http.ResponseWriter, _ *http.Request) {
+	if !s.health.Ready() {
+		http.Error(w, "not ready", http.StatusServiceUnavailable)
+		return
+	}
+	_, _ = w.Write([]byte("ok"))
+}
+
+func (s *Server) handleBuildInfo(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(s.buildInfo); err != nil {
+		s.logger.Error("[kuryr] failed to write build info", zap.Error(err))
+	}
+}
+
+func readBuildInfo(name string) BuildInfo {
+	info := BuildInfo{Name: name, Version: Version}
+
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+
+	info.GoVersion = bi.GoVersion
+	if info.Version == "dev" && bi.Main.Version != "" && bi.Main.Version != "(devel)" {
+		info.Version = bi.Main.Version
+	}
+	for _, setting := range bi.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			info.Revision = setting.Value
+		case "vcs.time":
+			info.BuildTime = setting.Value
+		case "vcs.modified":
+			info.Modified = setting.Value == "true"
+		}
+	}
+	return info
+}
+
+func NewServer(name string, gatherer prometheus.Gatherer, health *Health, logger *zap.Logger) *Server {
+	return &Server{
+		gatherer:  gatherer,
+		health:    health,
+		buildInfo: readBuildInfo(name),
+		logger:    logger,
+	}
+}
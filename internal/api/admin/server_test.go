@@ -0,0 +1,61 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestServer(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+	counter := prometheus.NewCounter(prometheus.CounterOpts{Name: "kuryr_test_count", Help: "test"})
+	registry.MustRegister(counter)
+	counter.Inc()
+
+	tcs := []struct {
+		name     string
+		path     string
+		ready    bool
+		wantCode int
+		wantBody string
+	}{
+		{name: "healthz", path: "/healthz", wantCode: http.StatusOK, wantBody: "ok"},
+		{name: "readyz before ready", path: "/readyz", wantCode: http.StatusServiceUnavailable, wantBody: "not ready"},
+		{name: "readyz after ready", path: "/readyz", ready: true, wantCode: http.StatusOK, wantBody: "ok"},
+		{name: "metrics", path: "/metrics", wantCode: http.StatusOK, wantBody: "kuryr_test_count 1"},
+		{name: "pprof", path: "/debug/pprof/", wantCode: http.StatusOK, wantBody: "goroutine"},
+		{name: "buildinfo", path: "/buildinfo", wantCode: http.StatusOK, wantBody: `"name":"kuryr"`},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			health := NewHealth()
+			health.SetReady(tc.ready)
+			handler := NewServer("kuryr", registry, health, zap.NewNop()).Handler()
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.path, nil))
+
+			assert.Equal(t, tc.wantCode, rec.Code)
+			assert.Contains(t, rec.Body.String(), tc.wantBody)
+
+			if tc.path == "/buildinfo" {
+				var info BuildInfo
+				require.NoError(t, json.NewDecoder(strings.NewReader(rec.Body.String())).Decode(&info))
+				assert.NotEmpty(t, info.GoVersion)
+				assert.NotEmpty(t, info.Version)
+			}
+		})
+	}
+}
@@ -239,14 +239,15 @@ func (s *BizConfigServer) domainToPb(bizConfig domain.BizConfig) *configv1.BizCo
 			}
 		})
 
-		retryPolicyConfig := bizConfig.ChannelConfig.RetryPolicyConfig.ExponentialBackoff
 		pb.ChannelConfig = &configv1.ChannelConfig{
 			Items: items,
-			RetryPolicy: &configv1.RetryPolicyConfig{
-				InitIntervalMs: int32(retryPolicyConfig.InitialInterval.Milliseconds()),
-				MaxIntervalMs:  int32(retryPolicyConfig.MaxInterval.Milliseconds()),
-				MaxRetryTimes:  retryPolicyConfig.MaxRetryTimes,
-			},
+		}
+		if retryPolicyConfig := bizConfig.ChannelConfig.RetryPolicyConfig; retryPolicyConfig != nil && retryPolicyConfig.ExponentialBackoff != nil {
+			pb.ChannelConfig.RetryPolicy = &configv1.RetryPolicyConfig{
+				InitIntervalMs: int32(retryPolicyConfig.ExponentialBackoff.InitialInterval.Milliseconds()),
+				MaxIntervalMs:  int32(retryPolicyConfig.ExponentialBackoff.MaxInterval.Milliseconds()),
+				MaxRetryTimes:  retryPolicyConfig.ExponentialBackoff.MaxRetryTimes,
+			}
 		}
 	}
 
@@ -270,14 +271,15 @@ func (s *BizConfigServer) domainToPb(bizConfig domain.BizConfig) *configv1.BizCo
 	}
 
 	if bizConfig.CallbackConfig != nil {
-		retryPolicyConfig := bizConfig.CallbackConfig.RetryPolicyConfig.ExponentialBackoff
 		pb.CallbackConfig = &configv1.CallbackConfig{
 			ServiceName: bizConfig.CallbackConfig.ServiceName,
-			RetryPolicy: &configv1.RetryPolicyConfig{
-				InitIntervalMs: int32(retryPolicyConfig.InitialInterval.Milliseconds()),
-				MaxIntervalMs:  int32(retryPolicyConfig.MaxInterval.Milliseconds()),
-				MaxRetryTimes:  retryPolicyConfig.MaxRetryTimes,
-			},
+		}
+		if retryPolicyConfig := bizConfig.CallbackConfig.RetryPolicyConfig; retryPolicyConfig != nil && retryPolicyConfig.ExponentialBackoff != nil {
+			pb.CallbackConfig.RetryPolicy = &configv1.RetryPolicyConfig{
+				InitIntervalMs: int32(retryPolicyConfig.ExponentialBackoff.InitialInterval.Milliseconds()),
+				MaxIntervalMs:  int32(retryPolicyConfig.ExponentialBackoff.MaxInterval.Milliseconds()),
+				MaxRetryTimes:  retryPolicyConfig.ExponentialBackoff.MaxRetryTimes,
+			}
 		}
 	}
 	return pb
@@ -3,11 +3,14 @@ package jwt
 import (
 	"context"
 	"crypto/ed25519"
+	"errors"
 	"fmt"
 	"maps"
+	"strconv"
 	"strings"
 	"time"
 
+	kuryrapi "github.com/JrMarcco/kuryr-api/api/go"
 	"github.com/golang-jwt/jwt/v5"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -81,29 +84,42 @@ func (b *InterceptorBuilder) Build() grpc.UnaryServerInterceptor {
 		if len(authHeaders) == 0 {
 			return nil, status.Error(codes.Unauthenticated, "missing authorization token")
 		}
-		//tokenStr := authHeaders[0]
-
-		//mc, err := b.Decode(tokenStr)
-		//if err != nil {
-		//	if errors.Is(err, jwt.ErrTokenExpired) {
-		//		return nil, status.Error(codes.Unauthenticated, "token expired")
-		//	}
-		//	if errors.Is(err, jwt.ErrTokenSignatureInvalid) {
-		//		return nil, status.Error(codes.Unauthenticated, "invalid signature")
-		//	}
-		//	return nil, status.Errorf(codes.Unauthenticated, "invalid token: %s", err.Error())
-		//}
-
-		//if val, ok := mc[paramBizId]; ok {
-		//	// 设置业务 id 到 context
-		//	bizId := uint64(val.(float64))
-		//	ctx = client.WithBizId(ctx, bizId)
-		//}
-		//if val, ok := mc[paramBizKey]; ok {
-		//	bizKey := val.(string)
-		//	ctx = client.WithBizKey(ctx, bizKey)
-		//}
+		mc, err := b.Decode(authHeaders[0])
+		if err != nil {
+			if errors.Is(err, jwt.ErrTokenExpired) {
+				return nil, status.Error(codes.Unauthenticated, "token expired")
+			}
+			if errors.Is(err, jwt.ErrTokenSignatureInvalid) {
+				return nil, status.Error(codes.Unauthenticated, "invalid signature")
+			}
+			return nil, status.Errorf(codes.Unauthenticated, "invalid token: %s", err.Error())
+		}
+
+		if uid, ok := claimUint64(mc, paramUid); ok {
+			// 设置操作用户 id 到 context
+			ctx = WithUid(ctx, uid)
+		}
+		if bizId, ok := claimUint64(mc, paramBizId); ok {
+			// 设置业务 id 到 context
+			ctx = context.WithValue(ctx, kuryrapi.ContextKeyBizId{}, bizId)
+		}
+		if bizKey, ok := mc[paramBizKey].(string); ok {
+			ctx = context.WithValue(ctx, kuryrapi.ContextKeyBizKey{}, bizKey)
+		}
 
 		return handler(ctx, req)
 	}
 }
+
+// claimUint64 读取数值类型的 claim，兼容数字与字符串两种格式。
+func claimUint64(mc jwt.MapClaims, key string) (uint64, bool) {
+	switch val := mc[key].(type) {
+	case float64:
+		return uint64(val), val > 0
+	case string:
+		n, err := strconv.ParseUint(val, 10, 64)
+		return n, err == nil && n > 0
+	default:
+		return 0, false
+	}
+}
@@ -9,10 +9,30 @@ import (
 )
 
 const (
+	paramUid    = "uid"
 	paramBizId  = "biz_id"
 	paramBizKey = "biz_key"
 )
 
+// contextKeyUid 当前操作用户 id
+type contextKeyUid struct{}
+
+func WithUid(ctx context.Context, uid uint64) context.Context {
+	return context.WithValue(ctx, contextKeyUid{}, uid)
+}
+
+func ContextUid(ctx context.Context) (uint64, error) {
+	val := ctx.Value(contextKeyUid{})
+	if val == nil {
+		return 0, fmt.Errorf("%w: uid is nil", errs.ErrInvalidParam)
+	}
+	uid, ok := val.(uint64)
+	if !ok {
+		return 0, fmt.Errorf("%w: uid is not uint64", errs.ErrInvalidParam)
+	}
+	return uid, nil
+}
+
 func ContextBizId(ctx context.Context) (uint64, error) {
 	val := ctx.Value(kuryrapi.ContextKeyBizId{})
 	if val == nil {
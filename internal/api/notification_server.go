@@ -2,8 +2,18 @@ package api
 
 import (
 	"context"
+	"errors"
+	"strconv"
+	"time"
 
+	"github.com/JrMarcco/easy-kit/slice"
 	notificationv1 "github.com/JrMarcco/kuryr-api/api/go/notification/v1"
+	"github.com/JrMarcco/kuryr/internal/api/interceptor/jwt"
+	"github.com/JrMarcco/kuryr/internal/domain"
+	"github.com/JrMarcco/kuryr/internal/errs"
+	"github.com/JrMarcco/kuryr/internal/service/notification"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 var _ notificationv1.NotificationServiceServer = (*NotificationServer)(nil)
@@ -13,37 +23,227 @@ var _ notificationv1.NotificationServiceServer = (*NotificationServer)(nil)
 // 消息发送调用链：
 //
 //	├── grpc client -> NotificationServer.Send / NotificationServer.AsyncSend / NotificationServer.BatchSend / NotificationServer.AsyncBatchSend
-//	└── NotificationServer 根据 Notification 的 SendStrategy 选择不同的策略发送消息 ( ImmediateSendStrategy 或 DefaultSendStrategy )
-//	    ├── DefaultSendStrategy 默认策略 ( 延迟发送策略 )
-//	   	│   └── 创建记录并入库，等待异步发送。
-//	    └── ImmediateSendStrategy 立即发送策略
-//	    	├── 创建记录并入库。
-//	    	├── ImmediateSendStrategy -> NotificationSender -> ChannelSender。
-//	    	├── ChannelSender 选择供应商，此时是真正的消息下发。
-//	    	└── 变更状态，返回结果。
+//	└── NotificationServer -> SendService -> TplValidateStrategy 按模板激活版本校验模板参数
+//	    └── Dispatcher 根据 Notification 的 SendStrategy 选择不同的策略发送消息 ( ImmediateSendStrategy 或 DefaultSendStrategy )
+//	        ├── DefaultSendStrategy 默认策略 ( 延迟发送策略 )
+//	        │   └── 创建记录并入库，等待异步发送。
+//	        └── ImmediateSendStrategy 立即发送策略
+//	            ├── 创建记录并入库。
+//	            ├── ImmediateSendStrategy -> NotificationSender -> ChannelSender。
+//	            ├── ChannelSender 选择供应商，此时是真正的消息下发。
+//	            └── 变更状态，返回结果。
+//
+// 业务 id 取自 jwt 中的 biz_id。
 type NotificationServer struct {
+	svc notification.SendService
 }
 
 func (s *NotificationServer) Send(ctx context.Context, request *notificationv1.SendRequest) (*notificationv1.SendResponse, error) {
-	//TODO implement me
-	panic("implement me")
+	if request == nil || request.Notification == nil {
+		return &notificationv1.SendResponse{}, status.Errorf(codes.InvalidArgument, "request or notification is nil")
+	}
+
+	n, err := s.pbToNotificationDomain(ctx, request.Notification)
+	if err != nil {
+		return &notificationv1.SendResponse{}, err
+	}
+
+	resp, err := s.svc.Send(ctx, n)
+	if err != nil {
+		return &notificationv1.SendResponse{}, s.sendErrToStatus(err)
+	}
+
+	return &notificationv1.SendResponse{
+		Result: s.sendResultDomainToPb(resp.Result),
+	}, nil
 }
 
 func (s *NotificationServer) AsyncSend(ctx context.Context, request *notificationv1.AsyncSendRequest) (*notificationv1.AsyncSendResponse, error) {
-	//TODO implement me
-	panic("implement me")
+	if request == nil || request.Notification == nil {
+		return &notificationv1.AsyncSendResponse{}, status.Errorf(codes.InvalidArgument, "request or notification is nil")
+	}
+
+	n, err := s.pbToNotificationDomain(ctx, request.Notification)
+	if err != nil {
+		return &notificationv1.AsyncSendResponse{}, err
+	}
+
+	published, err := s.svc.AsyncSend(ctx, n)
+	if err != nil {
+		return &notificationv1.AsyncSendResponse{}, s.sendErrToStatus(err)
+	}
+
+	return &notificationv1.AsyncSendResponse{
+		Result: &notificationv1.SendResult{
+			NotificationId: published.Id,
+			Status:         s.sendStatusDomainToPb(published.SendStatus),
+		},
+	}, nil
 }
 
 func (s *NotificationServer) BatchSend(ctx context.Context, request *notificationv1.BatchSendRequest) (*notificationv1.BatchSendResponse, error) {
-	//TODO implement me
-	panic("implement me")
+	if request == nil || len(request.Notifications) == 0 {
+		return &notificationv1.BatchSendResponse{}, status.Errorf(codes.InvalidArgument, "request or notifications is empty")
+	}
+
+	ns, err := s.pbToNotificationDomains(ctx, request.Notifications)
+	if err != nil {
+		return &notificationv1.BatchSendResponse{}, err
+	}
+
+	resp, err := s.svc.BatchSend(ctx, ns)
+	if err != nil {
+		return &notificationv1.BatchSendResponse{}, s.sendErrToStatus(err)
+	}
+
+	// 未失败即视为受理成功，延迟发送的消息此时为待发送状态。
+	var successCnt int32
+	for _, result := range resp.Results {
+		if result.SendStatus != domain.SendStatusFailure {
+			successCnt++
+		}
+	}
+	return &notificationv1.BatchSendResponse{
+		Results: slice.Map(resp.Results, func(_ int, result domain.SendResult) *notificationv1.SendResult {
+			return s.sendResultDomainToPb(result)
+		}),
+		TotalCnt:   int32(len(resp.Results)),
+		SuccessCnt: successCnt,
+	}, nil
 }
 
+// AsyncBatchSend 批量异步发送。
+//
+// 注意：
+//
+//	AsyncBatchSendResponse.NotificationIds 仍然是 uint64，无法承载消息 id ( MongoDB 的 ObjectID )，
+//	因此受理成功时只返回空的 NotificationIds，待 kuryr-api 调整后再返回消息 id。
+//	消息 id 由 biz_id + biz_key 确定，客户端可以通过 biz_key 关联回调结果中的消息 id。
 func (s *NotificationServer) AsyncBatchSend(ctx context.Context, request *notificationv1.AsyncBatchSendRequest) (*notificationv1.AsyncBatchSendResponse, error) {
-	//TODO implement me
-	panic("implement me")
+	if request == nil || len(request.Notifications) == 0 {
+		return &notificationv1.AsyncBatchSendResponse{}, status.Errorf(codes.InvalidArgument, "request or notifications is empty")
+	}
+
+	ns, err := s.pbToNotificationDomains(ctx, request.Notifications)
+	if err != nil {
+		return &notificationv1.AsyncBatchSendResponse{}, err
+	}
+
+	if _, err = s.svc.BatchAsyncSend(ctx, ns); err != nil {
+		return &notificationv1.AsyncBatchSendResponse{}, s.sendErrToStatus(err)
+	}
+	return &notificationv1.AsyncBatchSendResponse{}, nil
+}
+
+func (s *NotificationServer) pbToNotificationDomains(ctx context.Context, pbs []*notificationv1.Notification) ([]domain.Notification, error) {
+	ns := make([]domain.Notification, 0, len(pbs))
+	for _, pb := range pbs {
+		if pb == nil {
+			return nil, status.Errorf(codes.InvalidArgument, "notification is nil")
+		}
+		n, err := s.pbToNotificationDomain(ctx, pb)
+		if err != nil {
+			return nil, err
+		}
+		ns = append(ns, n)
+	}
+	return ns, nil
+}
+
+func (s *NotificationServer) pbToNotificationDomain(ctx context.Context, pb *notificationv1.Notification) (domain.Notification, error) {
+	bizId, err := jwt.ContextBizId(ctx)
+	if err != nil {
+		return domain.Notification{}, status.Errorf(codes.Unauthenticated, "missing biz id: %v", err)
+	}
+
+	tplId, err := strconv.ParseUint(pb.TplId, 10, 64)
+	if err != nil {
+		return domain.Notification{}, status.Errorf(codes.InvalidArgument, "invalid tpl_id [ %s ]", pb.TplId)
+	}
+
+	return domain.Notification{
+		BizId:     bizId,
+		BizKey:    pb.BizKey,
+		Receivers: pb.Receivers,
+		Channel:   domain.Channel(pb.Channel),
+		Template: domain.Template{
+			Id:     tplId,
+			Params: pb.TplParams,
+		},
+		StrategyConfig: s.pbToStrategyDomain(pb.Strategy),
+	}, nil
+}
+
+// pbToStrategyDomain 未指定发送策略时立即发送。
+func (s *NotificationServer) pbToStrategyDomain(pb *notificationv1.SendStrategy) domain.SendStrategyConfig {
+	switch st := pb.GetStrategyType().(type) {
+	case *notificationv1.SendStrategy_Delayed:
+		return domain.SendStrategyConfig{
+			StrategyType: domain.SendStrategyDelayed,
+			Delay:        time.Duration(st.Delayed.GetDelaySeconds()) * time.Second,
+		}
+	case *notificationv1.SendStrategy_Scheduled:
+		return domain.SendStrategyConfig{
+			StrategyType: domain.SendStrategyScheduled,
+			ScheduledAt:  st.Scheduled.GetSendTime().AsTime(),
+		}
+	case *notificationv1.SendStrategy_TimeWindow:
+		return domain.SendStrategyConfig{
+			StrategyType: domain.SendStrategyWindow,
+			StartAt:      time.UnixMilli(st.TimeWindow.GetStartTimeMillis()),
+			EndAt:        time.UnixMilli(st.TimeWindow.GetEndTimeMillis()),
+		}
+	case *notificationv1.SendStrategy_Deadline:
+		return domain.SendStrategyConfig{
+			StrategyType: domain.SendStrategyDeadline,
+			Deadline:     st.Deadline.GetDeadline().AsTime(),
+		}
+	default:
+		return domain.SendStrategyConfig{StrategyType: domain.SendStrategyImmediate}
+	}
+}
+
+func (s *NotificationServer) sendResultDomainToPb(result domain.SendResult) *notificationv1.SendResult {
+	return &notificationv1.SendResult{
+		NotificationId: result.NotificationId,
+		Status:         s.sendStatusDomainToPb(result.SendStatus),
+	}
+}
+
+func (s *NotificationServer) sendStatusDomainToPb(sendStatus domain.SendStatus) notificationv1.SendStatus {
+	switch sendStatus {
+	case domain.SendStatusPrepare:
+		return notificationv1.SendStatus_PREPARE
+	case domain.SendStatusPending, domain.SendStatusSending:
+		return notificationv1.SendStatus_PENDING
+	case domain.SendStatusSuccess:
+		return notificationv1.SendStatus_SUCCESS
+	case domain.SendStatusFailure:
+		return notificationv1.SendStatus_FAILURE
+	case domain.SendStatusCancel:
+		return notificationv1.SendStatus_CANCEL
+	default:
+		return notificationv1.SendStatus_STATUS_UNSPECIFIED
+	}
+}
+
+func (s *NotificationServer) sendErrToStatus(err error) error {
+	switch {
+	case errors.Is(err, errs.ErrInvalidParam), errors.Is(err, errs.ErrInvalidChannel):
+		return status.Errorf(codes.InvalidArgument, "failed to send notification: %v", err)
+	case errors.Is(err, errs.ErrRecordNotFound):
+		return status.Errorf(codes.NotFound, "failed to send notification: %v", err)
+	case errors.Is(err, errs.ErrNoActivatedTplVersion), errors.Is(err, errs.ErrNotApprovedTplVersion):
+		return status.Errorf(codes.FailedPrecondition, "failed to send notification: %v", err)
+	case errors.Is(err, errs.ErrQuotaExceeded), errors.Is(err, errs.ErrSystemBusy):
+		return status.Errorf(codes.ResourceExhausted, "failed to send notification: %v", err)
+	default:
+		return status.Errorf(codes.Internal, "failed to send notification: %v", err)
+	}
 }
 
-func NewNotificationServer() *NotificationServer {
-	return &NotificationServer{}
+func NewNotificationServer(svc notification.SendService) *NotificationServer {
+	return &NotificationServer{
+		svc: svc,
+	}
 }
@@ -2,11 +2,14 @@ package api
 
 import (
 	"context"
+	"errors"
 
 	"github.com/JrMarcco/easy-kit/slice"
 	commonv1 "github.com/JrMarcco/kuryr-api/api/go/common/v1"
 	templatev1 "github.com/JrMarcco/kuryr-api/api/go/template/v1"
+	"github.com/JrMarcco/kuryr/internal/api/interceptor/jwt"
 	"github.com/JrMarcco/kuryr/internal/domain"
+	"github.com/JrMarcco/kuryr/internal/errs"
 	pkggorm "github.com/JrMarcco/kuryr/internal/pkg/gorm"
 	"github.com/JrMarcco/kuryr/internal/service/template"
 	"google.golang.org/grpc/codes"
@@ -16,6 +19,12 @@ import (
 
 var _ templatev1.TemplateServiceServer = (*TemplateServer)(nil)
 
+// TemplateServer 模板 grpc 服务。
+//
+// TODO: 以下服务依赖 kuryr-api 新增对应 rpc，当前 kuryr-api v0.0.27 尚未定义，待升级后在此补充 handler：
+//  1. 审核流程 ( SubmitForReview / ApproveVersion / RejectVersion / FindAuditLogByVersionId )。
+//  2. 版本预览与测试发送 ( PreviewVersion / TestSend )。
+//  3. 版本回滚与激活历史 ( RollbackVersion / FindActivationLogByTplId )。
 type TemplateServer struct {
 	svc template.Service
 }
@@ -189,8 +198,26 @@ func (s *TemplateServer) DeleteTemplateVersion(ctx context.Context, request *tem
 }
 
 func (s *TemplateServer) ActivateTemplateVersion(ctx context.Context, request *templatev1.ActivateTemplateVersionRequest) (*templatev1.ActivateTemplateVersionResponse, error) {
-	// TODO: implement me
-	panic("implement me")
+	if request == nil || request.TplId == 0 || request.VersionId == 0 {
+		return &templatev1.ActivateTemplateVersionResponse{}, status.Errorf(codes.InvalidArgument, "request or tpl_id or version_id is nil")
+	}
+
+	operatorId, err := jwt.ContextUid(ctx)
+	if err != nil {
+		return &templatev1.ActivateTemplateVersionResponse{}, status.Errorf(codes.Unauthenticated, "missing operator: %v", err)
+	}
+	err = s.svc.ActivateVersion(ctx, request.TplId, request.VersionId, operatorId)
+	if err != nil {
+		if errors.Is(err, errs.ErrInvalidParam) || errors.Is(err, errs.ErrInvalidStatus) {
+			return &templatev1.ActivateTemplateVersionResponse{}, status.Errorf(codes.FailedPrecondition, "failed to activate template version: %v", err)
+		}
+		if errors.Is(err, errs.ErrRecordNotFound) {
+			return &templatev1.ActivateTemplateVersionResponse{}, status.Errorf(codes.NotFound, "failed to activate template version: %v", err)
+		}
+		return &templatev1.ActivateTemplateVersionResponse{}, status.Errorf(codes.Internal, "failed to activate template version: %v", err)
+	}
+
+	return &templatev1.ActivateTemplateVersionResponse{}, nil
 }
 
 func (s *TemplateServer) ListTemplateVersion(ctx context.Context, request *templatev1.ListTemplateVersionRequest) (*templatev1.ListTemplateVersionResponse, error) {
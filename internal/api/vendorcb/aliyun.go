@@ -0,0 +1,103 @@
+package vendorcb
+
+import (
+	"net/http"
+
+	"github.com/JrMarcco/kuryr/internal/domain"
+)
+
+var _ Vendor = (*AliyunVendor)(nil)
+
+// AliyunVendor 阿里云短信回调 ( HTTP 批量推送模式 )。
+//
+// 阿里云 HTTP 推送不携带签名，只能通过来源 ip 白名单以及回调地址上的 token 参数校验。
+//
+// https://help.aliyun.com/zh/sms/developer-reference/configure-delivery-receipts-1
+type AliyunVendor struct{}
+
+// aliyunTplAuditReport 模板审核结果 ( TemplateSmsReport )。
+type aliyunTplAuditReport struct {
+	TemplateCode   string `json:"template_code"`
+	TemplateName   string `json:"template_name"`
+	TemplateStatus string `json:"template_status"`
+	Reason         string `json:"reason"`
+	OrderId        string `json:"order_id"`
+	CreateDate     string `json:"create_date"`
+	FinishDate     string `json:"finish_date"`
+}
+
+// aliyunReceipt 短信发送状态 ( SmsReport )。
+type aliyunReceipt struct {
+	PhoneNumber string `json:"phone_number"`
+	SendTime    string `json:"send_time"`
+	ReportTime  string `json:"report_time"`
+	Success     bool   `json:"success"`
+	ErrCode     string `json:"err_code"`
+	ErrMsg      string `json:"err_msg"`
+	SmsSize     string `json:"sms_size"`
+	BizId       string `json:"biz_id"`
+	OutId       string `json:"out_id"`
+}
+
+func (v *AliyunVendor) Name() string {
+	return "aliyun"
+}
+
+func (v *AliyunVendor) ParseTplAudit(providerId uint64, body []byte) ([]domain.ProviderTplAuditEvent, error) {
+	var reports []aliyunTplAuditReport
+	if err := unmarshalOneOrMany(body, &reports); err != nil {
+		return nil, err
+	}
+
+	events := make([]domain.ProviderTplAuditEvent, 0, len(reports))
+	for _, report := range reports {
+		events = append(events, domain.ProviderTplAuditEvent{
+			ProviderId:      providerId,
+			ProviderTplId:   report.TemplateCode,
+			AuditStatus:     v.toAuditStatus(report.TemplateStatus),
+			RejectionReason: report.Reason,
+			ReviewAt:        parseVendorTime(report.FinishDate),
+		})
+	}
+	return events, nil
+}
+
+func (v *AliyunVendor) toAuditStatus(status string) domain.AuditStatus {
+	switch status {
+	case "approved":
+		return domain.AuditStatusApproved
+	case "rejected":
+		return domain.AuditStatusRejected
+	default:
+		return domain.AuditStatusAuditing
+	}
+}
+
+func (v *AliyunVendor) ParseReceipt(providerId uint64, body []byte) ([]domain.DeliveryReceiptEvent, error) {
+	var receipts []aliyunReceipt
+	if err := unmarshalOneOrMany(body, &receipts); err != nil {
+		return nil, err
+	}
+
+	events := make([]domain.DeliveryReceiptEvent, 0, len(receipts))
+	for _, receipt := range receipts {
+		events = append(events, domain.DeliveryReceiptEvent{
+			ProviderId: providerId,
+			SerialNo:   receipt.BizId,
+			Receiver:   receipt.PhoneNumber,
+			Success:    receipt.Success,
+			ErrCode:    receipt.ErrCode,
+			ErrMsg:     receipt.ErrMsg,
+			ReceivedAt: parseVendorTime(receipt.ReportTime),
+		})
+	}
+	return events, nil
+}
+
+func (v *AliyunVendor) Ack(w http.ResponseWriter) {
+	writeJson(w, map[string]any{"code": 0, "msg": "成功"})
+}
+
+func NewAliyunVendor() *AliyunVendor {
+	return &AliyunVendor{}
+}
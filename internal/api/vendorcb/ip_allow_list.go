@@ -0,0 +1,73 @@
+package vendorcb
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/JrMarcco/kuryr/internal/errs"
+)
+
+// IpAllowList 来源 ip 白名单，支持单个 ip 和 CIDR。
+// 白名单为空时不做限制。
+//
+// 注意：来源 ip 取自 RemoteAddr，前置代理的场景需要代理层做透传或者在代理层做限制。
+type IpAllowList struct {
+	prefixes []netip.Prefix
+}
+
+func (l *IpAllowList) Allow(r *http.Request) bool {
+	if l == nil || len(l.prefixes) == 0 {
+		return true
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+
+	for _, prefix := range l.prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func NewIpAllowList(items []string) (*IpAllowList, error) {
+	prefixes := make([]netip.Prefix, 0, len(items))
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		if strings.Contains(item, "/") {
+			prefix, err := netip.ParsePrefix(item)
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid cidr [ %s ]", errs.ErrInvalidParam, item)
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+
+		addr, err := netip.ParseAddr(item)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid ip [ %s ]", errs.ErrInvalidParam, item)
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+
+	return &IpAllowList{
+		prefixes: prefixes,
+	}, nil
+}
@@ -0,0 +1,146 @@
+package vendorcb
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/JrMarcco/kuryr/internal/service/vendorevent"
+	"go.uber.org/zap"
+)
+
+const defaultMaxBodySize = 1 << 20
+
+// Endpoint 单个供应商的回调配置。
+type Endpoint struct {
+	Vendor    Vendor
+	Verifier  Verifier
+	AllowList *IpAllowList
+}
+
+// Server 供应商推送回调入口。
+//
+// 路由：
+//
+//	POST /callback/{vendor}/{provider_id}/tpl_audit 模板审核结果
+//	POST /callback/{vendor}/{provider_id}/receipt   送达回执
+type Server struct {
+	svc         vendorevent.Service
+	endpoints   map[string]Endpoint
+	maxBodySize int64
+
+	logger *zap.Logger
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /callback/{vendor}/{provider_id}/tpl_audit", s.handleTplAudit)
+	mux.HandleFunc("POST /callback/{vendor}/{provider_id}/receipt", s.handleReceipt)
+	return mux
+}
+
+func (s *Server) handleTplAudit(w http.ResponseWriter, r *http.Request) {
+	endpoint, providerId, body, ok := s.accept(w, r)
+	if !ok {
+		return
+	}
+
+	events, err := endpoint.Vendor.ParseTplAudit(providerId, body)
+	if err != nil {
+		s.logger.Warn("[kuryr] failed to parse vendor template audit payload", zap.String("vendor", endpoint.Vendor.Name()), zap.Error(err))
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	if err = s.svc.HandleTplAudit(r.Context(), events); err != nil {
+		s.logger.Error("[kuryr] failed to handle vendor template audit event", zap.String("vendor", endpoint.Vendor.Name()), zap.Error(err))
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	endpoint.Vendor.Ack(w)
+}
+
+func (s *Server) handleReceipt(w http.ResponseWriter, r *http.Request) {
+	endpoint, providerId, body, ok := s.accept(w, r)
+	if !ok {
+		return
+	}
+
+	events, err := endpoint.Vendor.ParseReceipt(providerId, body)
+	if err != nil {
+		s.logger.Warn("[kuryr] failed to parse vendor receipt payload", zap.String("vendor", endpoint.Vendor.Name()), zap.Error(err))
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	if err = s.svc.HandleDeliveryReceipt(r.Context(), events); err != nil {
+		s.logger.Error("[kuryr] failed to handle vendor receipt event", zap.String("vendor", endpoint.Vendor.Name()), zap.Error(err))
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	endpoint.Vendor.Ack(w)
+}
+
+// accept 公共校验逻辑：供应商、来源 ip、供应商 id、请求体大小以及签名。
+func (s *Server) accept(w http.ResponseWriter, r *http.Request) (Endpoint, uint64, []byte, bool) {
+	vendorName := r.PathValue("vendor")
+	endpoint, ok := s.endpoints[vendorName]
+	if !ok {
+		http.NotFound(w, r)
+		return Endpoint{}, 0, nil, false
+	}
+
+	if !endpoint.AllowList.Allow(r) {
+		s.logger.Warn("[kuryr] vendor callback rejected by ip allow list", zap.String("vendor", vendorName), zap.String("remote_addr", r.RemoteAddr))
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return Endpoint{}, 0, nil, false
+	}
+
+	providerId, err := strconv.ParseUint(r.PathValue("provider_id"), 10, 64)
+	if err != nil || providerId == 0 {
+		http.Error(w, "invalid provider id", http.StatusBadRequest)
+		return Endpoint{}, 0, nil, false
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, s.maxBodySize))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+			return Endpoint{}, 0, nil, false
+		}
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return Endpoint{}, 0, nil, false
+	}
+
+	if endpoint.Verifier != nil {
+		if err = endpoint.Verifier.Verify(r, body); err != nil {
+			s.logger.Warn("[kuryr] vendor callback verification failed", zap.String("vendor", vendorName), zap.Error(err))
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return Endpoint{}, 0, nil, false
+		}
+	}
+
+	return endpoint, providerId, body, true
+}
+
+func NewServer(svc vendorevent.Service, logger *zap.Logger, maxBodySize int64, endpoints ...Endpoint) *Server {
+	if maxBodySize <= 0 {
+		maxBodySize = defaultMaxBodySize
+	}
+
+	m := make(map[string]Endpoint, len(endpoints))
+	for _, endpoint := range endpoints {
+		m[endpoint.Vendor.Name()] = endpoint
+	}
+
+	return &Server{
+		svc:         svc,
+		endpoints:   m,
+		maxBodySize: maxBodySize,
+		logger:      logger,
+	}
+}
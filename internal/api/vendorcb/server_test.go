@@ -0,0 +1,168 @@
+package vendorcb
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/JrMarcco/kuryr/internal/domain"
+	vendoreventmock "github.com/JrMarcco/kuryr/internal/service/vendorevent/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestServer_TplAudit(t *testing.T) {
+	t.Parallel()
+
+	const secret = "test-secret"
+	const tencentBody = `[{"template_id":1001,"status":-1,"review_reply":"签名不规范"}]`
+	// 阿里云 SmsReport 推送报文。
+	const aliyunBody = `[{"phone_number":"13800000000","send_time":"2025-08-01 11:12:13","report_time":"2025-08-01 11:12:15",` +
+		`"success":false,"err_code":"MK:0001","err_msg":"用户拒收","sms_size":"1","biz_id":"900619746936498440^0","out_id":"n-1"}]`
+
+	tencentAllowList, err := NewIpAllowList([]string{"10.0.0.0/8"})
+	require.NoError(t, err)
+	aliyunAllowList, err := NewIpAllowList([]string{"47.100.0.0/16"})
+	require.NoError(t, err)
+
+	tcs := []struct {
+		name     string
+		path     string
+		body     string
+		reqFunc  func(r *http.Request)
+		mockFunc func(ctrl *gomock.Controller) *vendoreventmock.MockService
+		wantCode int
+	}{
+		{
+			name: "tencent with token",
+			path: "/callback/tencent/1/tpl_audit?token=" + secret,
+			body: tencentBody,
+			reqFunc: func(r *http.Request) {
+				r.RemoteAddr = "10.1.2.3:10000"
+			},
+			mockFunc: func(ctrl *gomock.Controller) *vendoreventmock.MockService {
+				mock := vendoreventmock.NewMockService(ctrl)
+				mock.EXPECT().HandleTplAudit(gomock.Any(), []domain.ProviderTplAuditEvent{
+					{
+						ProviderId:      1,
+						ProviderTplId:   "1001",
+						AuditStatus:     domain.AuditStatusRejected,
+						RejectionReason: "签名不规范",
+					},
+				}).Return(nil)
+				return mock
+			},
+			wantCode: http.StatusOK,
+		}, {
+			name: "tencent with invalid token",
+			path: "/callback/tencent/1/tpl_audit?token=invalid",
+			body: tencentBody,
+			reqFunc: func(r *http.Request) {
+				r.RemoteAddr = "10.1.2.3:10000"
+			},
+			mockFunc: func(ctrl *gomock.Controller) *vendoreventmock.MockService {
+				return vendoreventmock.NewMockService(ctrl)
+			},
+			wantCode: http.StatusUnauthorized,
+		}, {
+			name: "tencent not in allow list",
+			path: "/callback/tencent/1/tpl_audit?token=" + secret,
+			body: tencentBody,
+			reqFunc: func(r *http.Request) {
+				r.RemoteAddr = "192.168.1.1:10000"
+			},
+			mockFunc: func(ctrl *gomock.Controller) *vendoreventmock.MockService {
+				return vendoreventmock.NewMockService(ctrl)
+			},
+			wantCode: http.StatusForbidden,
+		}, {
+			name: "aliyun receipt",
+			path: "/callback/aliyun/2/receipt?token=" + secret,
+			body: aliyunBody,
+			reqFunc: func(r *http.Request) {
+				r.RemoteAddr = "47.100.1.1:10000"
+			},
+			mockFunc: func(ctrl *gomock.Controller) *vendoreventmock.MockService {
+				mock := vendoreventmock.NewMockService(ctrl)
+				mock.EXPECT().HandleDeliveryReceipt(gomock.Any(), []domain.DeliveryReceiptEvent{
+					{
+						ProviderId: 2,
+						SerialNo:   "900619746936498440^0",
+						Receiver:   "13800000000",
+						Success:    false,
+						ErrCode:    "MK:0001",
+						ErrMsg:     "用户拒收",
+						ReceivedAt: parseVendorTime("2025-08-01 11:12:15"),
+					},
+				}).Return(nil)
+				return mock
+			},
+			wantCode: http.StatusOK,
+		}, {
+			name: "aliyun without token",
+			path: "/callback/aliyun/2/receipt",
+			body: aliyunBody,
+			reqFunc: func(r *http.Request) {
+				r.RemoteAddr = "47.100.1.1:10000"
+			},
+			mockFunc: func(ctrl *gomock.Controller) *vendoreventmock.MockService {
+				return vendoreventmock.NewMockService(ctrl)
+			},
+			wantCode: http.StatusUnauthorized,
+		}, {
+			name:    "unknown vendor",
+			path:    "/callback/unknown/1/receipt",
+			reqFunc: func(r *http.Request) {},
+			mockFunc: func(ctrl *gomock.Controller) *vendoreventmock.MockService {
+				return vendoreventmock.NewMockService(ctrl)
+			},
+			wantCode: http.StatusNotFound,
+		}, {
+			name: "invalid provider id",
+			path: "/callback/tencent/abc/receipt?token=" + secret,
+			reqFunc: func(r *http.Request) {
+				r.RemoteAddr = "10.1.2.3:10000"
+			},
+			mockFunc: func(ctrl *gomock.Controller) *vendoreventmock.MockService {
+				return vendoreventmock.NewMockService(ctrl)
+			},
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			server := NewServer(
+				tc.mockFunc(ctrl),
+				zap.NewNop(),
+				0,
+				Endpoint{
+					Vendor:    NewTencentVendor(),
+					Verifier:  NewTokenVerifier(secret),
+					AllowList: tencentAllowList,
+				},
+				Endpoint{
+					Vendor:    NewAliyunVendor(),
+					Verifier:  NewTokenVerifier(secret),
+					AllowList: aliyunAllowList,
+				},
+			)
+
+			req := httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.body))
+			tc.reqFunc(req)
+
+			rec := httptest.NewRecorder()
+			server.Handler().ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.wantCode, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package vendorcb
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/JrMarcco/kuryr/internal/domain"
+)
+
+const vendorTimeLayout = "2006-01-02 15:04:05"
+
+// 供应商推送报文中的时间均为北京时间。
+var vendorLocation = time.FixedZone("CST", 8*60*60)
+
+func parseVendorTime(value string) int64 {
+	t, err := time.ParseInLocation(vendorTimeLayout, value, vendorLocation)
+	if err != nil {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+var _ Vendor = (*TencentVendor)(nil)
+
+// TencentVendor 腾讯云短信回调。
+//
+// https://cloud.tencent.com/document/product/382/52077
+type TencentVendor struct{}
+
+// tencentTplAuditReport 模板审核结果。
+type tencentTplAuditReport struct {
+	TemplateId  uint64 `json:"template_id"`
+	StatusCode  int64  `json:"status"`
+	ReviewReply string `json:"review_reply"`
+	ReviewTime  string `json:"review_time"`
+}
+
+// tencentReceipt 短信下发状态。
+type tencentReceipt struct {
+	UserReceiveTime string `json:"user_receive_time"`
+	NationCode      string `json:"nationcode"`
+	Mobile          string `json:"mobile"`
+	ReportStatus    string `json:"report_status"`
+	ErrMsg          string `json:"errmsg"`
+	Description     string `json:"description"`
+	Sid             string `json:"sid"`
+}
+
+func (v *TencentVendor) Name() string {
+	return "tencent"
+}
+
+func (v *TencentVendor) ParseTplAudit(providerId uint64, body []byte) ([]domain.ProviderTplAuditEvent, error) {
+	var reports []tencentTplAuditReport
+	if err := unmarshalOneOrMany(body, &reports); err != nil {
+		return nil, err
+	}
+
+	events := make([]domain.ProviderTplAuditEvent, 0, len(reports))
+	for _, report := range reports {
+		events = append(events, domain.ProviderTplAuditEvent{
+			ProviderId:      providerId,
+			ProviderTplId:   strconv.FormatUint(report.TemplateId, 10),
+			AuditStatus:     v.toAuditStatus(report.StatusCode),
+			RejectionReason: report.ReviewReply,
+			ReviewAt:        parseVendorTime(report.ReviewTime),
+		})
+	}
+	return events, nil
+}
+
+// toAuditStatus 与查询模板状态接口的状态码保持一致：0 -> 审核通过；-1 -> 审核拒绝；其余 -> 审核中。
+func (v *TencentVendor) toAuditStatus(code int64) domain.AuditStatus {
+	switch code {
+	case 0:
+		return domain.AuditStatusApproved
+	case -1:
+		return domain.AuditStatusRejected
+	default:
+		return domain.AuditStatusAuditing
+	}
+}
+
+func (v *TencentVendor) ParseReceipt(providerId uint64, body []byte) ([]domain.DeliveryReceiptEvent, error) {
+	var receipts []tencentReceipt
+	if err := unmarshalOneOrMany(body, &receipts); err != nil {
+		return nil, err
+	}
+
+	events := make([]domain.DeliveryReceiptEvent, 0, len(receipts))
+	for _, receipt := range receipts {
+		events = append(events, domain.DeliveryReceiptEvent{
+			ProviderId: providerId,
+			SerialNo:   receipt.Sid,
+			Receiver:   receipt.Mobile,
+			Success:    receipt.ReportStatus == "SUCCESS",
+			ErrCode:    receipt.ErrMsg,
+			ErrMsg:     receipt.Description,
+			ReceivedAt: parseVendorTime(receipt.UserReceiveTime),
+		})
+	}
+	return events, nil
+}
+
+func (v *TencentVendor) Ack(w http.ResponseWriter) {
+	writeJson(w, map[string]any{"result": 0, "errmsg": "OK"})
+}
+
+func NewTencentVendor() *TencentVendor {
+	return &TencentVendor{}
+}
+
+// unmarshalOneOrMany 兼容单条和批量推送两种报文格式。
+func unmarshalOneOrMany[T any](body []byte, dst *[]T) error {
+	if err := json.Unmarshal(body, dst); err == nil {
+		return nil
+	}
+
+	var one T
+	if err := json.Unmarshal(body, &one); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidPayload, err)
+	}
+	*dst = []T{one}
+	return nil
+}
+
+func writeJson(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(v)
+}
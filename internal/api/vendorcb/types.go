@@ -0,0 +1,32 @@
+package vendorcb
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/JrMarcco/kuryr/internal/domain"
+)
+
+var (
+	ErrInvalidSignature = errors.New("[kuryr] invalid vendor callback signature")
+	ErrInvalidPayload   = errors.New("[kuryr] invalid vendor callback payload")
+)
+
+// Vendor 供应商回调协议。
+//
+// 负责把供应商推送的报文转换为领域事件，以及按供应商要求的格式返回应答。
+type Vendor interface {
+	// Name 供应商名称，对应回调地址中的 {vendor} 路径参数。
+	Name() string
+	// ParseTplAudit 解析模板审核结果报文。
+	ParseTplAudit(providerId uint64, body []byte) ([]domain.ProviderTplAuditEvent, error)
+	// ParseReceipt 解析送达回执报文。
+	ParseReceipt(providerId uint64, body []byte) ([]domain.DeliveryReceiptEvent, error)
+	// Ack 返回处理成功的应答。
+	Ack(w http.ResponseWriter)
+}
+
+// Verifier 回调请求校验器。
+type Verifier interface {
+	Verify(r *http.Request, body []byte) error
+}
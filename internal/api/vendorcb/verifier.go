@@ -0,0 +1,93 @@
+package vendorcb
+
+import (
+	"crypto/hmac"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/JrMarcco/kuryr/internal/pkg/signature"
+)
+
+const (
+	HeaderToken = "X-Kuryr-Token"
+
+	queryToken = "token"
+)
+
+var _ Verifier = (*TokenVerifier)(nil)
+
+// TokenVerifier 共享密钥校验。
+//
+// 适用于不支持签名的供应商，在控制台配置回调地址时携带 token 参数，
+// 例如：https://host/callback/tencent/1/receipt?token=xxx。
+type TokenVerifier struct {
+	secret []byte
+}
+
+func (v *TokenVerifier) Verify(r *http.Request, _ []byte) error {
+	token := r.Header.Get(HeaderToken)
+	if token == "" {
+		token = r.URL.Query().Get(queryToken)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(token), v.secret) != 1 {
+		return fmt.Errorf("%w: token mismatch", ErrInvalidSignature)
+	}
+	return nil
+}
+
+func NewTokenVerifier(secret string) *TokenVerifier {
+	return &TokenVerifier{
+		secret: []byte(secret),
+	}
+}
+
+var _ Verifier = (*HmacVerifier)(nil)
+
+// HmacVerifier HMAC-SHA256 签名校验。
+//
+// 签名算法见 signature.Sign，签名结果使用 hex 编码。
+// timestamp 为毫秒时间戳，与服务端时间偏差超过 maxSkew 的请求直接拒绝，防止重放。
+type HmacVerifier struct {
+	secret  []byte
+	maxSkew time.Duration
+	nowFunc func() time.Time
+}
+
+func (v *HmacVerifier) Verify(r *http.Request, body []byte) error {
+	tsStr := r.Header.Get(signature.HeaderTimestamp)
+	ts, err := strconv.ParseInt(tsStr, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: invalid timestamp [ %s ]", ErrInvalidSignature, tsStr)
+	}
+
+	skew := v.nowFunc().Sub(time.UnixMilli(ts))
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew > v.maxSkew {
+		return fmt.Errorf("%w: timestamp expired", ErrInvalidSignature)
+	}
+
+	sig, err := hex.DecodeString(r.Header.Get(signature.HeaderSignature))
+	if err != nil {
+		return fmt.Errorf("%w: malformed signature", ErrInvalidSignature)
+	}
+
+	if !hmac.Equal(sig, signature.Sign(v.secret, tsStr, body)) {
+		return fmt.Errorf("%w: signature mismatch", ErrInvalidSignature)
+	}
+	return nil
+}
+
+func NewHmacVerifier(secret string, maxSkew time.Duration) *HmacVerifier {
+	return &HmacVerifier{
+		secret:  []byte(secret),
+		maxSkew: maxSkew,
+		nowFunc: time.Now,
+	}
+}
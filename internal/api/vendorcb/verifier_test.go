@@ -0,0 +1,45 @@
+package vendorcb
+
+import (
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/JrMarcco/kuryr/internal/pkg/signature"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHmacVerifier_Verify(t *testing.T) {
+	t.Parallel()
+
+	const secret = "test-secret"
+	body := []byte(`{"id":1}`)
+
+	tcs := []struct {
+		name    string
+		ts      time.Time
+		body    []byte
+		wantErr error
+	}{
+		{name: "valid", ts: time.Now(), body: body},
+		{name: "expired timestamp", ts: time.Now().Add(-time.Hour), body: body, wantErr: ErrInvalidSignature},
+		{name: "body tampered", ts: time.Now(), body: []byte(`{"id":2}`), wantErr: ErrInvalidSignature},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ts := strconv.FormatInt(tc.ts.UnixMilli(), 10)
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+			r.Header.Set(signature.HeaderTimestamp, ts)
+			r.Header.Set(signature.HeaderSignature, hex.EncodeToString(signature.Sign([]byte(secret), ts, body)))
+
+			err := NewHmacVerifier(secret, 5*time.Minute).Verify(r, tc.body)
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/JrMarcco/kuryr/internal/errs"
 	"github.com/JrMarcco/kuryr/internal/pkg/retry"
@@ -99,6 +100,9 @@ type BizConfig struct {
 	QuotaConfig    *QuotaConfig    `json:"quota_config"`   // 配额配置
 	CallbackConfig *CallbackConfig `json:"callback_config"`
 	RateLimit      int32           `json:"rate_limit"`
+
+	CreatedAt int64 `json:"created_at"`
+	UpdatedAt int64 `json:"updated_at"`
 }
 
 type ChannelItem struct {
@@ -117,12 +121,97 @@ type Quota struct {
 	Email int32 `json:"email"`
 }
 
+// Of 获取渠道对应的配额，小于等于 0 表示不限制。
+func (q *Quota) Of(channel Channel) int32 {
+	if q == nil {
+		return 0
+	}
+	switch channel {
+	case ChannelSms:
+		return q.Sms
+	case ChannelEmail:
+		return q.Email
+	default:
+		return 0
+	}
+}
+
 type QuotaConfig struct {
 	Daily   *Quota `json:"daily"`
 	Monthly *Quota `json:"monthly"`
 }
 
+// CallbackTransport 回调方式。
+type CallbackTransport string
+
+const (
+	CallbackTransportGrpc    CallbackTransport = "grpc"    // 通过注册中心调用业务方 grpc 服务
+	CallbackTransportWebhook CallbackTransport = "webhook" // 通过 http 请求调用业务方 webhook
+)
+
+// IsWebhook 判断是否是 webhook 回调，为空时默认使用 grpc 回调。
+func (t CallbackTransport) IsWebhook() bool {
+	return t == CallbackTransportWebhook
+}
+
+// WebhookConfig webhook 回调配置。
+type WebhookConfig struct {
+	Url     string `json:"url"`
+	Method  string `json:"method"`  // 请求方法，为空时默认 POST
+	Timeout int64  `json:"timeout"` // 请求超时时间，单位：毫秒
+	Secret  string `json:"secret"`  // 请求签名密钥
+}
+
+// DefaultCallbackRetryPolicy 默认回调重试策略：指数退避，间隔 1s 起最长 10min，最多重试 10 次。
+var DefaultCallbackRetryPolicy = retry.Config{
+	Type: retry.StrategyTypeExponentialBackoff,
+	ExponentialBackoff: &retry.ExponentialBackoffConfig{
+		InitialInterval: time.Second,
+		MaxInterval:     10 * time.Minute,
+		MaxRetryTimes:   10,
+	},
+}
+
 type CallbackConfig struct {
-	ServiceName       string        `json:"service_name"`
-	RetryPolicyConfig *retry.Config `json:"retry_policy_config"`
+	Transport         CallbackTransport `json:"transport"`
+	ServiceName       string            `json:"service_name"`
+	Webhook           *WebhookConfig    `json:"webhook"`
+	RetryPolicyConfig *retry.Config     `json:"retry_policy_config"`
+}
+
+func (c *CallbackConfig) Validate() error {
+	switch c.Transport {
+	case "", CallbackTransportGrpc:
+		if c.ServiceName == "" {
+			return fmt.Errorf("%w: callback service name cannot be empty", errs.ErrInvalidParam)
+		}
+	case CallbackTransportWebhook:
+		if c.Webhook == nil || c.Webhook.Url == "" {
+			return fmt.Errorf("%w: webhook url cannot be empty", errs.ErrInvalidParam)
+		}
+		if c.Webhook.Secret == "" {
+			return fmt.Errorf("%w: webhook secret cannot be empty", errs.ErrInvalidParam)
+		}
+	default:
+		return fmt.Errorf("%w: invalid callback transport [ %s ]", errs.ErrInvalidParam, c.Transport)
+	}
+
+	// 未配置重试策略时使用默认策略，见 RetryPolicy。
+	if c.RetryPolicyConfig != nil {
+		if err := c.RetryPolicyConfig.Validate(); err != nil {
+			return fmt.Errorf("%w: invalid callback retry policy: %w", errs.ErrInvalidParam, err)
+		}
+		if c.RetryPolicyConfig.MaxRetryTimes() <= 0 {
+			return fmt.Errorf("%w: callback max retry times must be greater than 0", errs.ErrInvalidParam)
+		}
+	}
+	return nil
+}
+
+// RetryPolicy 回调重试策略配置，业务方未配置时使用 DefaultCallbackRetryPolicy。
+func (c *CallbackConfig) RetryPolicy() retry.Config {
+	if c.RetryPolicyConfig == nil {
+		return DefaultCallbackRetryPolicy
+	}
+	return *c.RetryPolicyConfig
 }
@@ -0,0 +1,22 @@
+package domain
+
+// BizConfigAction 业务方配置变更操作。
+type BizConfigAction string
+
+const (
+	BizConfigActionSave   BizConfigAction = "save"
+	BizConfigActionUpdate BizConfigAction = "update"
+	BizConfigActionDelete BizConfigAction = "delete"
+)
+
+// BizConfigEvent 业务方配置变更事件。
+//
+// 业务方配置保存 / 更新 / 删除后广播给所有实例，各实例据此刷新本地缓存的业务方配置。
+// Version 为配置的更新时间 ( 删除时为删除时间 )，用于丢弃乱序到达的旧事件，
+// BizConfig 为变更后的配置，删除时为空。
+type BizConfigEvent struct {
+	BizId     uint64          `json:"biz_id"`
+	Action    BizConfigAction `json:"action"`
+	Version   int64           `json:"version"`
+	BizConfig *BizConfig      `json:"biz_config,omitempty"`
+}
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/JrMarcco/kuryr/internal/errs"
+	"github.com/JrMarcco/kuryr/internal/pkg/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCallbackConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name    string
+		cfg     CallbackConfig
+		wantErr error
+	}{
+		{
+			name: "default retry policy",
+			cfg:  CallbackConfig{ServiceName: "biz-1"},
+		}, {
+			name: "with retry policy",
+			cfg: CallbackConfig{
+				ServiceName: "biz-1",
+				RetryPolicyConfig: &retry.Config{
+					Type:          retry.StrategyTypeFixedInterval,
+					FixedInterval: &retry.FixedIntervalConfig{Interval: time.Second, MaxRetryTimes: 3},
+				},
+			},
+		}, {
+			name: "missing strategy config",
+			cfg: CallbackConfig{
+				ServiceName:       "biz-1",
+				RetryPolicyConfig: &retry.Config{Type: retry.StrategyTypeExponentialBackoff},
+			},
+			wantErr: errs.ErrInvalidParam,
+		}, {
+			name: "unlimited retry times",
+			cfg: CallbackConfig{
+				ServiceName: "biz-1",
+				RetryPolicyConfig: &retry.Config{
+					Type:          retry.StrategyTypeFixedInterval,
+					FixedInterval: &retry.FixedIntervalConfig{Interval: time.Second},
+				},
+			},
+			wantErr: errs.ErrInvalidParam,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tc.cfg.Validate()
+			assert.ErrorIs(t, err, tc.wantErr)
+			if err == nil {
+				_, err = retry.NewRetryStrategy(tc.cfg.RetryPolicy())
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -22,4 +22,51 @@ type CallbackLog struct {
 	RetriedTimes int32             `json:"retried_times"`
 	NextRetryAt  int64             `json:"next_retry_at"`
 	Status       CallbackLogStatus `json:"status"` // 回调状态
+
+	CreatedAt int64 `json:"created_at"`
+	UpdatedAt int64 `json:"updated_at"`
+}
+
+// CallbackOutbox 回调 outbox 领域对象。
+//
+// 更新消息发送状态时在同一个事务中写入，再由 relay 异步写入回调日志，
+// 保证消息发送状态更新后一定会生成对应的回调日志。
+type CallbackOutbox struct {
+	NotificationId string     `json:"notification_id"`
+	BizId          uint64     `json:"biz_id"`
+	BizKey         string     `json:"biz_key"`
+	SendStatus     SendStatus `json:"send_status"`
+	RelayedTimes   int32      `json:"relayed_times"`
+	NextRelayAt    int64      `json:"next_relay_at"`
+
+	CreatedAt int64 `json:"created_at"`
+	UpdatedAt int64 `json:"updated_at"`
+}
+
+// CallbackReplayLog 回调重放记录领域对象。
+//
+// 按 id 重放时记录 CallbackLogIds，按时间范围重放时记录 StartTime 和 EndTime。
+type CallbackReplayLog struct {
+	Id             uint64   `json:"id"`
+	BizId          uint64   `json:"biz_id"`
+	CallbackLogIds []uint64 `json:"callback_log_ids"` // 指定重放的回调日志 id
+	StartTime      int64    `json:"start_time"`       // 重放时间范围起始，回调日志创建时间
+	EndTime        int64    `json:"end_time"`         // 重放时间范围结束，回调日志创建时间
+	ReplayedCount  int64    `json:"replayed_count"`   // 实际重放的回调日志数量
+	OperatorId     uint64   `json:"operator_id"`      // 操作人 id
+
+	CreatedAt int64 `json:"created_at"`
+}
+
+// CallbackDeadLetterEvent 回调死信事件。
+//
+// 回调重试耗尽后发送，供业务方或者运维人员离线排查。
+type CallbackDeadLetterEvent struct {
+	CallbackLogId      uint64     `json:"callback_log_id"`
+	BizId              uint64     `json:"biz_id"`
+	BizKey             string     `json:"biz_key"`
+	NotificationId     string     `json:"notification_id"`
+	NotificationStatus SendStatus `json:"notification_status"`
+	RetriedTimes       int32      `json:"retried_times"`
+	FailedAt           int64      `json:"failed_at"`
 }
@@ -22,7 +22,7 @@ const (
 // 包含模板 id、版本、参数。
 type Template struct {
 	Id      uint64            `json:"id"`      // 模板 id
-	Version uint64            `json:"version"` // 模板版本
+	Version uint64            `json:"version"` // 模板版本，发送时设置为模板激活版本
 	Params  map[string]string `json:"params"`  // 模板参数
 }
 
@@ -34,6 +34,7 @@ type Notification struct {
 	Receivers      []string           `json:"receivers"`       // 接收者
 	Channel        Channel            `json:"channel"`         // 渠道
 	Template       Template           `json:"template"`        // 模板
+	Locale         string             `json:"locale"`          // 语言区域，用于选择模板变体，为空使用默认变体
 	SendStatus     SendStatus         `json:"send_status"`     // 发送状态
 	ScheduledStrat time.Time          `json:"scheduled_strat"` // 计划发送开始时间
 	ScheduledEnd   time.Time          `json:"scheduled_end"`   // 计划发送结束时间
@@ -61,13 +62,7 @@ func (n *Notification) Validate() error {
 	if n.Template.Id == 0 {
 		return fmt.Errorf("%w: template id cannot be zero", errs.ErrInvalidParam)
 	}
-	if n.Template.Version == 0 {
-		return fmt.Errorf("%w: template version cannot be zero", errs.ErrInvalidParam)
-	}
-
-	if len(n.Template.Params) == 0 {
-		return fmt.Errorf("%w: template params cannot be empty", errs.ErrInvalidParam)
-	}
+	// 模板版本与模板参数在发送时按模板激活版本设置及校验，见 sendstrategy.TplValidateStrategy。
 
 	if err := n.StrategyConfig.Validate(); err != nil {
 		return err
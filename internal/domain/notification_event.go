@@ -0,0 +1,9 @@
+package domain
+
+// AsyncSendEvent 异步发送事件。
+//
+// 异步批量发送时，消息校验并分配 id 后按业务 id 分批写入 kafka，由消费者入库并等待调度发送。
+type AsyncSendEvent struct {
+	BizId         uint64         `json:"biz_id"`
+	Notifications []Notification `json:"notifications"`
+}
@@ -91,6 +91,7 @@ func (c SendStrategyConfig) SendTimeWindow() (startAt, endAt time.Time) {
 type SendResult struct {
 	NotificationId string
 	SendStatus     SendStatus
+	Provider       string // 实际发送消息的供应商名称，未发送到供应商时为空
 }
 
 // SendResp 消息请求响应领域对象
@@ -142,6 +142,10 @@ type ChannelTemplateVersion struct {
 	Signature   string `json:"signature"`    // 签名
 	Content     string `json:"content"`      // 模板内容
 
+	ParamSchema TplParamSchema `json:"param_schema"` // 模板参数定义，保存时从模板内容中解析
+
+	Variants []TplVariant `json:"variants"` // 多语言变体，版本自身的签名与内容作为默认变体
+
 	ApplyRemark string `json:"apply_remark"` // 申请说明
 
 	// 这里的审批相关字段记录系统内的审批状态，不包含供应商侧的审批状态。
@@ -174,7 +178,19 @@ func (tv ChannelTemplateVersion) Validate() error {
 	if tv.ApplyRemark == "" {
 		return fmt.Errorf("%w: apply remark cannot be empty", errs.ErrInvalidParam)
 	}
-	return nil
+	return tv.validateVariants()
+}
+
+// FindApprovedProvider 查找指定供应商在指定变体下、供应商侧审批通过的关联关系。
+//
+// locale 为变体的语言区域 ( 精确匹配 )，调用方需要先通过 ResolveVariant 确定变体。
+func (tv ChannelTemplateVersion) FindApprovedProvider(providerId uint64, locale string) (ChannelTemplateProvider, bool) {
+	for _, provider := range tv.Providers {
+		if provider.ProviderId == providerId && provider.Locale == locale && provider.AuditStatus.IsApproved() {
+			return provider, true
+		}
+	}
+	return ChannelTemplateProvider{}, false
 }
 
 // ChannelTemplateProvider 渠道模板供应商领域对象。
@@ -187,6 +203,7 @@ type ChannelTemplateProvider struct {
 	ProviderName    string  `json:"provider_name"`    // 供应商名称
 	ProviderTplId   string  `json:"provider_tpl_id"`  // 供应商侧模板 id
 	ProviderChannel Channel `json:"provider_channel"` // 供应商渠道类型
+	Locale          string  `json:"locale"`           // 关联变体的语言区域，默认变体为空
 
 	// 这里的审批字段记录供应商侧的审批状态。
 	AuditRequestId  string      `json:"audit_request_id"` // 审批请求 id
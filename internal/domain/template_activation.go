@@ -0,0 +1,39 @@
+package domain
+
+// ChannelTemplateActivationLog 渠道模板版本激活记录领域对象。
+type ChannelTemplateActivationLog struct {
+	Id            uint64           `json:"id"`
+	TplId         uint64           `json:"tpl_id"`          // 模板 id
+	FromVersionId uint64           `json:"from_version_id"` // 操作前激活版本 id，为 0 表示之前没有激活版本
+	ToVersionId   uint64           `json:"to_version_id"`   // 操作后激活版本 id
+	Action        ActivationAction `json:"action"`          // 激活操作
+	OperatorId    uint64           `json:"operator_id"`     // 操作人 id
+
+	CreatedAt int64 `json:"created_at"`
+}
+
+// RollbackCandidates 根据激活记录 ( 按时间倒序 ) 计算可回滚的版本 id，越靠前优先级越高。
+//
+// 被回滚掉的版本不会再作为候选，
+// 因此连续回滚会沿着激活历史一直向前，而不是在两个版本之间来回切换。
+func RollbackCandidates(currentVersionId uint64, logs []ChannelTemplateActivationLog) []uint64 {
+	skipped := map[uint64]struct{}{currentVersionId: {}}
+
+	candidates := make([]uint64, 0, len(logs))
+	for _, log := range logs {
+		if log.Action == ActivationActionRollback {
+			skipped[log.FromVersionId] = struct{}{}
+			continue
+		}
+
+		if log.ToVersionId == 0 {
+			continue
+		}
+		if _, ok := skipped[log.ToVersionId]; ok {
+			continue
+		}
+		skipped[log.ToVersionId] = struct{}{}
+		candidates = append(candidates, log.ToVersionId)
+	}
+	return candidates
+}
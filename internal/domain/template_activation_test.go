@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRollbackCandidates(t *testing.T) {
+	t.Parallel()
+
+	activate := func(from, to uint64) ChannelTemplateActivationLog {
+		return ChannelTemplateActivationLog{FromVersionId: from, ToVersionId: to, Action: ActivationActionActivate}
+	}
+	rollback := func(from, to uint64) ChannelTemplateActivationLog {
+		return ChannelTemplateActivationLog{FromVersionId: from, ToVersionId: to, Action: ActivationActionRollback}
+	}
+
+	tcs := []struct {
+		name    string
+		current uint64
+		logs    []ChannelTemplateActivationLog
+		want    []uint64
+	}{
+		{
+			name:    "no history",
+			current: 1,
+			logs:    []ChannelTemplateActivationLog{activate(0, 1)},
+			want:    []uint64{},
+		}, {
+			name:    "activate in order",
+			current: 3,
+			logs:    []ChannelTemplateActivationLog{activate(2, 3), activate(1, 2), activate(0, 1)},
+			want:    []uint64{2, 1},
+		}, {
+			name:    "rollback twice",
+			current: 2,
+			logs:    []ChannelTemplateActivationLog{rollback(3, 2), activate(2, 3), activate(1, 2), activate(0, 1)},
+			want:    []uint64{1},
+		}, {
+			name:    "reactivate same version",
+			current: 1,
+			logs:    []ChannelTemplateActivationLog{activate(2, 1), activate(1, 2), activate(0, 1)},
+			want:    []uint64{2},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.want, RollbackCandidates(tc.current, tc.logs))
+		})
+	}
+}
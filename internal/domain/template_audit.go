@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/JrMarcco/kuryr/internal/errs"
+)
+
+// ChannelTemplateAuditLog 渠道模板版本审批记录领域对象。
+type ChannelTemplateAuditLog struct {
+	Id           uint64 `json:"id"`
+	TplId        uint64 `json:"tpl_id"`         // 模板 id
+	TplVersionId uint64 `json:"tpl_version_id"` // 模板版本 id
+
+	Action     AuditAction `json:"action"`      // 审批操作
+	FromStatus AuditStatus `json:"from_status"` // 操作前状态
+	ToStatus   AuditStatus `json:"to_status"`   // 操作后状态
+	OperatorId uint64      `json:"operator_id"` // 操作人 id
+	Remark     string      `json:"remark"`      // 备注 ( 拒绝时为拒绝原因 )
+
+	CreatedAt int64 `json:"created_at"`
+}
+
+// SubmitForReview 提交审批。
+//
+// 允许的状态流转：
+//  1. pending -> auditing
+//  2. rejected -> auditing，需要距离上次提交审批超过 cooldown
+func (tv *ChannelTemplateVersion) SubmitForReview(now time.Time, cooldown time.Duration) error {
+	switch tv.AuditStatus {
+	case AuditStatusPending:
+	case AuditStatusRejected:
+		next := time.UnixMilli(tv.LastReviewAt).Add(cooldown)
+		if now.Before(next) {
+			return fmt.Errorf(
+				"%w: resubmission is not allowed until %s", errs.ErrInvalidStatus, next.Format(time.DateTime),
+			)
+		}
+	default:
+		return fmt.Errorf("%w: cannot submit version in status [ %s ]", errs.ErrInvalidStatus, tv.AuditStatus)
+	}
+
+	tv.AuditStatus = AuditStatusAuditing
+	tv.AuditorId = 0
+	tv.AuditTime = 0
+	tv.RejectionReason = ""
+	tv.LastReviewAt = now.UnixMilli()
+	return nil
+}
+
+// Approve 审批通过，只允许 auditing -> approved。
+func (tv *ChannelTemplateVersion) Approve(auditorId uint64, now time.Time) error {
+	if !tv.AuditStatus.IsAuditing() {
+		return fmt.Errorf("%w: cannot approve version in status [ %s ]", errs.ErrInvalidStatus, tv.AuditStatus)
+	}
+
+	tv.AuditStatus = AuditStatusApproved
+	tv.AuditorId = auditorId
+	tv.AuditTime = now.UnixMilli()
+	tv.RejectionReason = ""
+	return nil
+}
+
+// Reject 审批拒绝，只允许 auditing -> rejected。
+func (tv *ChannelTemplateVersion) Reject(auditorId uint64, reason string, now time.Time) error {
+	if reason == "" {
+		return fmt.Errorf("%w: rejection reason cannot be empty", errs.ErrInvalidParam)
+	}
+	if !tv.AuditStatus.IsAuditing() {
+		return fmt.Errorf("%w: cannot reject version in status [ %s ]", errs.ErrInvalidStatus, tv.AuditStatus)
+	}
+
+	tv.AuditStatus = AuditStatusRejected
+	tv.AuditorId = auditorId
+	tv.AuditTime = now.UnixMilli()
+	tv.RejectionReason = reason
+	return nil
+}
@@ -0,0 +1,135 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/JrMarcco/kuryr/internal/errs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChannelTemplateVersion_SubmitForReview(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	cooldown := 10 * time.Minute
+
+	tcs := []struct {
+		name       string
+		version    ChannelTemplateVersion
+		wantStatus AuditStatus
+		wantErr    error
+	}{
+		{
+			name:       "pending",
+			version:    ChannelTemplateVersion{AuditStatus: AuditStatusPending},
+			wantStatus: AuditStatusAuditing,
+		}, {
+			name: "rejected after cooldown",
+			version: ChannelTemplateVersion{
+				AuditStatus:  AuditStatusRejected,
+				LastReviewAt: now.Add(-time.Hour).UnixMilli(),
+			},
+			wantStatus: AuditStatusAuditing,
+		}, {
+			name: "rejected within cooldown",
+			version: ChannelTemplateVersion{
+				AuditStatus:  AuditStatusRejected,
+				LastReviewAt: now.Add(-time.Minute).UnixMilli(),
+			},
+			wantStatus: AuditStatusRejected,
+			wantErr:    errs.ErrInvalidStatus,
+		}, {
+			name:       "auditing",
+			version:    ChannelTemplateVersion{AuditStatus: AuditStatusAuditing},
+			wantStatus: AuditStatusAuditing,
+			wantErr:    errs.ErrInvalidStatus,
+		}, {
+			name:       "approved",
+			version:    ChannelTemplateVersion{AuditStatus: AuditStatusApproved},
+			wantStatus: AuditStatusApproved,
+			wantErr:    errs.ErrInvalidStatus,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tc.version.SubmitForReview(now, cooldown)
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.wantStatus, tc.version.AuditStatus)
+
+			if err == nil {
+				assert.Equal(t, now.UnixMilli(), tc.version.LastReviewAt)
+			}
+		})
+	}
+}
+
+func TestChannelTemplateVersion_ApproveAndReject(t *testing.T) {
+	t.Parallel()
+
+	const auditorId = uint64(1)
+
+	tcs := []struct {
+		name       string
+		status     AuditStatus
+		approve    bool
+		reason     string
+		wantStatus AuditStatus
+		wantErr    error
+	}{
+		{
+			name:       "approve auditing",
+			status:     AuditStatusAuditing,
+			approve:    true,
+			wantStatus: AuditStatusApproved,
+		}, {
+			name:       "approve pending",
+			status:     AuditStatusPending,
+			approve:    true,
+			wantStatus: AuditStatusPending,
+			wantErr:    errs.ErrInvalidStatus,
+		}, {
+			name:       "reject auditing",
+			status:     AuditStatusAuditing,
+			reason:     "invalid content",
+			wantStatus: AuditStatusRejected,
+		}, {
+			name:       "reject without reason",
+			status:     AuditStatusAuditing,
+			wantStatus: AuditStatusAuditing,
+			wantErr:    errs.ErrInvalidParam,
+		}, {
+			name:       "reject approved",
+			status:     AuditStatusApproved,
+			reason:     "invalid content",
+			wantStatus: AuditStatusApproved,
+			wantErr:    errs.ErrInvalidStatus,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			version := ChannelTemplateVersion{AuditStatus: tc.status}
+
+			var err error
+			if tc.approve {
+				err = version.Approve(auditorId, time.Now())
+			} else {
+				err = version.Reject(auditorId, tc.reason, time.Now())
+			}
+
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.wantStatus, version.AuditStatus)
+
+			if err == nil {
+				assert.Equal(t, auditorId, version.AuditorId)
+				assert.Equal(t, tc.reason, version.RejectionReason)
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package domain
+
+// TplProviderBindEvent 模板版本关联供应商事件。
+//
+// 版本关联供应商后发送，由同步任务把模板提交到供应商侧审核。
+type TplProviderBindEvent struct {
+	TplId        uint64   `json:"tpl_id"`         // 模板 id
+	TplVersionId uint64   `json:"tpl_version_id"` // 模板版本 id
+	ProviderIds  []uint64 `json:"provider_ids"`   // 供应商 id，为空表示该版本下全部待审核的供应商
+}
+
+// ChannelTplEvent 渠道模板变更事件。
+//
+// 模板 ( 版本、激活版本、供应商关联 ) 变更后广播给所有实例，各实例据此淘汰本地缓存的模板。
+type ChannelTplEvent struct {
+	TplIds []uint64 `json:"tpl_ids"` // 变更的模板 id
+}
@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/JrMarcco/kuryr/internal/errs"
+)
+
+// LocaleDefault 默认变体的语言区域，即版本自身的签名与内容。
+const LocaleDefault = ""
+
+// TplVariant 模板版本的多语言变体。
+//
+// 同一个版本下的不同语言共享审批、激活流程，
+// 每个变体需要单独关联供应商 ( 供应商侧按变体分别创建模板 )。
+type TplVariant struct {
+	Locale      string         `json:"locale"`       // 语言区域，例如：en、zh-hk，默认变体为空
+	Signature   string         `json:"signature"`    // 签名
+	Content     string         `json:"content"`      // 模板内容
+	ParamSchema TplParamSchema `json:"param_schema"` // 模板参数定义，保存时从模板内容中解析
+}
+
+func (v TplVariant) Validate() error {
+	if v.Locale == LocaleDefault {
+		return fmt.Errorf("%w: variant locale cannot be empty", errs.ErrInvalidParam)
+	}
+	if v.Signature == "" {
+		return fmt.Errorf("%w: signature of variant [ %s ] cannot be empty", errs.ErrInvalidParam, v.Locale)
+	}
+	if v.Content == "" {
+		return fmt.Errorf("%w: content of variant [ %s ] cannot be empty", errs.ErrInvalidParam, v.Locale)
+	}
+	return nil
+}
+
+// NormalizeLocale 统一语言区域格式：小写，使用 "-" 分隔。
+//
+// 例如：zh_HK -> zh-hk。
+func NormalizeLocale(locale string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(locale)), "_", "-")
+}
+
+// LocaleFallbacks 按优先级返回语言区域的回退链，最后一项固定为默认变体。
+//
+// 例如：zh-HK -> [ zh-hk, zh, "" ]。
+func LocaleFallbacks(locale string) []string {
+	locale = NormalizeLocale(locale)
+
+	fallbacks := make([]string, 0, strings.Count(locale, "-")+2)
+	for locale != LocaleDefault {
+		fallbacks = append(fallbacks, locale)
+
+		idx := strings.LastIndex(locale, "-")
+		if idx < 0 {
+			break
+		}
+		locale = locale[:idx]
+	}
+	return append(fallbacks, LocaleDefault)
+}
+
+// DefaultVariant 默认变体。
+func (tv ChannelTemplateVersion) DefaultVariant() TplVariant {
+	return TplVariant{
+		Locale:      LocaleDefault,
+		Signature:   tv.Signature,
+		Content:     tv.Content,
+		ParamSchema: tv.ParamSchema,
+	}
+}
+
+// Variant 精确查找语言区域对应的变体。
+func (tv ChannelTemplateVersion) Variant(locale string) (TplVariant, bool) {
+	locale = NormalizeLocale(locale)
+	if locale == LocaleDefault {
+		return tv.DefaultVariant(), true
+	}
+
+	for _, variant := range tv.Variants {
+		if variant.Locale == locale {
+			return variant, true
+		}
+	}
+	return TplVariant{}, false
+}
+
+// ResolveVariant 按回退链查找变体，找不到时使用默认变体。
+func (tv ChannelTemplateVersion) ResolveVariant(locale string) TplVariant {
+	for _, l := range LocaleFallbacks(locale) {
+		if variant, ok := tv.Variant(l); ok {
+			return variant
+		}
+	}
+	return tv.DefaultVariant()
+}
+
+// validateVariants 校验变体，语言区域不能重复。
+func (tv ChannelTemplateVersion) validateVariants() error {
+	locales := make(map[string]struct{}, len(tv.Variants))
+	for _, variant := range tv.Variants {
+		if err := variant.Validate(); err != nil {
+			return err
+		}
+
+		if _, ok := locales[variant.Locale]; ok {
+			return fmt.Errorf("%w: duplicate variant locale [ %s ]", errs.ErrInvalidParam, variant.Locale)
+		}
+		locales[variant.Locale] = struct{}{}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocaleFallbacks(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name   string
+		locale string
+		want   []string
+	}{
+		{name: "default", locale: "", want: []string{""}},
+		{name: "language", locale: "en", want: []string{"en", ""}},
+		{name: "region", locale: "zh_HK", want: []string{"zh-hk", "zh", ""}},
+		{name: "script and region", locale: "zh-Hant-TW", want: []string{"zh-hant-tw", "zh-hant", "zh", ""}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.want, LocaleFallbacks(tc.locale))
+		})
+	}
+}
+
+func TestChannelTemplateVersion_ResolveVariant(t *testing.T) {
+	t.Parallel()
+
+	version := ChannelTemplateVersion{
+		Signature: "kuryr",
+		Content:   "默认",
+		Variants: []TplVariant{
+			{Locale: "zh", Signature: "kuryr", Content: "中文"},
+			{Locale: "zh-tw", Signature: "kuryr", Content: "繁體"},
+			{Locale: "en", Signature: "kuryr", Content: "english"},
+		},
+	}
+
+	tcs := []struct {
+		name       string
+		locale     string
+		wantLocale string
+	}{
+		{name: "exact", locale: "zh-TW", wantLocale: "zh-tw"},
+		{name: "fallback to language", locale: "zh-HK", wantLocale: "zh"},
+		{name: "fallback to default", locale: "ja-JP", wantLocale: ""},
+		{name: "default", locale: "", wantLocale: ""},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.wantLocale, version.ResolveVariant(tc.locale).Locale)
+		})
+	}
+}
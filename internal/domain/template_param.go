@@ -0,0 +1,205 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/JrMarcco/kuryr/internal/errs"
+)
+
+// TplParamStyle 模板占位符风格
+type TplParamStyle string
+
+const (
+	TplParamStyleNone       TplParamStyle = ""           // 无占位符
+	TplParamStyleNamed      TplParamStyle = "named"      // 命名占位符，例如：${code}
+	TplParamStylePositional TplParamStyle = "positional" // 位置占位符，例如：{1} ( 腾讯云 )
+)
+
+// TplParamType 模板参数类型
+type TplParamType string
+
+const (
+	TplParamTypeString TplParamType = "string" // 任意字符串
+	TplParamTypeNumber TplParamType = "number" // 纯数字，例如：验证码
+)
+
+func (t TplParamType) IsValid() bool {
+	switch t {
+	case "", TplParamTypeString, TplParamTypeNumber:
+		return true
+	default:
+		return false
+	}
+}
+
+var (
+	namedPlaceholderRegexp      = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)}`)
+	positionalPlaceholderRegexp = regexp.MustCompile(`\{([0-9]+)}`)
+)
+
+// TplParam 模板参数定义。
+type TplParam struct {
+	Name   string       `json:"name"`    // 参数名，位置占位符时为位置序号 ( "1"、"2" ... )
+	Type   TplParamType `json:"type"`    // 参数类型，为空不校验
+	MinLen int          `json:"min_len"` // 最小长度 ( 字符数 )，为 0 不校验
+	MaxLen int          `json:"max_len"` // 最大长度 ( 字符数 )，为 0 不校验
+}
+
+func (p TplParam) validate(val string) error {
+	length := utf8.RuneCountInString(val)
+	if p.MinLen > 0 && length < p.MinLen {
+		return fmt.Errorf("%w: template param [ %s ] is shorter than %d", errs.ErrInvalidParam, p.Name, p.MinLen)
+	}
+	if p.MaxLen > 0 && length > p.MaxLen {
+		return fmt.Errorf("%w: template param [ %s ] is longer than %d", errs.ErrInvalidParam, p.Name, p.MaxLen)
+	}
+
+	if p.Type == TplParamTypeNumber {
+		if val == "" {
+			return fmt.Errorf("%w: template param [ %s ] should be number", errs.ErrInvalidParam, p.Name)
+		}
+		for _, r := range val {
+			if !unicode.IsDigit(r) {
+				return fmt.Errorf("%w: template param [ %s ] should be number", errs.ErrInvalidParam, p.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// TplParamSchema 模板参数定义集合，保存版本时从模板内容中解析。
+type TplParamSchema struct {
+	Style  TplParamStyle `json:"style"`
+	Params []TplParam    `json:"params"` // 按占位符首次出现顺序 ( 位置占位符按序号 ) 排列
+}
+
+// ParseTplParamSchema 从模板内容中解析占位符。
+//
+// 同一个模板只允许使用一种占位符风格，位置占位符必须从 1 开始连续编号。
+func ParseTplParamSchema(content string) (TplParamSchema, error) {
+	named := namedPlaceholderRegexp.FindAllStringSubmatch(content, -1)
+	// 去掉命名占位符后再匹配位置占位符，避免 ${1abc} 之类的内容误判。
+	positional := positionalPlaceholderRegexp.FindAllStringSubmatch(
+		namedPlaceholderRegexp.ReplaceAllString(content, ""), -1,
+	)
+
+	switch {
+	case len(named) > 0 && len(positional) > 0:
+		return TplParamSchema{}, fmt.Errorf("%w: template content cannot mix named and positional placeholders", errs.ErrInvalidParam)
+	case len(named) > 0:
+		schema := TplParamSchema{Style: TplParamStyleNamed}
+		for _, match := range named {
+			name := match[1]
+			if slices.ContainsFunc(schema.Params, func(p TplParam) bool { return p.Name == name }) {
+				continue
+			}
+			schema.Params = append(schema.Params, TplParam{Name: name})
+		}
+		return schema, nil
+	case len(positional) > 0:
+		positions := make([]int, 0, len(positional))
+		for _, match := range positional {
+			pos, err := strconv.Atoi(match[1])
+			if err != nil {
+				return TplParamSchema{}, fmt.Errorf("%w: invalid positional placeholder {%s}", errs.ErrInvalidParam, match[1])
+			}
+			if !slices.Contains(positions, pos) {
+				positions = append(positions, pos)
+			}
+		}
+		slices.Sort(positions)
+
+		schema := TplParamSchema{Style: TplParamStylePositional}
+		for i, pos := range positions {
+			if pos != i+1 {
+				return TplParamSchema{}, fmt.Errorf("%w: positional placeholders must start from {1} and be continuous", errs.ErrInvalidParam)
+			}
+			schema.Params = append(schema.Params, TplParam{Name: strconv.Itoa(pos)})
+		}
+		return schema, nil
+	default:
+		return TplParamSchema{Style: TplParamStyleNone}, nil
+	}
+}
+
+// WithConstraints 合并参数约束 ( 类型、长度 )，约束只能声明模板中存在的参数。
+func (s TplParamSchema) WithConstraints(constraints []TplParam) (TplParamSchema, error) {
+	params := slices.Clone(s.Params)
+	for _, c := range constraints {
+		if !c.Type.IsValid() {
+			return TplParamSchema{}, fmt.Errorf("%w: invalid template param type [ %s ]", errs.ErrInvalidParam, c.Type)
+		}
+		if c.MinLen < 0 || c.MaxLen < 0 || (c.MaxLen > 0 && c.MinLen > c.MaxLen) {
+			return TplParamSchema{}, fmt.Errorf("%w: invalid length constraint of template param [ %s ]", errs.ErrInvalidParam, c.Name)
+		}
+
+		idx := slices.IndexFunc(params, func(p TplParam) bool { return p.Name == c.Name })
+		if idx < 0 {
+			return TplParamSchema{}, fmt.Errorf("%w: template param [ %s ] not found in content", errs.ErrInvalidParam, c.Name)
+		}
+		params[idx] = c
+	}
+
+	return TplParamSchema{
+		Style:  s.Style,
+		Params: params,
+	}, nil
+}
+
+// ParamNames 按参数位置返回参数名。
+func (s TplParamSchema) ParamNames() []string {
+	names := make([]string, 0, len(s.Params))
+	for _, p := range s.Params {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+// Validate 校验发送参数，参数缺失、多余以及不满足约束都视为非法。
+func (s TplParamSchema) Validate(params map[string]string) error {
+	for _, p := range s.Params {
+		val, ok := params[p.Name]
+		if !ok {
+			return fmt.Errorf("%w: missing template param [ %s ]", errs.ErrInvalidParam, p.Name)
+		}
+		if err := p.validate(val); err != nil {
+			return err
+		}
+	}
+
+	if len(params) > len(s.Params) {
+		for name := range params {
+			if !slices.ContainsFunc(s.Params, func(p TplParam) bool { return p.Name == name }) {
+				return fmt.Errorf("%w: unknown template param [ %s ]", errs.ErrInvalidParam, name)
+			}
+		}
+	}
+	return nil
+}
+
+// Render 使用参数渲染模板内容，渲染前先校验参数。
+func (s TplParamSchema) Render(content string, params map[string]string) (string, error) {
+	if err := s.Validate(params); err != nil {
+		return "", err
+	}
+
+	replace := func(re *regexp.Regexp) string {
+		return re.ReplaceAllStringFunc(content, func(placeholder string) string {
+			return params[re.FindStringSubmatch(placeholder)[1]]
+		})
+	}
+
+	switch s.Style {
+	case TplParamStyleNamed:
+		return replace(namedPlaceholderRegexp), nil
+	case TplParamStylePositional:
+		return replace(positionalPlaceholderRegexp), nil
+	default:
+		return content, nil
+	}
+}
@@ -0,0 +1,205 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/JrMarcco/kuryr/internal/errs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTplParamSchema(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name       string
+		content    string
+		wantSchema TplParamSchema
+		wantErr    error
+	}{
+		{
+			name:       "no placeholder",
+			content:    "hello world",
+			wantSchema: TplParamSchema{Style: TplParamStyleNone},
+		}, {
+			name:    "named",
+			content: "your code is ${code}, expire in ${minute} minutes, code: ${code}",
+			wantSchema: TplParamSchema{
+				Style:  TplParamStyleNamed,
+				Params: []TplParam{{Name: "code"}, {Name: "minute"}},
+			},
+		}, {
+			name:    "positional",
+			content: "your code is {1}, expire in {2} minutes, code: {1}",
+			wantSchema: TplParamSchema{
+				Style:  TplParamStylePositional,
+				Params: []TplParam{{Name: "1"}, {Name: "2"}},
+			},
+		}, {
+			name:    "positional out of order",
+			content: "expire in {2} minutes, your code is {1}",
+			wantSchema: TplParamSchema{
+				Style:  TplParamStylePositional,
+				Params: []TplParam{{Name: "1"}, {Name: "2"}},
+			},
+		}, {
+			name:    "positional not continuous",
+			content: "your code is {1}, expire in {3} minutes",
+			wantErr: errs.ErrInvalidParam,
+		}, {
+			name:    "mixed",
+			content: "your code is ${code}, expire in {1} minutes",
+			wantErr: errs.ErrInvalidParam,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			schema, err := ParseTplParamSchema(tc.content)
+			assert.ErrorIs(t, err, tc.wantErr)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantSchema, schema)
+		})
+	}
+}
+
+func TestTplParamSchema_Validate(t *testing.T) {
+	t.Parallel()
+
+	schema := TplParamSchema{
+		Style: TplParamStyleNamed,
+		Params: []TplParam{
+			{Name: "code", Type: TplParamTypeNumber, MinLen: 4, MaxLen: 6},
+			{Name: "name", MaxLen: 4},
+		},
+	}
+
+	tcs := []struct {
+		name    string
+		params  map[string]string
+		wantErr error
+	}{
+		{
+			name:   "valid",
+			params: map[string]string{"code": "1234", "name": "张三"},
+		}, {
+			name:    "missing param",
+			params:  map[string]string{"code": "1234"},
+			wantErr: errs.ErrInvalidParam,
+		}, {
+			name:    "unknown param",
+			params:  map[string]string{"code": "1234", "name": "张三", "extra": "x"},
+			wantErr: errs.ErrInvalidParam,
+		}, {
+			name:    "not number",
+			params:  map[string]string{"code": "12ab", "name": "张三"},
+			wantErr: errs.ErrInvalidParam,
+		}, {
+			name:    "too short",
+			params:  map[string]string{"code": "123", "name": "张三"},
+			wantErr: errs.ErrInvalidParam,
+		}, {
+			name:    "too long",
+			params:  map[string]string{"code": "1234", "name": "张三李四王五"},
+			wantErr: errs.ErrInvalidParam,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := schema.Validate(tc.params)
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestChannelTemplateVersion_Preview(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name        string
+		channel     Channel
+		version     ChannelTemplateVersion
+		params      map[string]string
+		wantPreview TplPreview
+		wantErr     error
+	}{
+		{
+			name:    "sms positional",
+			channel: ChannelSms,
+			version: ChannelTemplateVersion{
+				Signature: "kuryr",
+				Content:   "验证码 {1}，{2} 分钟内有效",
+				ParamSchema: TplParamSchema{
+					Style:  TplParamStylePositional,
+					Params: []TplParam{{Name: "1"}, {Name: "2"}},
+				},
+			},
+			params: map[string]string{"1": "1234", "2": "5"},
+			wantPreview: TplPreview{
+				Content:  "【kuryr】验证码 1234，5 分钟内有效",
+				Length:   23,
+				Segments: 1,
+			},
+		}, {
+			name:    "sms long",
+			channel: ChannelSms,
+			version: ChannelTemplateVersion{
+				Signature:   "kuryr",
+				Content:     strings.Repeat("长", 70),
+				ParamSchema: TplParamSchema{Style: TplParamStyleNone},
+			},
+			wantPreview: TplPreview{
+				Content:  "【kuryr】" + strings.Repeat("长", 70),
+				Length:   77,
+				Segments: 2,
+			},
+		}, {
+			name:    "email named",
+			channel: ChannelEmail,
+			version: ChannelTemplateVersion{
+				Signature: "kuryr",
+				Content:   "hello ${name}",
+				ParamSchema: TplParamSchema{
+					Style:  TplParamStyleNamed,
+					Params: []TplParam{{Name: "name"}},
+				},
+			},
+			params: map[string]string{"name": "kuryr"},
+			wantPreview: TplPreview{
+				Content: "hello kuryr",
+				Length:  11,
+			},
+		}, {
+			name:    "invalid params",
+			channel: ChannelSms,
+			version: ChannelTemplateVersion{
+				Content: "验证码 {1}",
+				ParamSchema: TplParamSchema{
+					Style:  TplParamStylePositional,
+					Params: []TplParam{{Name: "1"}},
+				},
+			},
+			wantErr: errs.ErrInvalidParam,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			preview, err := tc.version.Preview(tc.channel, "", tc.params)
+			assert.ErrorIs(t, err, tc.wantErr)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantPreview, preview)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package domain
+
+import "unicode/utf8"
+
+const (
+	SmsSingleSegmentLen = 70 // 单条短信最大字符数
+	SmsMultiSegmentLen  = 67 // 长短信拆分后每条的最大字符数
+)
+
+// TplPreview 模板预览结果。
+type TplPreview struct {
+	Content  string `json:"content"`  // 渲染后的完整内容，短信包含签名
+	Length   int    `json:"length"`   // 字符数
+	Segments int    `json:"segments"` // 短信计费条数，非短信渠道为 0
+}
+
+// Preview 使用参数渲染语言区域对应的变体内容，变体按 ResolveVariant 规则回退。
+func (tv ChannelTemplateVersion) Preview(channel Channel, locale string, params map[string]string) (TplPreview, error) {
+	return tv.ResolveVariant(locale).Preview(channel, params)
+}
+
+// Preview 使用参数渲染变体内容。
+//
+// 短信渠道会在内容前拼接签名 ( 【签名】 )，并按照供应商计费规则计算条数：
+// 不超过 70 字为 1 条，超过 70 字按每条 67 字拆分。
+func (v TplVariant) Preview(channel Channel, params map[string]string) (TplPreview, error) {
+	content, err := v.ParamSchema.Render(v.Content, params)
+	if err != nil {
+		return TplPreview{}, err
+	}
+
+	if !channel.IsSms() {
+		return TplPreview{
+			Content: content,
+			Length:  utf8.RuneCountInString(content),
+		}, nil
+	}
+
+	if v.Signature != "" {
+		content = "【" + v.Signature + "】" + content
+	}
+	length := utf8.RuneCountInString(content)
+	return TplPreview{
+		Content:  content,
+		Length:   length,
+		Segments: SmsSegments(length),
+	}, nil
+}
+
+// SmsSegments 计算短信计费条数。
+func SmsSegments(length int) int {
+	switch {
+	case length == 0:
+		return 0
+	case length <= SmsSingleSegmentLen:
+		return 1
+	default:
+		return (length + SmsMultiSegmentLen - 1) / SmsMultiSegmentLen
+	}
+}
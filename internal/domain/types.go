@@ -10,6 +10,17 @@ const (
 	ChannelEmail
 )
 
+func (c Channel) String() string {
+	switch c {
+	case ChannelSms:
+		return "sms"
+	case ChannelEmail:
+		return "email"
+	default:
+		return "unspecified"
+	}
+}
+
 func (c Channel) IsValid() bool {
 	switch c {
 	case ChannelSms, ChannelEmail:
@@ -73,3 +84,20 @@ func (s AuditStatus) IsApproved() bool {
 func (s AuditStatus) IsRejected() bool {
 	return s == AuditStatusRejected
 }
+
+// AuditAction 审批操作
+type AuditAction string
+
+const (
+	AuditActionSubmit  AuditAction = "submit"  // 提交审批
+	AuditActionApprove AuditAction = "approve" // 审批通过
+	AuditActionReject  AuditAction = "reject"  // 审批拒绝
+)
+
+// ActivationAction 版本激活操作
+type ActivationAction string
+
+const (
+	ActivationActionActivate ActivationAction = "activate" // 激活
+	ActivationActionRollback ActivationAction = "rollback" // 回滚
+)
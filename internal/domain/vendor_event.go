@@ -0,0 +1,21 @@
+package domain
+
+// ProviderTplAuditEvent 供应商推送的模板审核结果事件。
+type ProviderTplAuditEvent struct {
+	ProviderId      uint64      `json:"provider_id"`      // 供应商 id
+	ProviderTplId   string      `json:"provider_tpl_id"`  // 供应商侧模板 id
+	AuditStatus     AuditStatus `json:"audit_status"`     // 审核状态
+	RejectionReason string      `json:"rejection_reason"` // 拒绝原因
+	ReviewAt        int64       `json:"review_at"`        // 审核时间
+}
+
+// DeliveryReceiptEvent 供应商推送的消息送达回执事件。
+type DeliveryReceiptEvent struct {
+	ProviderId uint64 `json:"provider_id"` // 供应商 id
+	SerialNo   string `json:"serial_no"`   // 供应商侧发送流水号
+	Receiver   string `json:"receiver"`    // 接收者
+	Success    bool   `json:"success"`     // 是否送达
+	ErrCode    string `json:"err_code"`    // 供应商错误码
+	ErrMsg     string `json:"err_msg"`     // 供应商错误描述
+	ReceivedAt int64  `json:"received_at"` // 用户接收时间
+}
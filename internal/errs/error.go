@@ -13,4 +13,8 @@ var (
 	ErrNotApprovedTplVersion = errors.New("[kuryr] not approved channel template version")
 
 	ErrFailedToSendNotification = errors.New("[kuryr] failed to send notification")
+
+	ErrQuotaExceeded = errors.New("[kuryr] quota exceeded")
+
+	ErrSystemBusy = errors.New("[kuryr] system busy, please retry later")
 )
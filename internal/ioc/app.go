@@ -2,23 +2,39 @@ package ioc
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
+	"net/http"
 	"time"
 
 	"github.com/JrMarcco/easy-grpc/registry"
+	"github.com/JrMarcco/kuryr/internal/api/admin"
 	"github.com/spf13/viper"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 )
 
-var AppFxOpt = fx.Module("app", fx.Invoke(InitApp))
+var AppFxOpt = fx.Module(
+	"app",
+	fx.Invoke(
+		fx.Annotate(
+			InitApp,
+			fx.ParamTags(``, ``, ``, ``, `group:"http_server"`, ``),
+		),
+	),
+)
 
-// App 应用整体的封装，组合了 grpc.Server
+// App 应用整体的封装，组合了 grpc.Server 以及附属的 http 服务。
+// grpc 服务监听 app.addr，与注册到注册中心的地址一致。
 type App struct {
 	*grpc.Server
 
+	httpServers []*HttpServer
+	health      *admin.Health
+
+	addr            string
 	timeout         time.Duration
 	registry        registry.Registry
 	serviceInstance registry.ServiceInstance
@@ -27,17 +43,23 @@ type App struct {
 }
 
 func (app *App) Start() error {
-	ln, err := net.Listen("tcp", ":50501")
+	ln, err := net.Listen("tcp", app.addr)
 	if err != nil {
-		return err
+		return fmt.Errorf("[kuryr] failed to listen grpc server [ %s ]: %w", app.addr, err)
 	}
 
 	go func() {
 		if serveErr := app.Serve(ln); serveErr != nil {
 			panic(serveErr)
 		}
-		app.logger.Info("[kuryr] successfully started grpc server")
 	}()
+	app.logger.Info("[kuryr] successfully started grpc server", zap.String("addr", app.addr))
+
+	for _, hs := range app.httpServers {
+		if err = app.startHttpServer(hs); err != nil {
+			return err
+		}
+	}
 
 	if app.registry != nil {
 		// 注册服务到注册中心
@@ -50,10 +72,15 @@ func (app *App) Start() error {
 		}
 		app.logger.Info("[kuryr] successfully registered service instance]")
 	}
+
+	app.health.SetReady(true)
 	return nil
 }
 
 func (app *App) Stop() error {
+	// 先标记为未就绪，让负载均衡摘除流量。
+	app.health.SetReady(false)
+
 	if app.registry != nil {
 		// 从注册中心注销服务
 		unregisterCtx, cancel := context.WithTimeout(context.Background(), app.timeout)
@@ -68,6 +95,10 @@ func (app *App) Stop() error {
 		_ = app.registry.Close()
 	}
 
+	for _, hs := range app.httpServers {
+		app.stopHttpServer(hs)
+	}
+
 	// 优雅推出
 	app.logger.Info("[kuryr] gracefully stopping grpc server ...")
 	app.GracefulStop()
@@ -75,8 +106,41 @@ func (app *App) Stop() error {
 	return nil
 }
 
+func (app *App) startHttpServer(hs *HttpServer) error {
+	ln, err := net.Listen("tcp", hs.Addr)
+	if err != nil {
+		return fmt.Errorf("[kuryr] failed to listen http server [ %s ]: %w", hs.name, err)
+	}
+
+	go func() {
+		if serveErr := hs.Serve(ln); serveErr != nil && !errors.Is(serveErr, http.ErrServerClosed) {
+			app.logger.Error("[kuryr] http server exited", zap.String("name", hs.name), zap.Error(serveErr))
+		}
+	}()
+	app.logger.Info("[kuryr] successfully started http server", zap.String("name", hs.name), zap.String("addr", hs.Addr))
+	return nil
+}
+
+func (app *App) stopHttpServer(hs *HttpServer) {
+	ctx, cancel := context.WithTimeout(context.Background(), app.timeout)
+	defer cancel()
+
+	if err := hs.Shutdown(ctx); err != nil {
+		app.logger.Error("[kuryr] failed to shutdown http server", zap.String("name", hs.name), zap.Error(err))
+		return
+	}
+	app.logger.Info("[kuryr] http server stopped", zap.String("name", hs.name))
+}
+
 // InitApp 初始化 app
-func InitApp(lc fx.Lifecycle, grpcServer *grpc.Server, r registry.Registry, logger *zap.Logger) *App {
+func InitApp(
+	lc fx.Lifecycle,
+	grpcServer *grpc.Server,
+	r registry.Registry,
+	logger *zap.Logger,
+	httpServers []*HttpServer,
+	health *admin.Health,
+) *App {
 	type config struct {
 		Name        string `mapstructure:"name"`
 		Addr        string `mapstructure:"addr"`
@@ -101,6 +165,9 @@ func InitApp(lc fx.Lifecycle, grpcServer *grpc.Server, r registry.Registry, logg
 
 	app := &App{
 		Server:          grpcServer,
+		httpServers:     httpServers,
+		health:          health,
+		addr:            cfg.Addr,
 		timeout:         time.Duration(cfg.Timeout) * time.Millisecond,
 		registry:        r,
 		serviceInstance: si,
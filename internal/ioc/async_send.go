@@ -0,0 +1,135 @@
+package ioc
+
+import (
+	"context"
+	"time"
+
+	"github.com/JrMarcco/kuryr/internal/domain"
+	"github.com/JrMarcco/kuryr/internal/pkg/mq"
+	"github.com/JrMarcco/kuryr/internal/service/notification"
+	"github.com/JrMarcco/kuryr/internal/service/sendstrategy"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/spf13/viper"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+var AsyncSendFxOpt = fx.Module(
+	"async-send",
+	fx.Provide(
+		InitAsyncSendLagMonitor,
+
+		// send service
+		fx.Annotate(
+			InitSendService,
+			fx.As(fx.Self()),
+			fx.As(new(notification.SendService)),
+		),
+	),
+	fx.Invoke(InitAsyncSendConsumer),
+)
+
+type asyncSendConfig struct {
+	GroupId   string `mapstructure:"group_id"`
+	BatchSize int    `mapstructure:"batch_size"`
+	Consumer  struct {
+		Concurrency   int   `mapstructure:"concurrency"`
+		RetryBackoffs []int `mapstructure:"retry_backoffs"` // 单位：毫秒
+	} `mapstructure:"consumer"`
+	Backpressure struct {
+		MaxLag        int64 `mapstructure:"max_lag"`
+		CheckInterval int   `mapstructure:"check_interval"` // 单位：毫秒
+	} `mapstructure:"backpressure"`
+}
+
+func loadAsyncSendConfig() (asyncSendConfig, string) {
+	cfg := asyncSendConfig{}
+	if err := viper.UnmarshalKey("async_send", &cfg); err != nil {
+		panic(err)
+	}
+
+	var topic string
+	if err := viper.UnmarshalKey("kafka.topics.async_send", &topic); err != nil {
+		panic(err)
+	}
+	return cfg, topic
+}
+
+// InitAsyncSendLagMonitor 初始化异步发送消费积压监控。
+func InitAsyncSendLagMonitor(lc fx.Lifecycle, logger *zap.Logger) *mq.LagMonitor {
+	cfg, topic := loadAsyncSendConfig()
+
+	var bootstrapServers string
+	if err := viper.UnmarshalKey("kafka.bootstrap_servers", &bootstrapServers); err != nil {
+		panic(err)
+	}
+	admin, err := kafka.NewAdminClient(&kafka.ConfigMap{"bootstrap.servers": bootstrapServers})
+	if err != nil {
+		panic(err)
+	}
+
+	monitor := mq.NewLagMonitor(
+		admin,
+		cfg.GroupId,
+		topic,
+		cfg.Backpressure.MaxLag,
+		time.Duration(cfg.Backpressure.CheckInterval)*time.Millisecond,
+		logger,
+	)
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			return monitor.Start()
+		},
+		OnStop: func(ctx context.Context) error {
+			err := monitor.Stop()
+			admin.Close()
+			return err
+		},
+	})
+	return monitor
+}
+
+// InitSendService 初始化消息发送服务，发送前统一经过模板参数校验。
+func InitSendService(
+	strategy *sendstrategy.TplValidateStrategy,
+	producer mq.Producer[domain.AsyncSendEvent],
+	monitor *mq.LagMonitor,
+) *notification.DefaultSendService {
+	cfg, _ := loadAsyncSendConfig()
+	return notification.NewDefaultSendService(strategy, strategy, producer, monitor, cfg.BatchSize)
+}
+
+// InitAsyncSendConsumer 初始化异步发送事件消费者，消费异步发送事件并入库。
+func InitAsyncSendConsumer(
+	lc fx.Lifecycle,
+	producer *mq.AsyncProducer,
+	svc *notification.DefaultSendService,
+	logger *zap.Logger,
+) {
+	cfg, topic := loadAsyncSendConfig()
+
+	retryBackoffs := make([]time.Duration, 0, len(cfg.Consumer.RetryBackoffs))
+	for _, backoff := range cfg.Consumer.RetryBackoffs {
+		retryBackoffs = append(retryBackoffs, time.Duration(backoff)*time.Millisecond)
+	}
+	consumer := mq.NewGeneralConsumer[domain.AsyncSendEvent](
+		newKafkaConsumer(cfg.GroupId),
+		producer,
+		mq.ConsumerConfig{
+			Topic:         topic,
+			Concurrency:   cfg.Consumer.Concurrency,
+			RetryBackoffs: retryBackoffs,
+		},
+		svc.HandleAsyncSendEvent,
+		logger,
+	)
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			return consumer.Start()
+		},
+		OnStop: func(ctx context.Context) error {
+			return consumer.Stop()
+		},
+	})
+}
@@ -0,0 +1,64 @@
+package ioc
+
+import (
+	"context"
+	"time"
+
+	"github.com/JrMarcco/kuryr/internal/pkg/sharding"
+	"github.com/JrMarcco/kuryr/internal/repository"
+	"github.com/JrMarcco/kuryr/internal/service/callback"
+	"github.com/spf13/viper"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+var CallbackOutboxRelayFxOpt = fx.Module(
+	"callback-outbox-relay",
+	fx.Provide(
+		fx.Annotate(
+			InitCallbackOutboxRelay,
+			fx.ParamTags(``, ``, ``, `name:"cbl_sharding_strategy"`),
+		),
+	),
+	fx.Invoke(func(*callback.OutboxRelay) {}),
+)
+
+// InitCallbackOutboxRelay 初始化回调 outbox 转发任务。
+func InitCallbackOutboxRelay(
+	lc fx.Lifecycle,
+	outboxRepo repository.CallbackOutboxRepo,
+	logRepo repository.CallbackLogRepo,
+	shardingStrategy sharding.Strategy,
+	logger *zap.Logger,
+) *callback.OutboxRelay {
+	type config struct {
+		Interval      int `mapstructure:"interval"`       // 单位：毫秒
+		RetryInterval int `mapstructure:"retry_interval"` // 单位：毫秒
+		BatchSize     int `mapstructure:"batch_size"`
+	}
+
+	cfg := config{}
+	if err := viper.UnmarshalKey("callback_outbox_relay", &cfg); err != nil {
+		panic(err)
+	}
+
+	relay := callback.NewOutboxRelay(
+		outboxRepo,
+		logRepo,
+		shardingStrategy,
+		time.Duration(cfg.Interval)*time.Millisecond,
+		time.Duration(cfg.RetryInterval)*time.Millisecond,
+		cfg.BatchSize,
+		logger,
+	)
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			return relay.Start()
+		},
+		OnStop: func(ctx context.Context) error {
+			return relay.Stop()
+		},
+	})
+	return relay
+}
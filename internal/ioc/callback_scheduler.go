@@ -0,0 +1,89 @@
+package ioc
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/JrMarcco/kuryr/internal/pkg/batch/slidewindow"
+	"github.com/JrMarcco/kuryr/internal/service/callback"
+	"github.com/spf13/viper"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+var CallbackSchedulerFxOpt = fx.Module(
+	"callback-scheduler",
+	fx.Provide(InitCallbackScheduler),
+	fx.Invoke(func(*callback.Scheduler) {}),
+)
+
+// InitCallbackScheduler 初始化回调定时任务。
+func InitCallbackScheduler(
+	lc fx.Lifecycle,
+	svc callback.Service,
+	etcdClient *clientv3.Client,
+	logger *zap.Logger,
+) *callback.Scheduler {
+	type batchConfig struct {
+		BufferSize        int `mapstructure:"buffer_size"`
+		InitSize          int `mapstructure:"init_size"`
+		MinSize           int `mapstructure:"min_size"`
+		MaxSize           int `mapstructure:"max_size"`
+		AdjustStep        int `mapstructure:"adjust_step"`
+		MinAdjustInterval int `mapstructure:"min_adjust_interval"` // 单位：毫秒
+	}
+
+	type config struct {
+		ElectionKey string      `mapstructure:"election_key"`
+		SessionTTL  int         `mapstructure:"session_ttl"` // 单位：秒
+		Interval    int         `mapstructure:"interval"`    // 单位：毫秒
+		Batch       batchConfig `mapstructure:"batch"`
+	}
+
+	cfg := config{}
+	if err := viper.UnmarshalKey("callback_scheduler", &cfg); err != nil {
+		panic(err)
+	}
+
+	adjuster, err := slidewindow.NewAdjuster(
+		cfg.Batch.BufferSize,
+		cfg.Batch.InitSize,
+		cfg.Batch.MinSize,
+		cfg.Batch.MaxSize,
+		cfg.Batch.AdjustStep,
+		time.Duration(cfg.Batch.MinAdjustInterval)*time.Millisecond,
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		panic(err)
+	}
+
+	scheduler := callback.NewScheduler(
+		svc,
+		adjuster,
+		etcdClient,
+		cfg.ElectionKey,
+		fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+		cfg.SessionTTL,
+		time.Duration(cfg.Interval)*time.Millisecond,
+		min(max(cfg.Batch.InitSize, cfg.Batch.MinSize), cfg.Batch.MaxSize),
+		logger,
+	)
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			return scheduler.Start()
+		},
+		OnStop: func(ctx context.Context) error {
+			return scheduler.Stop()
+		},
+	})
+	return scheduler
+}
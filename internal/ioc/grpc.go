@@ -1,6 +1,11 @@
 package ioc
 
 import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
 	"time"
 
 	"github.com/JrMarcco/easy-grpc/client"
@@ -12,8 +17,13 @@ import (
 	configv1 "github.com/JrMarcco/kuryr-api/api/go/config/v1"
 	notificationv1 "github.com/JrMarcco/kuryr-api/api/go/notification/v1"
 	providerv1 "github.com/JrMarcco/kuryr-api/api/go/provider/v1"
+	templatev1 "github.com/JrMarcco/kuryr-api/api/go/template/v1"
 	"github.com/JrMarcco/kuryr/internal/api"
+	"github.com/JrMarcco/kuryr/internal/api/interceptor/jwt"
+	"github.com/JrMarcco/kuryr/internal/pkg/tracing"
 	"github.com/spf13/viper"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/fx"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/balancer"
@@ -25,10 +35,12 @@ var GrpcFxOpt = fx.Module(
 	"grpc",
 	fx.Provide(
 		InitGrpc,
+		InitJwtInterceptorBuilder,
 		InitCallbackGrpcClients,
 		api.NewBizInfoServer,
 		api.NewBizConfigServer,
 		api.NewProviderServer,
+		api.NewTemplateServer,
 		api.NewNotificationServer,
 	),
 )
@@ -37,19 +49,84 @@ func InitGrpc(
 	bizInfoServer *api.BizInfoServer,
 	bizConfigServer *api.BizConfigServer,
 	providerServer *api.ProviderServer,
+	templateServer *api.TemplateServer,
 	notificationServer *api.NotificationServer,
+	jwtBuilder *jwt.InterceptorBuilder,
+	tp trace.TracerProvider,
 ) *grpc.Server {
-	grpcServer := grpc.NewServer()
+	grpcServer := grpc.NewServer(
+		// 校验 jwt 并将 uid / biz_id / biz_key 写入 context。
+		grpc.ChainUnaryInterceptor(jwtBuilder.Build()),
+		// 从请求 metadata 中还原调用方的链路上下文并开启 server span。
+		grpc.StatsHandler(otelgrpc.NewServerHandler(otelgrpc.WithTracerProvider(tp))),
+	)
 
 	businessv1.RegisterBusinessServiceServer(grpcServer, bizInfoServer)
 	configv1.RegisterBizConfigServiceServer(grpcServer, bizConfigServer)
 	providerv1.RegisterProviderServiceServer(grpcServer, providerServer)
+	templatev1.RegisterTemplateServiceServer(grpcServer, templateServer)
 	notificationv1.RegisterNotificationServiceServer(grpcServer, notificationServer)
 	return grpcServer
 }
 
+// InitJwtInterceptorBuilder 从 PEM 文件加载 ed25519 密钥对并创建 jwt 拦截器构造器。
+// 服务端只需要公钥校验 token，私钥文件未配置时不加载。
+func InitJwtInterceptorBuilder() *jwt.InterceptorBuilder {
+	type config struct {
+		PrivateKeyFile string `mapstructure:"private_key_file"`
+		PublicKeyFile  string `mapstructure:"public_key_file"`
+	}
+
+	cfg := config{}
+	if err := viper.UnmarshalKey("grpc.server.jwt", &cfg); err != nil {
+		panic(err)
+	}
+
+	pubKey, err := loadPemBlock(cfg.PublicKeyFile)
+	if err != nil {
+		panic(err)
+	}
+	parsedPub, err := x509.ParsePKIXPublicKey(pubKey)
+	if err != nil {
+		panic(err)
+	}
+	edPub, ok := parsedPub.(ed25519.PublicKey)
+	if !ok {
+		panic(fmt.Errorf("[kuryr] jwt public key is not ed25519: %s", cfg.PublicKeyFile))
+	}
+
+	var edPri ed25519.PrivateKey
+	if cfg.PrivateKeyFile != "" {
+		priKey, err := loadPemBlock(cfg.PrivateKeyFile)
+		if err != nil {
+			panic(err)
+		}
+		parsedPri, err := x509.ParsePKCS8PrivateKey(priKey)
+		if err != nil {
+			panic(err)
+		}
+		if edPri, ok = parsedPri.(ed25519.PrivateKey); !ok {
+			panic(fmt.Errorf("[kuryr] jwt private key is not ed25519: %s", cfg.PrivateKeyFile))
+		}
+	}
+
+	return jwt.Builder(edPri, edPub)
+}
+
+func loadPemBlock(file string) ([]byte, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("[kuryr] failed to read pem file %s: %w", file, err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("[kuryr] failed to decode pem file: %s", file)
+	}
+	return block.Bytes, nil
+}
+
 // InitCallbackGrpcClients 初始化回调通知的 grpc 客户端。
-func InitCallbackGrpcClients(r registry.Registry) *client.Manager[clientv1.CallbackServiceClient] {
+func InitCallbackGrpcClients(r registry.Registry, tp trace.TracerProvider) *client.Manager[clientv1.CallbackServiceClient] {
 	type keepaliveConfig struct {
 		Time                int  `mapstructure:"time"`    // keep alive 请求间隔时间，单位：毫秒
 		Timeout             int  `mapstructure:"timeout"` // keep alive 请求超时时间，单位：毫秒
@@ -82,7 +159,8 @@ func InitCallbackGrpcClients(r registry.Registry) *client.Manager[clientv1.Callb
 		rr.NewResolverBuilder(r, time.Duration(cfg.Timeout)*time.Millisecond),
 		bb,
 		func(conn *grpc.ClientConn) clientv1.CallbackServiceClient {
-			return clientv1.NewCallbackServiceClient(conn)
+			// easy-grpc 不支持设置 dial option，通过包装连接为回调请求开启 client span。
+			return clientv1.NewCallbackServiceClient(tracing.NewClientConn(conn, tp))
 		},
 	).KeepAlive(keepalive.ClientParameters{
 		Time:                time.Duration(cfg.Keepalive.Time) * time.Millisecond,
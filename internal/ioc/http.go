@@ -0,0 +1,149 @@
+package ioc
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/JrMarcco/kuryr/internal/api/admin"
+	"github.com/JrMarcco/kuryr/internal/api/vendorcb"
+	"github.com/JrMarcco/kuryr/internal/service/vendorevent"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spf13/viper"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+var HttpFxOpt = fx.Module(
+	"http",
+	fx.Provide(
+		admin.NewHealth,
+
+		fx.Annotate(
+			InitVendorCallbackServer,
+			fx.ResultTags(`group:"http_server"`),
+		),
+		fx.Annotate(
+			InitAdminServer,
+			fx.ResultTags(`group:"http_server"`),
+		),
+	),
+)
+
+// HttpServer 由 App 统一管理生命周期的 http 服务。
+type HttpServer struct {
+	*http.Server
+
+	name string
+}
+
+// InitVendorCallbackServer 初始化供应商推送回调的 http 服务。
+func InitVendorCallbackServer(svc vendorevent.Service, logger *zap.Logger) *HttpServer {
+	type vendorConfig struct {
+		VerifyType string   `mapstructure:"verify_type"` // 校验方式：token / hmac / none ( 只校验来源 ip )
+		Secret     string   `mapstructure:"secret"`
+		MaxSkew    int      `mapstructure:"max_skew"`  // hmac 时间戳最大偏差，单位：毫秒
+		AllowIps   []string `mapstructure:"allow_ips"` // 来源 ip 白名单，为空不限制
+	}
+
+	type config struct {
+		Addr         string                  `mapstructure:"addr"`
+		ReadTimeout  int                     `mapstructure:"read_timeout"`  // 单位：毫秒
+		WriteTimeout int                     `mapstructure:"write_timeout"` // 单位：毫秒
+		MaxBodySize  int64                   `mapstructure:"max_body_size"` // 单位：字节
+		Vendors      map[string]vendorConfig `mapstructure:"vendors"`
+	}
+
+	cfg := config{}
+	if err := viper.UnmarshalKey("vendor_callback", &cfg); err != nil {
+		panic(err)
+	}
+
+	vendors := []vendorcb.Vendor{
+		vendorcb.NewTencentVendor(),
+		vendorcb.NewAliyunVendor(),
+	}
+
+	endpoints := make([]vendorcb.Endpoint, 0, len(vendors))
+	for _, vendor := range vendors {
+		vendorCfg, ok := cfg.Vendors[vendor.Name()]
+		if !ok {
+			continue
+		}
+
+		allowList, err := vendorcb.NewIpAllowList(vendorCfg.AllowIps)
+		if err != nil {
+			panic(err)
+		}
+
+		var verifier vendorcb.Verifier
+		switch vendorCfg.VerifyType {
+		case "token":
+			verifier = vendorcb.NewTokenVerifier(vendorCfg.Secret)
+		case "hmac":
+			verifier = vendorcb.NewHmacVerifier(vendorCfg.Secret, time.Duration(vendorCfg.MaxSkew)*time.Millisecond)
+		case "none":
+			// 不校验请求内容时必须配置来源 ip 白名单。
+			if len(vendorCfg.AllowIps) == 0 {
+				panic(fmt.Sprintf("[kuryr] vendor [ %s ] callback without verifier requires allow ips", vendor.Name()))
+			}
+		default:
+			panic(fmt.Sprintf("[kuryr] unsupported vendor callback verify type [ %s ]", vendorCfg.VerifyType))
+		}
+
+		endpoints = append(endpoints, vendorcb.Endpoint{
+			Vendor:    vendor,
+			Verifier:  verifier,
+			AllowList: allowList,
+		})
+	}
+
+	server := vendorcb.NewServer(svc, logger, cfg.MaxBodySize, endpoints...)
+	return &HttpServer{
+		Server: &http.Server{
+			Addr:         cfg.Addr,
+			Handler:      server.Handler(),
+			ReadTimeout:  time.Duration(cfg.ReadTimeout) * time.Millisecond,
+			WriteTimeout: time.Duration(cfg.WriteTimeout) * time.Millisecond,
+		},
+		name: "vendor_callback",
+	}
+}
+
+// defaultAdminAddr 运维管理服务默认只监听本机，pprof 等接口不对外暴露。
+const defaultAdminAddr = "127.0.0.1:50503"
+
+// InitAdminServer 初始化运维管理 http 服务，提供指标、pprof、健康检查以及构建信息。
+// 未配置监听地址时使用 defaultAdminAddr。
+func InitAdminServer(health *admin.Health, logger *zap.Logger) *HttpServer {
+	type config struct {
+		Addr         string `mapstructure:"addr"`
+		ReadTimeout  int    `mapstructure:"read_timeout"`  // 单位：毫秒
+		WriteTimeout int    `mapstructure:"write_timeout"` // 单位：毫秒，需要大于 pprof 采样时间
+	}
+
+	cfg := config{}
+	if err := viper.UnmarshalKey("admin_server", &cfg); err != nil {
+		panic(err)
+	}
+
+	var appName string
+	if err := viper.UnmarshalKey("app.name", &appName); err != nil {
+		panic(err)
+	}
+
+	if cfg.Addr == "" {
+		cfg.Addr = defaultAdminAddr
+	}
+
+	server := admin.NewServer(appName, prometheus.DefaultGatherer, health, logger)
+	return &HttpServer{
+		Server: &http.Server{
+			Addr:         cfg.Addr,
+			Handler:      server.Handler(),
+			ReadTimeout:  time.Duration(cfg.ReadTimeout) * time.Millisecond,
+			WriteTimeout: time.Duration(cfg.WriteTimeout) * time.Millisecond,
+		},
+		name: "admin",
+	}
+}
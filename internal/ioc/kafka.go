@@ -0,0 +1,183 @@
+package ioc
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/JrMarcco/kuryr/internal/domain"
+	"github.com/JrMarcco/kuryr/internal/pkg/mq"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/spf13/viper"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// 消息 header 中的事件类型。
+const (
+	eventTypeTplProviderBind    = "tpl_provider_bind"
+	eventTypeCallbackDeadLetter = "callback_dead_letter"
+	eventTypeAsyncSend          = "async_send"
+)
+
+var KafkaFxOpt = fx.Module(
+	"kafka",
+	fx.Provide(
+		InitKafkaProducer,
+
+		// template provider bind event producer
+		fx.Annotate(
+			InitTplProviderBindProducer,
+			fx.As(new(mq.Producer[domain.TplProviderBindEvent])),
+		),
+
+		// callback dead letter event producer
+		InitCallbackDeadLetterProducer,
+
+		// async send event producer
+		fx.Annotate(
+			InitAsyncSendProducer,
+			fx.As(new(mq.Producer[domain.AsyncSendEvent])),
+		),
+	),
+)
+
+// InitKafkaProducer 初始化 kafka 生产者，各类消息共用同一个底层生产者。
+func InitKafkaProducer(lc fx.Lifecycle, logger *zap.Logger) *mq.AsyncProducer {
+	type producerConfig struct {
+		LingerMs         int    `mapstructure:"linger_ms"`
+		BatchSize        int    `mapstructure:"batch_size"` // 单位：字节
+		BatchNumMessages int    `mapstructure:"batch_num_messages"`
+		CompressionType  string `mapstructure:"compression_type"`
+	}
+
+	type config struct {
+		BootstrapServers string         `mapstructure:"bootstrap_servers"`
+		Producer         producerConfig `mapstructure:"producer"`
+	}
+
+	cfg := config{}
+	if err := viper.UnmarshalKey("kafka", &cfg); err != nil {
+		panic(err)
+	}
+
+	configMap := &kafka.ConfigMap{
+		"bootstrap.servers":  cfg.BootstrapServers,
+		"acks":               "all",
+		"enable.idempotence": true,
+	}
+	// 未配置的参数使用 librdkafka 默认值。
+	if cfg.Producer.LingerMs > 0 {
+		_ = configMap.SetKey("linger.ms", cfg.Producer.LingerMs)
+	}
+	if cfg.Producer.BatchSize > 0 {
+		_ = configMap.SetKey("batch.size", cfg.Producer.BatchSize)
+	}
+	if cfg.Producer.BatchNumMessages > 0 {
+		_ = configMap.SetKey("batch.num.messages", cfg.Producer.BatchNumMessages)
+	}
+	if cfg.Producer.CompressionType != "" {
+		_ = configMap.SetKey("compression.type", cfg.Producer.CompressionType)
+	}
+
+	producer, err := kafka.NewProducer(configMap)
+	if err != nil {
+		panic(err)
+	}
+
+	asyncProducer := mq.NewAsyncProducer(producer, logger)
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			return asyncProducer.Start()
+		},
+		OnStop: func(ctx context.Context) error {
+			// 等待所有消息发送完成后关闭
+			return asyncProducer.Stop(ctx)
+		},
+	})
+	return asyncProducer
+}
+
+func InitTplProviderBindProducer(producer *mq.AsyncProducer) *mq.GeneralProducer[domain.TplProviderBindEvent] {
+	var topic string
+	if err := viper.UnmarshalKey("kafka.topics.tpl_provider_bind", &topic); err != nil {
+		panic(err)
+	}
+	// 按模板 id 分区，保证同一模板的关联事件有序。
+	return mq.NewGeneralProducer(topic, producer,
+		mq.WithKeyFunc(func(event domain.TplProviderBindEvent) []byte {
+			return []byte(strconv.FormatUint(event.TplId, 10))
+		}),
+		mq.WithMetadataFunc(func(_ domain.TplProviderBindEvent) mq.Metadata {
+			return mq.Metadata{EventType: eventTypeTplProviderBind}
+		}),
+	)
+}
+
+// newKafkaConsumer 创建 kafka 消费者，关闭自动提交，由消费方在处理完成后手动提交。
+//
+// 重试消息的延迟通过暂停分区实现，不占用 poll 间隔，max.poll.interval.ms 只需要覆盖单批消息的处理时间。
+func newKafkaConsumer(groupId string) *kafka.Consumer {
+	type config struct {
+		BootstrapServers string `mapstructure:"bootstrap_servers"`
+		Consumer         struct {
+			MaxPollInterval int `mapstructure:"max_poll_interval"` // 单位：毫秒
+		} `mapstructure:"consumer"`
+	}
+
+	cfg := config{}
+	if err := viper.UnmarshalKey("kafka", &cfg); err != nil {
+		panic(err)
+	}
+
+	consumer, err := kafka.NewConsumer(&kafka.ConfigMap{
+		"bootstrap.servers":    cfg.BootstrapServers,
+		"group.id":             groupId,
+		"auto.offset.reset":    "earliest",
+		"enable.auto.commit":   false,
+		"max.poll.interval.ms": cfg.Consumer.MaxPollInterval,
+	})
+	if err != nil {
+		panic(err)
+	}
+	return consumer
+}
+
+// InitCallbackDeadLetterProducer 初始化回调死信事件生产者，未配置 topic 时不发送死信事件。
+func InitCallbackDeadLetterProducer(producer *mq.AsyncProducer) mq.Producer[domain.CallbackDeadLetterEvent] {
+	var topic string
+	if err := viper.UnmarshalKey("kafka.topics.callback_dead_letter", &topic); err != nil {
+		panic(err)
+	}
+	if topic == "" {
+		return nil
+	}
+	// 按业务 id 分区，保证同一业务方的死信事件有序。
+	return mq.NewGeneralProducer(topic, producer,
+		mq.WithKeyFunc(func(event domain.CallbackDeadLetterEvent) []byte {
+			return []byte(strconv.FormatUint(event.BizId, 10))
+		}),
+		mq.WithMetadataFunc(func(event domain.CallbackDeadLetterEvent) mq.Metadata {
+			return mq.Metadata{
+				BizId:          event.BizId,
+				NotificationId: event.NotificationId,
+				EventType:      eventTypeCallbackDeadLetter,
+			}
+		}),
+	)
+}
+
+func InitAsyncSendProducer(producer *mq.AsyncProducer) *mq.GeneralProducer[domain.AsyncSendEvent] {
+	var topic string
+	if err := viper.UnmarshalKey("kafka.topics.async_send", &topic); err != nil {
+		panic(err)
+	}
+	// 按业务 id 分区，同一业务方的消息由同一个分区顺序入库，避免单个业务方的大批量请求影响其他业务方。
+	return mq.NewGeneralProducer(topic, producer,
+		mq.WithKeyFunc(func(event domain.AsyncSendEvent) []byte {
+			return []byte(strconv.FormatUint(event.BizId, 10))
+		}),
+		mq.WithMetadataFunc(func(event domain.AsyncSendEvent) mq.Metadata {
+			return mq.Metadata{BizId: event.BizId, EventType: eventTypeAsyncSend}
+		}),
+	)
+}
@@ -12,7 +12,7 @@ import (
 	"go.uber.org/zap"
 )
 
-var MongoFxOpt = fx.Module("mongo", fx.Provide(InitMongoClient))
+var MongoFxOpt = fx.Module("mongo", fx.Provide(InitMongoClient, InitMongoDatabase))
 
 func InitMongoClient(lc fx.Lifecycle, logger *zap.Logger) *mongo.Client {
 	type config struct {
@@ -95,3 +95,12 @@ func InitMongoClient(lc fx.Lifecycle, logger *zap.Logger) *mongo.Client {
 
 	return client
 }
+
+// InitMongoDatabase 初始化 kuryr 使用的 mongo 数据库。
+func InitMongoDatabase(client *mongo.Client) *mongo.Database {
+	var database string
+	if err := viper.UnmarshalKey("mongo.database", &database); err != nil {
+		panic(err)
+	}
+	return client.Database(database)
+}
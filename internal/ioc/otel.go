@@ -0,0 +1,150 @@
+package ioc
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/JrMarcco/kuryr/internal/api/admin"
+	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// 链路数据导出方式
+const (
+	otelExporterOtlpGrpc = "otlp_grpc"
+	otelExporterOtlpHttp = "otlp_http"
+	otelExporterStdout   = "stdout" // 输出到标准输出，用于本地调试
+	otelExporterNone     = "none"   // 不采集链路数据
+)
+
+var OtelFxOpt = fx.Module(
+	"otel",
+	fx.Provide(InitTracerProvider),
+)
+
+// InitTracerProvider 初始化 otel sdk 并设置为全局 TracerProvider / TextMapPropagator。
+// 导出方式为 none 时返回 noop 实现，全局 TracerProvider 保持默认 ( 同样为 noop )。
+func InitTracerProvider(lc fx.Lifecycle, logger *zap.Logger) trace.TracerProvider {
+	type exporterConfig struct {
+		Type     string `mapstructure:"type"`     // otlp_grpc / otlp_http / stdout / none
+		Endpoint string `mapstructure:"endpoint"` // otlp collector 地址，例如 localhost:4317 ( grpc ) / localhost:4318 ( http )
+		Insecure bool   `mapstructure:"insecure"`
+		Timeout  int    `mapstructure:"timeout"` // 单位：毫秒
+	}
+
+	type config struct {
+		SamplerRatio float64        `mapstructure:"sampler_ratio"` // 根 span 采样率，子 span 跟随父 span 的采样结果
+		Exporter     exporterConfig `mapstructure:"exporter"`
+	}
+
+	cfg := config{}
+	if err := viper.UnmarshalKey("otel", &cfg); err != nil {
+		panic(err)
+	}
+
+	// 链路上下文统一使用 W3C trace context 与 baggage 格式传递。
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	if cfg.Exporter.Type == otelExporterNone {
+		return noop.NewTracerProvider()
+	}
+
+	exporter, err := newSpanExporter(cfg.Exporter.Type, cfg.Exporter.Endpoint, cfg.Exporter.Insecure, time.Duration(cfg.Exporter.Timeout)*time.Millisecond)
+	if err != nil {
+		panic(err)
+	}
+
+	res, err := newOtelResource()
+	if err != nil {
+		panic(err)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SamplerRatio))),
+	)
+	otel.SetTracerProvider(tp)
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		logger.Warn("[kuryr] otel error", zap.Error(err))
+	}))
+
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			// 导出缓冲区中剩余的 span
+			return tp.Shutdown(ctx)
+		},
+	})
+	return tp
+}
+
+func newSpanExporter(typ, endpoint string, insecure bool, timeout time.Duration) (sdktrace.SpanExporter, error) {
+	switch typ {
+	case otelExporterOtlpGrpc:
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(endpoint)}
+		if insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		if timeout > 0 {
+			opts = append(opts, otlptracegrpc.WithTimeout(timeout))
+		}
+		// 不阻塞等待连接建立，collector 不可用时不影响启动。
+		return otlptracegrpc.New(context.Background(), opts...)
+	case otelExporterOtlpHttp:
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(endpoint)}
+		if insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		if timeout > 0 {
+			opts = append(opts, otlptracehttp.WithTimeout(timeout))
+		}
+		return otlptracehttp.New(context.Background(), opts...)
+	case otelExporterStdout:
+		return stdouttrace.New(stdouttrace.WithWriter(os.Stdout), stdouttrace.WithPrettyPrint())
+	default:
+		return nil, fmt.Errorf("[kuryr] unsupported otel exporter type [ %s ]", typ)
+	}
+}
+
+// newOtelResource 链路数据的资源属性：服务名、版本、实例以及部署环境。
+func newOtelResource() (*resource.Resource, error) {
+	type appConfig struct {
+		Name string `mapstructure:"name"`
+		Addr string `mapstructure:"addr"`
+	}
+
+	app := appConfig{}
+	if err := viper.UnmarshalKey("app", &app); err != nil {
+		return nil, err
+	}
+
+	var env string
+	if err := viper.UnmarshalKey("profile.env", &env); err != nil {
+		return nil, err
+	}
+
+	hostname, _ := os.Hostname()
+	return resource.Merge(
+		resource.Default(),
+		resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceName(app.Name),
+			semconv.ServiceVersion(admin.Version),
+			semconv.ServiceInstanceID(hostname+"/"+app.Addr),
+			semconv.DeploymentEnvironmentName(env),
+		),
+	)
+}
@@ -1,11 +1,16 @@
 package ioc
 
 import (
+	"context"
+
 	"github.com/JrMarcco/dlock"
 	dredis "github.com/JrMarcco/dlock/redis"
+	"github.com/JrMarcco/kuryr/internal/domain"
+	"github.com/JrMarcco/kuryr/internal/pkg/pubsub"
 	"github.com/redis/go-redis/v9"
 	"github.com/spf13/viper"
 	"go.uber.org/fx"
+	"go.uber.org/zap"
 )
 
 var RedisFxOpt = fx.Module(
@@ -13,6 +18,18 @@ var RedisFxOpt = fx.Module(
 	fx.Provide(
 		InitRedis,
 		InitDClient,
+
+		// biz config event bus
+		fx.Annotate(
+			InitBizConfigEventBus,
+			fx.As(new(pubsub.Bus[domain.BizConfigEvent])),
+		),
+
+		// channel template event bus
+		fx.Annotate(
+			InitChannelTplEventBus,
+			fx.As(new(pubsub.Bus[domain.ChannelTplEvent])),
+		),
 	),
 )
 
@@ -36,3 +53,41 @@ func InitRedis() redis.Cmdable {
 func InitDClient(rc redis.Cmdable) dlock.Dclient {
 	return dredis.NewDClientBuilder(rc).Build()
 }
+
+// InitBizConfigEventBus 初始化业务方配置变更事件总线。
+func InitBizConfigEventBus(lc fx.Lifecycle, rc redis.Cmdable, logger *zap.Logger) *pubsub.RedisBus[domain.BizConfigEvent] {
+	var channel string
+	if err := viper.UnmarshalKey("redis.channels.biz_config_event", &channel); err != nil {
+		panic(err)
+	}
+
+	bus := pubsub.NewRedisBus[domain.BizConfigEvent](rc, channel, logger)
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			return bus.Start()
+		},
+		OnStop: func(ctx context.Context) error {
+			return bus.Stop()
+		},
+	})
+	return bus
+}
+
+// InitChannelTplEventBus 初始化渠道模板变更事件总线。
+func InitChannelTplEventBus(lc fx.Lifecycle, rc redis.Cmdable, logger *zap.Logger) *pubsub.RedisBus[domain.ChannelTplEvent] {
+	var channel string
+	if err := viper.UnmarshalKey("redis.channels.channel_tpl_event", &channel); err != nil {
+		panic(err)
+	}
+
+	bus := pubsub.NewRedisBus[domain.ChannelTplEvent](rc, channel, logger)
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			return bus.Start()
+		},
+		OnStop: func(ctx context.Context) error {
+			return bus.Stop()
+		},
+	})
+	return bus
+}
@@ -1,14 +1,24 @@
 package ioc
 
 import (
+	"context"
+	"time"
+
+	"github.com/JrMarcco/kuryr/internal/domain"
+	"github.com/JrMarcco/kuryr/internal/pkg/pubsub"
 	"github.com/JrMarcco/kuryr/internal/repository"
 	"github.com/JrMarcco/kuryr/internal/repository/cache"
 	"github.com/JrMarcco/kuryr/internal/repository/cache/local"
 	"github.com/JrMarcco/kuryr/internal/repository/cache/redis"
 	"github.com/JrMarcco/kuryr/internal/repository/dao"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/spf13/viper"
 	"go.uber.org/fx"
+	"go.uber.org/zap"
 	"gorm.io/gorm"
+
+	gocache "github.com/patrickmn/go-cache"
+	goredis "github.com/redis/go-redis/v9"
 )
 
 var RepoFxOpt = fx.Module(
@@ -21,6 +31,18 @@ var RepoFxOpt = fx.Module(
 			fx.As(new(dao.NotificationDao)),
 		),
 
+		// callback outbox dao
+		fx.Annotate(
+			dao.NewDefaultCallbackOutboxDao,
+			fx.As(new(dao.CallbackOutboxDao)),
+		),
+
+		// delivery receipt dao
+		fx.Annotate(
+			dao.NewDefaultDeliveryReceiptDao,
+			fx.As(new(dao.DeliveryReceiptDao)),
+		),
+
 		// biz info dao
 		fx.Annotate(
 			dao.NewDefaultBizInfoDao,
@@ -49,6 +71,13 @@ var RepoFxOpt = fx.Module(
 		fx.Annotate(
 			dao.NewCallbackLogDao,
 			fx.As(new(dao.CallbackLogDao)),
+			fx.ParamTags(``, `name:"cbl_sharding_strategy"`),
+		),
+
+		// callback replay log dao
+		fx.Annotate(
+			dao.NewCallbackReplayLogDao,
+			fx.As(new(dao.CallbackReplayLogDao)),
 		),
 	),
 
@@ -56,7 +85,7 @@ var RepoFxOpt = fx.Module(
 	fx.Provide(
 		// local cache
 		fx.Annotate(
-			local.NewLBizConfigCache,
+			InitLBizConfigCache,
 			fx.As(new(cache.BizConfigCache)),
 			fx.ResultTags(`name:"local_biz_config_cache"`),
 		),
@@ -67,10 +96,27 @@ var RepoFxOpt = fx.Module(
 			fx.As(new(cache.BizConfigCache)),
 			fx.ResultTags(`name:"redis_biz_config_cache"`),
 		),
+
+		// channel template local cache
+		fx.Annotate(
+			InitLChannelTplCache,
+			fx.As(new(cache.ChannelTplCache)),
+			fx.ResultTags(`name:"local_channel_tpl_cache"`),
+		),
+
+		// channel template redis cache
+		fx.Annotate(
+			redis.NewRChannelTplCache,
+			fx.As(new(cache.ChannelTplCache)),
+			fx.ResultTags(`name:"redis_channel_tpl_cache"`),
+		),
 	),
 
 	// repo
 	fx.Provide(
+		// notification repo
+		repository.NewDefaultNotificationRepo,
+
 		// biz info repo
 		fx.Annotate(
 			repository.NewDefaultBizInfoRepo,
@@ -94,6 +140,7 @@ var RepoFxOpt = fx.Module(
 		fx.Annotate(
 			repository.NewDefaultChannelTplRepo,
 			fx.As(new(repository.ChannelTplRepo)),
+			fx.ParamTags(``, `name:"local_channel_tpl_cache"`, `name:"redis_channel_tpl_cache"`, ``, ``),
 		),
 
 		// callback log repo
@@ -101,6 +148,18 @@ var RepoFxOpt = fx.Module(
 			repository.NewCallbackLogRepo,
 			fx.As(new(repository.CallbackLogRepo)),
 		),
+
+		// callback outbox repo
+		fx.Annotate(
+			repository.NewDefaultCallbackOutboxRepo,
+			fx.As(new(repository.CallbackOutboxRepo)),
+		),
+
+		// delivery receipt repo
+		fx.Annotate(
+			repository.NewDefaultDeliveryReceiptRepo,
+			fx.As(new(repository.DeliveryReceiptRepo)),
+		),
 	),
 )
 
@@ -111,3 +170,49 @@ func InitProviderDao(db *gorm.DB) *dao.DefaultProviderDao {
 	}
 	return dao.NewDefaultProviderDao(db, encryptKey)
 }
+
+// InitLBizConfigCache 初始化业务方配置本地缓存。
+func InitLBizConfigCache(
+	lc fx.Lifecycle,
+	rc goredis.Cmdable,
+	cc *gocache.Cache,
+	bus pubsub.Bus[domain.BizConfigEvent],
+	logger *zap.Logger,
+) *local.LBizConfigCache {
+	type config struct {
+		ReconcileInterval int `mapstructure:"reconcile_interval"` // 单位：毫秒
+	}
+
+	cfg := config{}
+	if err := viper.UnmarshalKey("biz_config_cache", &cfg); err != nil {
+		panic(err)
+	}
+
+	bizConfigCache := local.NewLBizConfigCache(
+		rc, cc, bus, time.Duration(cfg.ReconcileInterval)*time.Millisecond, prometheus.DefaultRegisterer, logger,
+	)
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			return bizConfigCache.Start()
+		},
+		OnStop: func(ctx context.Context) error {
+			return bizConfigCache.Stop()
+		},
+	})
+	return bizConfigCache
+}
+
+// InitLChannelTplCache 初始化渠道模板本地缓存。
+func InitLChannelTplCache(
+	lc fx.Lifecycle,
+	cc *gocache.Cache,
+	bus pubsub.Bus[domain.ChannelTplEvent],
+) *local.LChannelTplCache {
+	tplCache := local.NewLChannelTplCache(cc, bus)
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			return tplCache.Start()
+		},
+	})
+	return tplCache
+}
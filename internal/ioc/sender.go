@@ -0,0 +1,133 @@
+package ioc
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/JrMarcco/easy-kit/pool"
+	"github.com/JrMarcco/kuryr/internal/domain"
+	"github.com/JrMarcco/kuryr/internal/repository"
+	"github.com/JrMarcco/kuryr/internal/service/channel"
+	"github.com/JrMarcco/kuryr/internal/service/ports"
+	"github.com/JrMarcco/kuryr/internal/service/provider"
+	"github.com/JrMarcco/kuryr/internal/service/provider/selector"
+	"github.com/JrMarcco/kuryr/internal/service/provider/sms"
+	"github.com/JrMarcco/kuryr/internal/service/sender"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spf13/viper"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	smschannel "github.com/JrMarcco/kuryr/internal/service/channel/sms"
+)
+
+var SenderFxOpt = fx.Module(
+	"sender",
+	fx.Provide(
+		// sms provider selector builder
+		fx.Annotate(
+			InitSmsSelectorBuilder,
+			fx.As(new(provider.SelectorBuilder)),
+			fx.ResultTags(`name:"sms_selector_builder"`),
+		),
+
+		// channel sender
+		fx.Annotate(
+			InitChannelSender,
+			fx.As(new(ports.ChannelSender)),
+			fx.ParamTags(`name:"sms_selector_builder"`),
+		),
+
+		// sender task pool
+		fx.Annotate(
+			InitSenderTaskPool,
+			fx.As(new(pool.TaskPool)),
+		),
+
+		// notification sender
+		fx.Annotate(
+			InitNotificationSender,
+			fx.As(new(ports.NotificationSender)),
+		),
+	),
+)
+
+// InitSmsSelectorBuilder 初始化短信供应商选择器，按顺序选择启用中的短信供应商，
+// 并跳过没有为消息模板关联或者审批未通过的供应商。
+func InitSmsSelectorBuilder(
+	providerRepo repository.ProviderRepo,
+	tplRepo repository.ChannelTplRepo,
+	smsClients *sms.ClientManager,
+) *selector.ApprovedSelectorBuilder {
+	ctx := context.Background()
+	providers, err := providerRepo.FindByChannel(ctx, strconv.Itoa(int(domain.ChannelSms)))
+	if err != nil {
+		panic(err)
+	}
+
+	smsProviders := make([]provider.Provider, 0, len(providers))
+	for _, p := range providers {
+		smsClient, err := smsClients.Get(ctx, p.Id)
+		if err != nil {
+			panic(err)
+		}
+		smsProviders = append(smsProviders, sms.NewProvider(p.Id, p.ProviderName, smsClient, providerRepo, tplRepo))
+	}
+	return selector.NewApprovedSelectorBuilder(selector.NewSeqSelectorBuilder(smsProviders), tplRepo)
+}
+
+// InitChannelSender 初始化渠道分发器。
+func InitChannelSender(smsSelectorBuilder provider.SelectorBuilder) *channel.Dispatcher {
+	return channel.NewDispatcher(map[domain.Channel]ports.ChannelSender{
+		domain.ChannelSms: smschannel.NewSmsSender(smsSelectorBuilder),
+	})
+}
+
+// InitNotificationSender 初始化消息发送器，依次装饰指标收集与链路追踪。
+func InitNotificationSender(
+	notificationRepo repository.NotificationRepo,
+	channelSender ports.ChannelSender,
+	taskPool pool.TaskPool,
+	logger *zap.Logger,
+) *sender.TracingSender {
+	defaultSender := sender.NewDefaultSender(notificationRepo, channelSender, taskPool, logger)
+	return sender.NewTracingSender(sender.NewMetricsSender(defaultSender, prometheus.DefaultRegisterer))
+}
+
+// InitSenderTaskPool 初始化批量发送使用的任务池。
+func InitSenderTaskPool(lc fx.Lifecycle) *pool.BlockTaskPool {
+	type config struct {
+		InitGoroutines int32 `mapstructure:"init_goroutines"`
+		QueueSize      int32 `mapstructure:"queue_size"`
+	}
+
+	cfg := config{}
+	if err := viper.UnmarshalKey("sender.task_pool", &cfg); err != nil {
+		panic(err)
+	}
+
+	taskPool, err := pool.NewBlockTaskPool(cfg.InitGoroutines, cfg.QueueSize)
+	if err != nil {
+		panic(err)
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			return taskPool.Start()
+		},
+		OnStop: func(ctx context.Context) error {
+			// 等待已提交的发送任务执行完成。
+			done, err := taskPool.Shutdown()
+			if err != nil {
+				return err
+			}
+			select {
+			case <-done:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	})
+	return taskPool
+}
@@ -1,13 +1,26 @@
 package ioc
 
 import (
+	"net/http"
+	"time"
+
+	"github.com/JrMarcco/kuryr/internal/domain"
+	"github.com/JrMarcco/kuryr/internal/pkg/mq"
 	"github.com/JrMarcco/kuryr/internal/pkg/secret"
 	"github.com/JrMarcco/kuryr/internal/pkg/secret/base64"
+	"github.com/JrMarcco/kuryr/internal/repository"
 	"github.com/JrMarcco/kuryr/internal/service/bizconf"
 	"github.com/JrMarcco/kuryr/internal/service/bizinfo"
 	"github.com/JrMarcco/kuryr/internal/service/callback"
 	"github.com/JrMarcco/kuryr/internal/service/provider"
+	"github.com/JrMarcco/kuryr/internal/service/provider/sms"
+	"github.com/JrMarcco/kuryr/internal/service/provider/sms/client"
+	"github.com/JrMarcco/kuryr/internal/service/quota"
 	"github.com/JrMarcco/kuryr/internal/service/sendstrategy"
+	"github.com/JrMarcco/kuryr/internal/service/template"
+	"github.com/JrMarcco/kuryr/internal/service/vendorevent"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spf13/viper"
 	"go.uber.org/fx"
 )
 
@@ -38,10 +51,41 @@ var ServiceFxOpt = fx.Module(
 			fx.As(new(provider.Service)),
 		),
 
+		// sms client manager
+		InitSmsClientManager,
+
+		// quota service
+		fx.Annotate(
+			quota.NewRedisService,
+			fx.As(new(quota.Service)),
+		),
+
+		// channel template service
+		fx.Annotate(
+			InitTemplateService,
+			fx.As(new(template.Service)),
+		),
+
+		// callback circuit breakers
+		InitCallbackCircuitBreakers,
+
+		// callback webhook http client
+		fx.Annotate(
+			InitCallbackWebhookClient,
+			fx.ResultTags(`name:"callback_webhook_client"`),
+		),
+
 		// callback service
 		fx.Annotate(
 			callback.NewDefaultService,
 			fx.As(new(callback.Service)),
+			fx.ParamTags(``, `name:"callback_webhook_client"`, `name:"cbl_sharding_strategy"`),
+		),
+
+		// vendor event service
+		fx.Annotate(
+			vendorevent.NewDefaultService,
+			fx.As(new(vendorevent.Service)),
 		),
 
 		// default send strategy
@@ -65,5 +109,93 @@ var ServiceFxOpt = fx.Module(
 			fx.ParamTags(`name:"default_send_strategy"`, `name:"immediate_send_strategy"`),
 			fx.ResultTags(`name:"send_strategy_dispatcher"`),
 		),
+
+		// template param validate send strategy
+		fx.Annotate(
+			sendstrategy.NewTplValidateStrategy,
+			fx.ParamTags(`name:"send_strategy_dispatcher"`),
+		),
 	),
 )
+
+func InitTemplateService(
+	repo repository.ChannelTplRepo,
+	providerRepo repository.ProviderRepo,
+	quotaSvc quota.Service,
+	bindProducer mq.Producer[domain.TplProviderBindEvent],
+	smsClients *sms.ClientManager,
+) *template.DefaultService {
+	type config struct {
+		ReviewCooldown int      `mapstructure:"review_cooldown"` // 审批拒绝后重新提交审批的冷却时间，单位：毫秒
+		TestReceivers  []string `mapstructure:"test_receivers"`  // 测试发送允许的接收者
+	}
+
+	cfg := config{}
+	if err := viper.UnmarshalKey("template", &cfg); err != nil {
+		panic(err)
+	}
+	return template.NewDefaultService(
+		repo,
+		providerRepo,
+		quotaSvc,
+		bindProducer,
+		smsClients,
+		time.Duration(cfg.ReviewCooldown)*time.Millisecond,
+		cfg.TestReceivers,
+	)
+}
+
+// InitCallbackWebhookClient 初始化回调业务方 webhook 使用的 http 客户端。
+//
+// 单次请求的超时时间由业务方的 webhook 配置决定，这里的 timeout 只作为兜底。
+func InitCallbackWebhookClient() *http.Client {
+	type config struct {
+		Timeout             int `mapstructure:"timeout"` // 单位：毫秒
+		MaxIdleConns        int `mapstructure:"max_idle_conns"`
+		MaxIdleConnsPerHost int `mapstructure:"max_idle_conns_per_host"`
+		IdleConnTimeout     int `mapstructure:"idle_conn_timeout"` // 单位：毫秒
+	}
+
+	cfg := config{}
+	if err := viper.UnmarshalKey("callback.webhook", &cfg); err != nil {
+		panic(err)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = cfg.MaxIdleConns
+	transport.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+	transport.IdleConnTimeout = time.Duration(cfg.IdleConnTimeout) * time.Millisecond
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   time.Duration(cfg.Timeout) * time.Millisecond,
+		// 不跟随重定向，避免回调请求被转发到非预期的地址。
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// InitSmsClientManager 初始化短信客户端管理，模板同步、测试发送以及消息发送共用。
+func InitSmsClientManager(providerRepo repository.ProviderRepo) *sms.ClientManager {
+	return sms.NewClientManager(providerRepo, client.NewSmsClient)
+}
+
+// InitCallbackCircuitBreakers 初始化按业务方隔离的回调熔断器。
+func InitCallbackCircuitBreakers() *callback.CircuitBreakers {
+	type config struct {
+		FailureThreshold int `mapstructure:"failure_threshold"`
+		SuccessThreshold int `mapstructure:"success_threshold"`
+		CoolDownPeriod   int `mapstructure:"cool_down_period"` // 单位：毫秒
+	}
+
+	cfg := config{}
+	if err := viper.UnmarshalKey("callback.circuit_breaker", &cfg); err != nil {
+		panic(err)
+	}
+	return callback.NewCircuitBreakers(callback.CircuitBreakerConfig{
+		FailureThreshold: cfg.FailureThreshold,
+		SuccessThreshold: cfg.SuccessThreshold,
+		CoolDownPeriod:   time.Duration(cfg.CoolDownPeriod) * time.Millisecond,
+	}, prometheus.DefaultRegisterer)
+}
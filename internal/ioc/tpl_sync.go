@@ -0,0 +1,111 @@
+package ioc
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/JrMarcco/kuryr/internal/domain"
+	"github.com/JrMarcco/kuryr/internal/pkg/election"
+	"github.com/JrMarcco/kuryr/internal/pkg/mq"
+	"github.com/JrMarcco/kuryr/internal/repository"
+	"github.com/JrMarcco/kuryr/internal/service/provider/sms"
+	"github.com/JrMarcco/kuryr/internal/service/tplsync"
+	"github.com/spf13/viper"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+var TplSyncFxOpt = fx.Module(
+	"tpl-sync",
+	fx.Provide(InitTplSyncWorker),
+	fx.Invoke(func(*tplsync.Worker) {}),
+)
+
+// InitTplSyncWorker 初始化模板供应商同步任务。
+func InitTplSyncWorker(
+	lc fx.Lifecycle,
+	producer *mq.AsyncProducer,
+	tplRepo repository.ChannelTplRepo,
+	smsClients *sms.ClientManager,
+	etcdClient *clientv3.Client,
+	logger *zap.Logger,
+) *tplsync.Worker {
+	type consumerConfig struct {
+		Concurrency   int   `mapstructure:"concurrency"`
+		RetryBackoffs []int `mapstructure:"retry_backoffs"` // 单位：毫秒
+	}
+
+	type config struct {
+		GroupId         string         `mapstructure:"group_id"`
+		ElectionKey     string         `mapstructure:"election_key"`
+		SessionTTL      int            `mapstructure:"session_ttl"`       // 单位：秒
+		Interval        int            `mapstructure:"interval"`          // 补偿任务执行间隔，单位：毫秒
+		SubmitLease     int            `mapstructure:"submit_lease"`      // 提交权占用时长，单位：毫秒
+		MaxPollInterval int            `mapstructure:"max_poll_interval"` // 审核状态查询的最大退避间隔，单位：毫秒
+		Consumer        consumerConfig `mapstructure:"consumer"`
+	}
+
+	cfg := config{}
+	if err := viper.UnmarshalKey("tpl_sync", &cfg); err != nil {
+		panic(err)
+	}
+
+	var topic string
+	if err := viper.UnmarshalKey("kafka.topics.tpl_provider_bind", &topic); err != nil {
+		panic(err)
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		panic(err)
+	}
+
+	interval := time.Duration(cfg.Interval) * time.Millisecond
+	worker := tplsync.NewWorker(
+		tplRepo,
+		smsClients,
+		election.NewElector(etcdClient, cfg.ElectionKey, fmt.Sprintf("%s-%d", hostname, os.Getpid()), cfg.SessionTTL, interval, logger),
+		interval,
+		time.Duration(cfg.SubmitLease)*time.Millisecond,
+		time.Duration(cfg.MaxPollInterval)*time.Millisecond,
+		logger,
+	)
+
+	retryBackoffs := make([]time.Duration, 0, len(cfg.Consumer.RetryBackoffs))
+	for _, backoff := range cfg.Consumer.RetryBackoffs {
+		retryBackoffs = append(retryBackoffs, time.Duration(backoff)*time.Millisecond)
+	}
+	consumer := mq.NewGeneralConsumer[domain.TplProviderBindEvent](
+		newKafkaConsumer(cfg.GroupId),
+		producer,
+		mq.ConsumerConfig{
+			Topic:         topic,
+			Concurrency:   cfg.Consumer.Concurrency,
+			RetryBackoffs: retryBackoffs,
+		},
+		worker.Submit,
+		logger,
+	)
+
+	// 先启动 worker 再开始消费，停止时先停止消费 ( OnStop 按注册的逆序执行 )。
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			return worker.Start()
+		},
+		OnStop: func(ctx context.Context) error {
+			return worker.Stop()
+		},
+	})
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			return consumer.Start()
+		},
+		OnStop: func(ctx context.Context) error {
+			return consumer.Stop()
+		},
+	})
+	return worker
+}
@@ -0,0 +1,90 @@
+package election
+
+import (
+	"context"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/client/v3/concurrency"
+	"go.uber.org/zap"
+)
+
+// LeadFunc 成为 leader 后执行的任务。
+// 需要在 ctx 取消或者 sessionDone 关闭 ( etcd session 失效，失去 leader 身份 ) 时返回。
+type LeadFunc func(ctx context.Context, sessionDone <-chan struct{})
+
+// Elector 基于 etcd 的选主。
+//
+// 多副本部署时只有 leader 执行任务，失去 leader 身份后重新参与选主。
+type Elector struct {
+	etcdClient    *clientv3.Client
+	electionKey   string
+	candidate     string
+	sessionTTL    int // 单位：秒
+	retryInterval time.Duration
+
+	logger *zap.Logger
+}
+
+// Run 参与选主，成为 leader 后执行 lead，直到 ctx 取消。
+func (e *Elector) Run(ctx context.Context, lead LeadFunc) {
+	for {
+		if err := e.campaignAndLead(ctx, lead); err != nil && ctx.Err() == nil {
+			e.logger.Error("[kuryr] failed to campaign", zap.String("election_key", e.electionKey), zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(e.retryInterval):
+		}
+	}
+}
+
+func (e *Elector) campaignAndLead(ctx context.Context, lead LeadFunc) error {
+	session, err := concurrency.NewSession(
+		e.etcdClient,
+		concurrency.WithTTL(e.sessionTTL),
+		concurrency.WithContext(ctx),
+	)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = session.Close() }()
+
+	election := concurrency.NewElection(session, e.electionKey)
+	if err = election.Campaign(ctx, e.candidate); err != nil {
+		return err
+	}
+
+	e.logger.Info("[kuryr] became leader", zap.String("election_key", e.electionKey), zap.String("candidate", e.candidate))
+
+	lead(ctx, session.Done())
+
+	// 任务停止时 ctx 已经取消，这里使用独立的超时 context 主动放弃 leader。
+	resignCtx, cancel := context.WithTimeout(context.Background(), time.Duration(e.sessionTTL)*time.Second)
+	defer cancel()
+
+	if err = election.Resign(resignCtx); err != nil {
+		e.logger.Warn("[kuryr] failed to resign leader", zap.String("election_key", e.electionKey), zap.Error(err))
+	}
+	return nil
+}
+
+func NewElector(
+	etcdClient *clientv3.Client,
+	electionKey string,
+	candidate string,
+	sessionTTL int,
+	retryInterval time.Duration,
+	logger *zap.Logger,
+) *Elector {
+	return &Elector{
+		etcdClient:    etcdClient,
+		electionKey:   electionKey,
+		candidate:     candidate,
+		sessionTTL:    sessionTTL,
+		retryInterval: retryInterval,
+		logger:        logger,
+	}
+}
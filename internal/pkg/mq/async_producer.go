@@ -0,0 +1,178 @@
+package mq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"go.uber.org/zap"
+)
+
+const queueFullBackoff = 100 * time.Millisecond
+
+var errProducerClosed = errors.New("[kuryr] kafka producer is closed")
+
+var _ KafkaProducer = (*AsyncProducer)(nil)
+
+// DeliveryFuture 异步发送的投递结果。
+type DeliveryFuture struct {
+	done chan struct{}
+
+	tp  kafka.TopicPartition
+	err error
+}
+
+// Done 投递完成 ( 成功或者失败 ) 后关闭。
+func (f *DeliveryFuture) Done() <-chan struct{} {
+	return f.done
+}
+
+// Wait 等待投递结果，返回消息写入的分区与 offset。
+func (f *DeliveryFuture) Wait(ctx context.Context) (kafka.TopicPartition, error) {
+	select {
+	case <-ctx.Done():
+		return kafka.TopicPartition{}, ctx.Err()
+	case <-f.done:
+		return f.tp, f.err
+	}
+}
+
+func (f *DeliveryFuture) complete(tp kafka.TopicPartition, err error) {
+	f.tp = tp
+	f.err = err
+	close(f.done)
+}
+
+// AsyncProducer 异步 kafka 生产者。
+//
+// 所有 topic 共用同一个底层生产者，消息按 linger / batch 配置在客户端攒批发送，不再逐条 Flush。
+// 投递报告统一由一个事件循环 goroutine 处理，通过 kafka.Message.Opaque 找到对应的 DeliveryFuture。
+type AsyncProducer struct {
+	producer KafkaEventProducer
+
+	mu      sync.Mutex
+	pending map[*DeliveryFuture]struct{}
+	closed  bool
+
+	wg sync.WaitGroup
+
+	logger *zap.Logger
+}
+
+func (p *AsyncProducer) Start() error {
+	p.wg.Add(1)
+	go p.loop()
+	return nil
+}
+
+// Stop 等待队列中的消息投递完成后关闭生产者，ctx 超时后未完成的投递直接返回失败。
+func (p *AsyncProducer) Stop(ctx context.Context) error {
+	p.mu.Lock()
+	p.closed = true
+	p.mu.Unlock()
+
+	remaining := p.Flush(queueFullBackoff)
+	for remaining > 0 && ctx.Err() == nil {
+		remaining = p.Flush(queueFullBackoff)
+	}
+	if remaining > 0 {
+		p.logger.Warn("[kuryr] kafka producer stopped with unflushed messages", zap.Int("unflushed", remaining))
+	}
+
+	// 关闭后 Events channel 随之关闭，事件循环退出。
+	p.producer.Close()
+	p.wg.Wait()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for future := range p.pending {
+		future.complete(kafka.TopicPartition{}, errProducerClosed)
+		delete(p.pending, future)
+	}
+	return nil
+}
+
+// ProduceAsync 异步发送消息，本地队列满时等待后重试，直到 ctx 超时。
+func (p *AsyncProducer) ProduceAsync(ctx context.Context, msg *kafka.Message) (*DeliveryFuture, error) {
+	future := &DeliveryFuture{done: make(chan struct{})}
+	msg.Opaque = future
+
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil, errProducerClosed
+	}
+	p.pending[future] = struct{}{}
+	p.mu.Unlock()
+
+	for {
+		err := p.producer.Produce(msg, nil)
+		if err == nil {
+			return future, nil
+		}
+
+		var kafkaErr kafka.Error
+		if errors.As(err, &kafkaErr) && kafkaErr.Code() == kafka.ErrQueueFull {
+			select {
+			case <-ctx.Done():
+				err = ctx.Err()
+			case <-time.After(queueFullBackoff):
+				continue
+			}
+		}
+
+		p.mu.Lock()
+		delete(p.pending, future)
+		p.mu.Unlock()
+		return nil, fmt.Errorf("[kuryr] failed to produce message to topic [ %s ]: %w", *msg.TopicPartition.Topic, err)
+	}
+}
+
+// Produce 直接调用底层生产者，投递报告写入 deliveryChan。
+func (p *AsyncProducer) Produce(msg *kafka.Message, deliveryChan chan kafka.Event) error {
+	return p.producer.Produce(msg, deliveryChan)
+}
+
+// Flush 等待队列中的消息投递完成，返回仍未投递的消息数。
+func (p *AsyncProducer) Flush(timeout time.Duration) int {
+	return p.producer.Flush(int(timeout.Milliseconds()))
+}
+
+// loop 处理投递报告与生产者错误。
+func (p *AsyncProducer) loop() {
+	defer p.wg.Done()
+
+	for e := range p.producer.Events() {
+		switch ev := e.(type) {
+		case *kafka.Message:
+			future, ok := ev.Opaque.(*DeliveryFuture)
+			if !ok {
+				continue
+			}
+
+			p.mu.Lock()
+			_, pending := p.pending[future]
+			delete(p.pending, future)
+			p.mu.Unlock()
+
+			if pending {
+				future.complete(ev.TopicPartition, ev.TopicPartition.Error)
+			}
+		case kafka.Error:
+			p.logger.Error("[kuryr] kafka producer error", zap.Error(ev))
+		default:
+			p.logger.Debug("[kuryr] ignored kafka producer event", zap.String("event", e.String()))
+		}
+	}
+}
+
+func NewAsyncProducer(producer KafkaEventProducer, logger *zap.Logger) *AsyncProducer {
+	return &AsyncProducer{
+		producer: producer,
+		pending:  make(map[*DeliveryFuture]struct{}),
+		logger:   logger,
+	}
+}
@@ -0,0 +1,435 @@
+package mq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	HeaderRetryCount  = "x-kuryr-retry-count"  // 已重试次数
+	HeaderRetryAt     = "x-kuryr-retry-at"     // 重试时间 ( Unix 毫秒值 )
+	HeaderOriginTopic = "x-kuryr-origin-topic" // 原始 topic
+	HeaderError       = "x-kuryr-error"        // 最后一次处理失败的原因，只在死信消息中设置
+
+	readTimeout          = 100 * time.Millisecond
+	forwardRetryInterval = time.Second
+)
+
+// ErrNonRetryable handler 返回的错误包含该错误时，消息不再重试，直接投递到死信 topic。
+var ErrNonRetryable = errors.New("[kuryr] non-retryable message")
+
+var _ Consumer[any] = (*GeneralConsumer[any])(nil)
+
+// ConsumerConfig 消费者配置。
+type ConsumerConfig struct {
+	Topic       string
+	Concurrency int // 每个分区的最大并发数
+
+	// RetryBackoffs 每一级重试 topic 的延迟时间，长度即最大重试次数，为空时处理失败直接投递死信 topic。
+	// 第 i 级重试 topic 为 <Topic>_retry_<i>。
+	RetryBackoffs []time.Duration
+	// DeadLetterTopic 死信 topic，为空时使用 <Topic>_dlq。
+	DeadLetterTopic string
+}
+
+func (c ConsumerConfig) retryTopic(level int) string {
+	return fmt.Sprintf("%s_retry_%d", c.Topic, level)
+}
+
+func (c ConsumerConfig) deadLetterTopic() string {
+	if c.DeadLetterTopic != "" {
+		return c.DeadLetterTopic
+	}
+	return c.Topic + "_dlq"
+}
+
+// GeneralConsumer 通用的 kafka 消费者，消息以 json 格式解码。
+//
+//   - 关闭自动提交，同一分区的一批消息全部处理完成 ( 成功或者已经投递到重试 / 死信 topic ) 后才提交 offset。
+//   - 每个分区一个 worker，每批最多并发处理 Concurrency 条消息。
+//   - 处理失败的消息按重试次数投递到对应的重试 topic，延迟时间到达后重新处理，重试耗尽后投递到死信 topic。
+//   - 未到重试时间的重试消息暂停所在分区并回退 offset，到达重试时间后恢复拉取，不阻塞 poll。
+//   - 处理失败且错误包含 ErrNonRetryable 的消息直接投递到死信 topic。
+//   - 无法解码的消息直接投递到死信 topic。
+type GeneralConsumer[T any] struct {
+	consumer KafkaConsumer
+	producer KafkaProducer
+	cfg      ConsumerConfig
+	handler  Handler[T]
+
+	mu      sync.Mutex
+	workers map[string]*partitionWorker // topic + partition -> worker
+	paused  map[string]*pausedPartition // topic + partition -> 等待重试时间的暂停分区
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	logger *zap.Logger
+}
+
+type partitionWorker struct {
+	msgs chan *kafka.Message
+	stop chan struct{}
+	done chan struct{}
+}
+
+type pausedPartition struct {
+	tp       kafka.TopicPartition
+	resumeAt time.Time
+}
+
+func (c *GeneralConsumer[T]) Start() error {
+	topics := make([]string, 0, len(c.cfg.RetryBackoffs)+1)
+	topics = append(topics, c.cfg.Topic)
+	for i := range c.cfg.RetryBackoffs {
+		topics = append(topics, c.cfg.retryTopic(i))
+	}
+
+	if err := c.consumer.SubscribeTopics(topics, c.rebalance); err != nil {
+		return fmt.Errorf("[kuryr] failed to subscribe topics %v: %w", topics, err)
+	}
+
+	c.wg.Add(1)
+	go c.poll()
+	return nil
+}
+
+// Stop 停止拉取消息，等待正在处理的消息完成后关闭消费者。
+// 未处理的消息不会提交 offset，重启后重新消费。
+func (c *GeneralConsumer[T]) Stop() error {
+	c.cancel()
+	c.wg.Wait()
+
+	c.mu.Lock()
+	for key, worker := range c.workers {
+		close(worker.stop)
+		<-worker.done
+		delete(c.workers, key)
+	}
+	c.mu.Unlock()
+
+	return c.consumer.Close()
+}
+
+func (c *GeneralConsumer[T]) poll() {
+	defer c.wg.Done()
+
+	for c.ctx.Err() == nil {
+		c.resumeDue()
+
+		msg, err := c.consumer.ReadMessage(readTimeout)
+		if err != nil {
+			var kafkaErr kafka.Error
+			if errors.As(err, &kafkaErr) && kafkaErr.IsTimeout() {
+				continue
+			}
+			c.logger.Error("[kuryr] failed to read message", zap.String("topic", c.cfg.Topic), zap.Error(err))
+			continue
+		}
+
+		// 重试消息等到重试时间后再处理。
+		if retryAt := time.UnixMilli(headerInt64(msg, HeaderRetryAt)); time.Now().Before(retryAt) && c.pause(msg, retryAt) {
+			continue
+		}
+
+		worker := c.worker(msg.TopicPartition)
+		select {
+		case worker.msgs <- msg:
+		case <-c.ctx.Done():
+			return
+		}
+	}
+}
+
+// pause 暂停消息所在的分区并回退到该消息，到达 resumeAt 后由 poll 恢复拉取。
+//
+// 同一重试 topic 的消息延迟相同，分区内靠前的消息总是先到达重试时间，暂停整个分区不会推迟后面的消息。
+// 暂停或者回退失败时返回 false，消息提前处理，避免跳过该消息。
+func (c *GeneralConsumer[T]) pause(msg *kafka.Message, resumeAt time.Time) bool {
+	tp := msg.TopicPartition
+	if err := c.consumer.Pause([]kafka.TopicPartition{tp}); err != nil {
+		c.logger.Error("[kuryr] failed to pause partition", zap.String("topic", *tp.Topic), zap.Int32("partition", tp.Partition), zap.Error(err))
+		return false
+	}
+
+	seeked, err := c.consumer.SeekPartitions([]kafka.TopicPartition{tp})
+	if err == nil && len(seeked) > 0 {
+		err = seeked[0].Error
+	}
+	if err != nil {
+		c.logger.Error("[kuryr] failed to seek partition", zap.String("topic", *tp.Topic), zap.Int32("partition", tp.Partition), zap.Error(err))
+		if err := c.consumer.Resume([]kafka.TopicPartition{tp}); err != nil {
+			c.logger.Error("[kuryr] failed to resume partition", zap.String("topic", *tp.Topic), zap.Int32("partition", tp.Partition), zap.Error(err))
+		}
+		return false
+	}
+
+	c.mu.Lock()
+	c.paused[partitionKey(tp)] = &pausedPartition{tp: tp, resumeAt: resumeAt}
+	c.mu.Unlock()
+	return true
+}
+
+// resumeDue 恢复到达重试时间的暂停分区，恢复失败时下一轮 poll 再次尝试。
+func (c *GeneralConsumer[T]) resumeDue() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for key, paused := range c.paused {
+		if now.Before(paused.resumeAt) {
+			continue
+		}
+		if err := c.consumer.Resume([]kafka.TopicPartition{paused.tp}); err != nil {
+			c.logger.Error("[kuryr] failed to resume partition", zap.String("topic", *paused.tp.Topic), zap.Int32("partition", paused.tp.Partition), zap.Error(err))
+			continue
+		}
+		delete(c.paused, key)
+	}
+}
+
+// rebalance 分区被回收时停止对应的 worker，保证回收前已经处理完成的消息提交 offset。
+// 被回收的暂停分区不再恢复，重新分配后从已提交的 offset 拉取。
+func (c *GeneralConsumer[T]) rebalance(_ *kafka.Consumer, event kafka.Event) error {
+	revoked, ok := event.(kafka.RevokedPartitions)
+	if !ok {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, tp := range revoked.Partitions {
+		key := partitionKey(tp)
+		delete(c.paused, key)
+		if worker, ok := c.workers[key]; ok {
+			close(worker.stop)
+			<-worker.done
+			delete(c.workers, key)
+		}
+	}
+	return nil
+}
+
+func (c *GeneralConsumer[T]) worker(tp kafka.TopicPartition) *partitionWorker {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := partitionKey(tp)
+	if worker, ok := c.workers[key]; ok {
+		return worker
+	}
+
+	worker := &partitionWorker{
+		msgs: make(chan *kafka.Message, c.cfg.Concurrency),
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	c.workers[key] = worker
+
+	go c.work(worker)
+	return worker
+}
+
+func (c *GeneralConsumer[T]) work(worker *partitionWorker) {
+	defer close(worker.done)
+
+	for {
+		var msg *kafka.Message
+		select {
+		case <-worker.stop:
+			return
+		case msg = <-worker.msgs:
+		}
+
+		batch := []*kafka.Message{msg}
+	drain:
+		for len(batch) < c.cfg.Concurrency {
+			select {
+			case msg = <-worker.msgs:
+				batch = append(batch, msg)
+			default:
+				break drain
+			}
+		}
+
+		if !c.processBatch(worker.stop, batch) {
+			return
+		}
+	}
+}
+
+// processBatch 并发处理同一分区的一批消息，全部完成后提交 offset。
+// 消费者停止导致消息未处理完成时返回 false，不提交 offset。
+func (c *GeneralConsumer[T]) processBatch(stop <-chan struct{}, batch []*kafka.Message) bool {
+	stopCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-stop:
+			cancel()
+		case <-stopCtx.Done():
+		}
+	}()
+
+	var eg errgroup.Group
+	for _, msg := range batch {
+		eg.Go(func() error {
+			return c.process(stopCtx, msg)
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return false
+	}
+
+	last := batch[len(batch)-1].TopicPartition
+	last.Offset++
+	if _, err := c.consumer.CommitOffsets([]kafka.TopicPartition{last}); err != nil {
+		// 提交失败时消息会被重新消费，handler 需要保证幂等。
+		c.logger.Error("[kuryr] failed to commit offset", zap.String("topic", *last.Topic), zap.Int32("partition", last.Partition), zap.Error(err))
+	}
+	return true
+}
+
+// process 处理单条消息，只有消费者停止时返回错误。
+func (c *GeneralConsumer[T]) process(stopCtx context.Context, msg *kafka.Message) error {
+	retryCount := int(headerInt64(msg, HeaderRetryCount))
+
+	// 正在处理的消息不受消费者停止影响，保证优雅退出。
+	ctx, span := startConsumeSpan(context.Background(), msg)
+	span.SetAttributes(attribute.Int("messaging.kafka.retry_count", retryCount))
+	defer span.End()
+
+	var event T
+	if err := json.Unmarshal(msg.Value, &event); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		c.logger.Error("[kuryr] failed to unmarshal message", zap.ByteString("value", msg.Value), zap.Error(err))
+		return c.forward(ctx, stopCtx, msg, c.cfg.deadLetterTopic(), retryCount, err)
+	}
+
+	err := c.handler(ctx, event)
+	if err == nil {
+		return nil
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+	c.logger.Warn("[kuryr] failed to handle message", zap.String("topic", *msg.TopicPartition.Topic), zap.Int("retry_count", retryCount), zap.Error(err))
+
+	if retryCount < len(c.cfg.RetryBackoffs) && !errors.Is(err, ErrNonRetryable) {
+		return c.forward(ctx, stopCtx, msg, c.cfg.retryTopic(retryCount), retryCount+1, nil)
+	}
+	return c.forward(ctx, stopCtx, msg, c.cfg.deadLetterTopic(), retryCount, err)
+}
+
+// forward 把消息投递到重试或者死信 topic，失败时持续重试直到成功或者消费者停止，
+// 避免跳过投递失败的消息提交 offset。
+// 原消息的业务元数据 header 原样保留，链路上下文替换为 ctx 中的当前 span，重试处理与本次处理串联在同一条链路中。
+func (c *GeneralConsumer[T]) forward(ctx, stopCtx context.Context, msg *kafka.Message, topic string, retryCount int, cause error) error {
+	forwarded := &kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: kafka.PartitionAny},
+		Key:            msg.Key,
+		Value:          msg.Value,
+	}
+	for _, header := range msg.Headers {
+		switch header.Key {
+		case HeaderRetryCount, HeaderRetryAt, HeaderOriginTopic, HeaderError:
+		default:
+			forwarded.Headers = append(forwarded.Headers, header)
+		}
+	}
+
+	setHeader(forwarded, HeaderRetryCount, []byte(strconv.Itoa(retryCount)))
+	setHeader(forwarded, HeaderOriginTopic, []byte(c.cfg.Topic))
+	if cause != nil {
+		setHeader(forwarded, HeaderError, []byte(cause.Error()))
+	} else {
+		retryAt := time.Now().Add(c.cfg.RetryBackoffs[retryCount-1]).UnixMilli()
+		setHeader(forwarded, HeaderRetryAt, []byte(strconv.FormatInt(retryAt, 10)))
+	}
+	InjectTraceContext(ctx, forwarded)
+
+	for {
+		err := c.produce(stopCtx, forwarded)
+		if err == nil {
+			return nil
+		}
+
+		c.logger.Error("[kuryr] failed to forward message", zap.String("topic", topic), zap.Error(err))
+		select {
+		case <-stopCtx.Done():
+			return stopCtx.Err()
+		case <-time.After(forwardRetryInterval):
+		}
+	}
+}
+
+func (c *GeneralConsumer[T]) produce(ctx context.Context, msg *kafka.Message) error {
+	deliveryChan := make(chan kafka.Event, 1)
+	if err := c.producer.Produce(msg, deliveryChan); err != nil {
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case e := <-deliveryChan:
+		switch ev := e.(type) {
+		case *kafka.Message:
+			return ev.TopicPartition.Error
+		case kafka.Error:
+			return ev
+		default:
+			return errors.New(e.String())
+		}
+	}
+}
+
+func partitionKey(tp kafka.TopicPartition) string {
+	topic := ""
+	if tp.Topic != nil {
+		topic = *tp.Topic
+	}
+	return fmt.Sprintf("%s:%d", topic, tp.Partition)
+}
+
+func headerInt64(msg *kafka.Message, key string) int64 {
+	val, _ := strconv.ParseInt(headerValue(msg, key), 10, 64)
+	return val
+}
+
+func NewGeneralConsumer[T any](
+	consumer KafkaConsumer,
+	producer KafkaProducer,
+	cfg ConsumerConfig,
+	handler Handler[T],
+	logger *zap.Logger,
+) *GeneralConsumer[T] {
+	cfg.Concurrency = max(cfg.Concurrency, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &GeneralConsumer[T]{
+		consumer: consumer,
+		producer: producer,
+		cfg:      cfg,
+		handler:  handler,
+		workers:  make(map[string]*partitionWorker),
+		paused:   make(map[string]*pausedPartition),
+		ctx:      ctx,
+		cancel:   cancel,
+		logger:   logger,
+	}
+}
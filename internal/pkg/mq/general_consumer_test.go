@@ -0,0 +1,226 @@
+package mq_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/JrMarcco/kuryr/internal/pkg/mq"
+	mqmock "github.com/JrMarcco/kuryr/internal/pkg/mq/mock"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+type testEvent struct {
+	Id uint64 `json:"id"`
+}
+
+func TestGeneralConsumer(t *testing.T) {
+	t.Parallel()
+
+	const topic = "test_topic"
+
+	tcs := []struct {
+		name       string
+		value      string
+		headers    []kafka.Header
+		handlerErr error
+		wantTopic  string // 期望投递的重试 / 死信 topic，为空表示不投递
+		wantRetry  string // 期望投递消息的重试次数
+	}{
+		{
+			name:  "handle success",
+			value: `{"id":1}`,
+		}, {
+			name:       "retry on failure",
+			value:      `{"id":1}`,
+			handlerErr: errors.New("mock handler error"),
+			wantTopic:  "test_topic_retry_0",
+			wantRetry:  "1",
+		}, {
+			name:  "dead letter after retries exhausted",
+			value: `{"id":1}`,
+			headers: []kafka.Header{
+				{Key: mq.HeaderRetryCount, Value: []byte("2")},
+				{Key: mq.HeaderRetryAt, Value: []byte("1")},
+			},
+			handlerErr: errors.New("mock handler error"),
+			wantTopic:  "test_topic_dlq",
+			wantRetry:  "2",
+		}, {
+			name:       "dead letter on non-retryable error",
+			value:      `{"id":1}`,
+			handlerErr: fmt.Errorf("%w: mock handler error", mq.ErrNonRetryable),
+			wantTopic:  "test_topic_dlq",
+			wantRetry:  "0",
+		}, {
+			name:      "dead letter on invalid payload",
+			value:     `invalid json`,
+			wantTopic: "test_topic_dlq",
+			wantRetry: "0",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			msgTopic := topic
+			msg := &kafka.Message{
+				TopicPartition: kafka.TopicPartition{Topic: &msgTopic, Partition: 0, Offset: 41},
+				Value:          []byte(tc.value),
+				Headers:        tc.headers,
+			}
+
+			consumer := mqmock.NewMockKafkaConsumer(ctrl)
+			consumer.EXPECT().
+				SubscribeTopics([]string{topic, "test_topic_retry_0", "test_topic_retry_1"}, gomock.Any()).
+				Return(nil)
+
+			timeout := kafka.NewError(kafka.ErrTimedOut, "timed out", false)
+			consumer.EXPECT().ReadMessage(gomock.Any()).Return(msg, nil)
+			consumer.EXPECT().ReadMessage(gomock.Any()).Return(nil, timeout).AnyTimes()
+
+			committed := make(chan kafka.TopicPartition, 1)
+			consumer.EXPECT().CommitOffsets(gomock.Any()).
+				DoAndReturn(func(offsets []kafka.TopicPartition) ([]kafka.TopicPartition, error) {
+					committed <- offsets[0]
+					return offsets, nil
+				})
+			consumer.EXPECT().Close().Return(nil)
+
+			producer := mqmock.NewMockKafkaProducer(ctrl)
+			var forwarded *kafka.Message
+			if tc.wantTopic != "" {
+				producer.EXPECT().Produce(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(m *kafka.Message, deliveryChan chan kafka.Event) error {
+						forwarded = m
+						deliveryChan <- m
+						return nil
+					})
+			}
+
+			handler := func(_ context.Context, event testEvent) error {
+				assert.Equal(t, uint64(1), event.Id)
+				return tc.handlerErr
+			}
+
+			c := mq.NewGeneralConsumer[testEvent](consumer, producer, mq.ConsumerConfig{
+				Topic:         topic,
+				Concurrency:   2,
+				RetryBackoffs: []time.Duration{time.Second, time.Minute},
+			}, handler, zap.NewNop())
+			require.NoError(t, c.Start())
+
+			select {
+			case tp := <-committed:
+				assert.Equal(t, kafka.Offset(42), tp.Offset)
+			case <-time.After(5 * time.Second):
+				t.Fatal("offset not committed")
+			}
+			require.NoError(t, c.Stop())
+
+			if tc.wantTopic == "" {
+				return
+			}
+			require.NotNil(t, forwarded)
+			assert.Equal(t, tc.wantTopic, *forwarded.TopicPartition.Topic)
+			assert.Equal(t, tc.value, string(forwarded.Value))
+			var retryCounts int
+			for _, header := range forwarded.Headers {
+				if header.Key == mq.HeaderRetryCount {
+					retryCounts++
+					assert.Equal(t, tc.wantRetry, string(header.Value))
+				}
+			}
+			assert.Equal(t, 1, retryCounts)
+			// 业务元数据随消息一起转发。
+			assert.Equal(t, mq.MetadataFromMessage(msg), mq.MetadataFromMessage(forwarded))
+		})
+	}
+}
+
+func TestGeneralConsumer_RetryDelay(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	const topic = "test_topic"
+
+	retryTopic := "test_topic_retry_0"
+	retryAt := time.UnixMilli(time.Now().Add(300 * time.Millisecond).UnixMilli())
+	msg := &kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &retryTopic, Partition: 0, Offset: 41},
+		Value:          []byte(`{"id":1}`),
+		Headers: []kafka.Header{
+			{Key: mq.HeaderRetryCount, Value: []byte("1")},
+			{Key: mq.HeaderRetryAt, Value: []byte(strconv.FormatInt(retryAt.UnixMilli(), 10))},
+		},
+	}
+
+	consumer := mqmock.NewMockKafkaConsumer(ctrl)
+	consumer.EXPECT().SubscribeTopics(gomock.Any(), gomock.Any()).Return(nil)
+
+	// 未到重试时间时暂停分区并回退到该消息，poll 不阻塞，恢复后重新拉取到该消息。
+	var resumedAt time.Time
+	timeout := kafka.NewError(kafka.ErrTimedOut, "timed out", false)
+	gomock.InOrder(
+		consumer.EXPECT().ReadMessage(gomock.Any()).Return(msg, nil),
+		consumer.EXPECT().Pause([]kafka.TopicPartition{msg.TopicPartition}).Return(nil),
+		consumer.EXPECT().SeekPartitions([]kafka.TopicPartition{msg.TopicPartition}).
+			Return([]kafka.TopicPartition{msg.TopicPartition}, nil),
+		consumer.EXPECT().ReadMessage(gomock.Any()).
+			DoAndReturn(func(d time.Duration) (*kafka.Message, error) {
+				time.Sleep(d)
+				return nil, timeout
+			}).MinTimes(1),
+		consumer.EXPECT().Resume([]kafka.TopicPartition{msg.TopicPartition}).
+			DoAndReturn(func(_ []kafka.TopicPartition) error {
+				resumedAt = time.Now()
+				return nil
+			}),
+		consumer.EXPECT().ReadMessage(gomock.Any()).Return(msg, nil),
+		consumer.EXPECT().ReadMessage(gomock.Any()).Return(nil, timeout).AnyTimes(),
+	)
+
+	committed := make(chan kafka.TopicPartition, 1)
+	consumer.EXPECT().CommitOffsets(gomock.Any()).
+		DoAndReturn(func(offsets []kafka.TopicPartition) ([]kafka.TopicPartition, error) {
+			committed <- offsets[0]
+			return offsets, nil
+		})
+	consumer.EXPECT().Close().Return(nil)
+
+	handled := 0
+	handler := func(_ context.Context, _ testEvent) error {
+		handled++
+		return nil
+	}
+
+	c := mq.NewGeneralConsumer[testEvent](consumer, mqmock.NewMockKafkaProducer(ctrl), mq.ConsumerConfig{
+		Topic:         topic,
+		RetryBackoffs: []time.Duration{time.Second},
+	}, handler, zap.NewNop())
+	require.NoError(t, c.Start())
+
+	select {
+	case tp := <-committed:
+		assert.Equal(t, kafka.Offset(42), tp.Offset)
+	case <-time.After(5 * time.Second):
+		t.Fatal("offset not committed")
+	}
+	require.NoError(t, c.Stop())
+
+	assert.Equal(t, 1, handled)
+	assert.False(t, resumedAt.Before(retryAt))
+}
@@ -3,107 +3,107 @@ package mq
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
-	"time"
 
 	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"go.opentelemetry.io/otel/codes"
+	"go.uber.org/zap"
 )
 
 var _ Producer[any] = (*GeneralProducer[any])(nil)
 
+// GeneralProducer 通用的 kafka 生产者，消息以 json 格式编码。
+// 发送时把 ctx 中的链路上下文与业务元数据写入消息 header，消费侧据此串联链路。
 type GeneralProducer[T any] struct {
 	topic    string
-	producer *kafka.Producer
+	producer *AsyncProducer
+
+	keyFunc      func(event T) []byte
+	metadataFunc func(event T) Metadata
 }
 
-func (p *GeneralProducer[T]) Produce(ctx context.Context, event T) error {
-	data, err := json.Marshal(&event)
+type GeneralProducerOption[T any] func(p *GeneralProducer[T])
+
+// WithKeyFunc 根据消息内容生成消息 key，例如按业务 id 分区保证同一业务方的消息有序。
+func WithKeyFunc[T any](fn func(event T) []byte) GeneralProducerOption[T] {
+	return func(p *GeneralProducer[T]) {
+		p.keyFunc = fn
+	}
+}
+
+// WithMetadataFunc 根据消息内容生成业务元数据，写入标准 header。
+func WithMetadataFunc[T any](fn func(event T) Metadata) GeneralProducerOption[T] {
+	return func(p *GeneralProducer[T]) {
+		p.metadataFunc = fn
+	}
+}
+
+func (p *GeneralProducer[T]) Produce(ctx context.Context, event T, opts ...ProduceOption) error {
+	future, err := p.ProduceAsync(ctx, event, opts...)
 	if err != nil {
 		return err
 	}
 
-	// 创建专用投递 channel
-	deliveryChan := make(chan kafka.Event, 1)
-
-	// 发送消息，处理队列满的情况
-	for {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-			// 没超时就继续执行。
-		}
-
-		err = p.producer.Produce(&kafka.Message{
-			TopicPartition: kafka.TopicPartition{
-				Topic:     &p.topic,
-				Partition: kafka.PartitionAny,
-			},
-			Value: data,
-		}, deliveryChan)
-
-		if err != nil {
-			var kafkaErr kafka.Error
-			ok := errors.As(err, &kafkaErr)
-			if ok && kafkaErr.Code() == kafka.ErrQueueFull {
-				// 队列满，等待 1000ms 后重试
-				select {
-				case <-ctx.Done():
-					return ctx.Err()
-				case <-time.After(time.Second):
-					continue
-				}
-			}
-			return fmt.Errorf("failed to produce message [ %s ] to topic [ %s ]: %w", data, p.topic, err)
-		}
-
-		for {
-			flushed := p.producer.Flush(int(time.Second))
-			if flushed == 0 {
-				break
-			}
-		}
-
-		// 成功提交到队列。
-		break
+	if _, err = future.Wait(ctx); err != nil {
+		return fmt.Errorf("[kuryr] failed to deliver message to topic [ %s ]: %w", p.topic, err)
+	}
+	return nil
+}
+
+func (p *GeneralProducer[T]) ProduceAsync(ctx context.Context, event T, opts ...ProduceOption) (*DeliveryFuture, error) {
+	data, err := json.Marshal(&event)
+	if err != nil {
+		return nil, fmt.Errorf("[kuryr] failed to marshal message: %w", err)
 	}
 
-	// 等待投递报告。
-	select {
-	case <-ctx.Done():
-		return ctx.Err()
-	case e := <-deliveryChan:
-		switch eventType := e.(type) {
-		case *kafka.Message:
-			if eventType.TopicPartition.Error != nil {
-				return fmt.Errorf("[failed to produce message [ %s ] to topic [ %s ]: %w", data, p.topic, eventType.TopicPartition.Error)
-			}
-		case kafka.Error:
-			return errors.New(eventType.Error())
-		default:
-			return errors.New(e.String())
-		}
+	msg := &kafka.Message{
+		TopicPartition: kafka.TopicPartition{
+			Topic:     &p.topic,
+			Partition: kafka.PartitionAny,
+		},
+		Value: data,
+	}
+	if p.keyFunc != nil {
+		msg.Key = p.keyFunc(event)
+	}
+	if p.metadataFunc != nil {
+		WithMetadata(p.metadataFunc(event))(msg)
+	}
+	for _, opt := range opts {
+		opt(msg)
 	}
 
-	return nil
+	// 异步发送时 span 只覆盖入队过程，投递结果由调用方通过 DeliveryFuture 获取。
+	span := startProduceSpan(ctx, msg)
+	defer span.End()
+
+	future, err := p.producer.ProduceAsync(ctx, msg)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return future, err
 }
 
-func (p *GeneralProducer[T]) Close() {
-	// 等待所有消息发送完成。
-	for {
-		flushed := p.producer.Flush(int(time.Second))
-		if flushed == 0 {
-			break
-		}
+// Close 等待已发送的消息投递完成，ctx 结束后不再等待并记录仍未投递的消息数。
+// 底层生产者由所有 topic 共用，其生命周期由 AsyncProducer 管理，这里不会关闭。
+func (p *GeneralProducer[T]) Close(ctx context.Context) {
+	remaining := p.producer.Flush(queueFullBackoff)
+	for remaining > 0 && ctx.Err() == nil {
+		remaining = p.producer.Flush(queueFullBackoff)
+	}
+	if remaining > 0 {
+		p.producer.logger.Warn("[kuryr] kafka producer closed with unflushed messages", zap.String("topic", p.topic), zap.Int("unflushed", remaining))
 	}
-	// 关闭生产者
-	p.producer.Close()
 }
 
-func NewGeneralProducer[T any](topic string, producer *kafka.Producer) *GeneralProducer[T] {
-	return &GeneralProducer[T]{
+func NewGeneralProducer[T any](topic string, producer *AsyncProducer, opts ...GeneralProducerOption[T]) *GeneralProducer[T] {
+	p := &GeneralProducer[T]{
 		topic:    topic,
 		producer: producer,
 	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
 }
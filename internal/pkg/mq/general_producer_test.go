@@ -0,0 +1,205 @@
+package mq_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/JrMarcco/kuryr/internal/pkg/mq"
+	mqmock "github.com/JrMarcco/kuryr/internal/pkg/mq/mock"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestGeneralProducer_ProduceAsync(t *testing.T) {
+	t.Parallel()
+
+	const topic = "test_topic"
+
+	tcs := []struct {
+		name        string
+		deliveryErr error
+		wantErr     bool
+	}{
+		{name: "delivered"},
+		{name: "delivery failed", deliveryErr: kafka.NewError(kafka.ErrMsgTimedOut, "message timed out", false), wantErr: true},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			events := make(chan kafka.Event, 1)
+			kp := mqmock.NewMockKafkaEventProducer(ctrl)
+			kp.EXPECT().Events().Return(events)
+			kp.EXPECT().Produce(gomock.Any(), gomock.Nil()).
+				DoAndReturn(func(msg *kafka.Message, _ chan kafka.Event) error {
+					assert.Equal(t, topic, *msg.TopicPartition.Topic)
+					assert.Equal(t, "1", string(msg.Key))
+					assert.Equal(t, []kafka.Header{{Key: "trace", Value: []byte("id")}}, msg.Headers)
+
+					// 模拟 librdkafka 异步回写投递报告。
+					delivered := *msg
+					delivered.TopicPartition.Partition = 3
+					delivered.TopicPartition.Offset = 42
+					delivered.TopicPartition.Error = tc.deliveryErr
+					events <- &delivered
+					return nil
+				})
+			kp.EXPECT().Flush(gomock.Any()).Return(0)
+			kp.EXPECT().Close().Do(func() { close(events) })
+
+			ap := mq.NewAsyncProducer(kp, zap.NewNop())
+			require.NoError(t, ap.Start())
+
+			p := mq.NewGeneralProducer(topic, ap, mq.WithKeyFunc(func(event testEvent) []byte {
+				return []byte(strconv.FormatUint(event.Id, 10))
+			}))
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			future, err := p.ProduceAsync(ctx, testEvent{Id: 1}, mq.WithHeaders(kafka.Header{Key: "trace", Value: []byte("id")}))
+			require.NoError(t, err)
+
+			tp, err := future.Wait(ctx)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, int32(3), tp.Partition)
+				assert.Equal(t, kafka.Offset(42), tp.Offset)
+			}
+
+			require.NoError(t, ap.Stop(ctx))
+		})
+	}
+}
+
+func TestGeneralProducer_PropagateHeaders(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	traceId, err := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	require.NoError(t, err)
+	spanId, err := trace.SpanIDFromHex("00f067aa0ba902b7")
+	require.NoError(t, err)
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceId,
+		SpanID:     spanId,
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	events := make(chan kafka.Event, 1)
+	kp := mqmock.NewMockKafkaEventProducer(ctrl)
+	kp.EXPECT().Events().Return(events)
+	kp.EXPECT().Produce(gomock.Any(), gomock.Nil()).
+		DoAndReturn(func(msg *kafka.Message, _ chan kafka.Event) error {
+			assert.Equal(t, mq.Metadata{BizId: 1, NotificationId: "n-1", EventType: "test"}, mq.MetadataFromMessage(msg))
+
+			// 消费侧能还原出同一条链路。
+			sc := trace.SpanContextFromContext(mq.ExtractTraceContext(context.Background(), msg))
+			assert.Equal(t, traceId, sc.TraceID())
+			assert.True(t, sc.IsRemote())
+
+			events <- msg
+			return nil
+		})
+	kp.EXPECT().Flush(gomock.Any()).Return(0)
+	kp.EXPECT().Close().Do(func() { close(events) })
+
+	ap := mq.NewAsyncProducer(kp, zap.NewNop())
+	require.NoError(t, ap.Start())
+
+	p := mq.NewGeneralProducer("test_topic", ap, mq.WithMetadataFunc(func(event testEvent) mq.Metadata {
+		return mq.Metadata{BizId: event.Id, EventType: "test"}
+	}))
+	require.NoError(t, p.Produce(ctx, testEvent{Id: 1}, mq.WithMetadata(mq.Metadata{NotificationId: "n-1"})))
+	require.NoError(t, ap.Stop(context.Background()))
+}
+
+func TestAsyncProducer_StopFailsPending(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	events := make(chan kafka.Event)
+	kp := mqmock.NewMockKafkaEventProducer(ctrl)
+	kp.EXPECT().Events().Return(events)
+	// 投递报告一直没有返回。
+	kp.EXPECT().Produce(gomock.Any(), gomock.Nil()).Return(nil)
+	kp.EXPECT().Flush(gomock.Any()).Return(1).AnyTimes()
+	kp.EXPECT().Close().Do(func() { close(events) })
+
+	ap := mq.NewAsyncProducer(kp, zap.NewNop())
+	require.NoError(t, ap.Start())
+
+	topic := "test_topic"
+	future, err := ap.ProduceAsync(context.Background(), &kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: kafka.PartitionAny},
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.NoError(t, ap.Stop(ctx))
+
+	select {
+	case <-future.Done():
+		_, err = future.Wait(context.Background())
+		assert.Error(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("pending future not completed after stop")
+	}
+
+	// 关闭后不再接受新消息。
+	_, err = ap.ProduceAsync(context.Background(), &kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: kafka.PartitionAny},
+	})
+	assert.True(t, err != nil && !errors.Is(err, context.Canceled))
+}
+
+func TestGeneralProducer_CloseBoundedByContext(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	events := make(chan kafka.Event)
+	kp := mqmock.NewMockKafkaEventProducer(ctrl)
+	kp.EXPECT().Events().Return(events)
+	// 队列中的消息一直无法投递。
+	kp.EXPECT().Flush(gomock.Any()).Return(1).AnyTimes()
+	kp.EXPECT().Close().Do(func() { close(events) })
+
+	ap := mq.NewAsyncProducer(kp, zap.NewNop())
+	require.NoError(t, ap.Start())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	closed := make(chan struct{})
+	go func() {
+		mq.NewGeneralProducer[testEvent]("test_topic", ap).Close(ctx)
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("close not bounded by context")
+	}
+	require.NoError(t, ap.Stop(ctx))
+}
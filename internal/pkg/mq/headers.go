@@ -0,0 +1,161 @@
+package mq
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	HeaderBizId          = "x-kuryr-biz-id"          // 业务 id
+	HeaderNotificationId = "x-kuryr-notification-id" // 通知 id
+	HeaderEventType      = "x-kuryr-event-type"      // 事件类型
+
+	tracerName = "kuryr.mq"
+)
+
+// propagator 消息头中的链路上下文固定使用 W3C trace context 与 baggage 格式，
+// 与全局 propagator 配置无关，保证生产者与消费者两侧格式一致。
+var propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+// Metadata 消息附带的业务元数据，以标准 header 的形式写入消息。
+type Metadata struct {
+	BizId          uint64
+	NotificationId string
+	EventType      string
+}
+
+func (md Metadata) headers() []kafka.Header {
+	headers := make([]kafka.Header, 0, 3)
+	if md.BizId != 0 {
+		headers = append(headers, kafka.Header{Key: HeaderBizId, Value: []byte(strconv.FormatUint(md.BizId, 10))})
+	}
+	if md.NotificationId != "" {
+		headers = append(headers, kafka.Header{Key: HeaderNotificationId, Value: []byte(md.NotificationId)})
+	}
+	if md.EventType != "" {
+		headers = append(headers, kafka.Header{Key: HeaderEventType, Value: []byte(md.EventType)})
+	}
+	return headers
+}
+
+func (md Metadata) attributes() []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, 3)
+	if md.BizId != 0 {
+		attrs = append(attrs, attribute.String("kuryr.biz_id", strconv.FormatUint(md.BizId, 10)))
+	}
+	if md.NotificationId != "" {
+		attrs = append(attrs, attribute.String("kuryr.notification_id", md.NotificationId))
+	}
+	if md.EventType != "" {
+		attrs = append(attrs, attribute.String("kuryr.event_type", md.EventType))
+	}
+	return attrs
+}
+
+// WithMetadata 把业务元数据写入消息 header。
+func WithMetadata(md Metadata) ProduceOption {
+	return func(msg *kafka.Message) {
+		for _, header := range md.headers() {
+			setHeader(msg, header.Key, header.Value)
+		}
+	}
+}
+
+// MetadataFromMessage 从消息 header 中读取业务元数据。
+func MetadataFromMessage(msg *kafka.Message) Metadata {
+	bizId, _ := strconv.ParseUint(headerValue(msg, HeaderBizId), 10, 64)
+	return Metadata{
+		BizId:          bizId,
+		NotificationId: headerValue(msg, HeaderNotificationId),
+		EventType:      headerValue(msg, HeaderEventType),
+	}
+}
+
+// headerCarrier 以 kafka 消息 header 作为链路上下文的载体。
+type headerCarrier struct {
+	msg *kafka.Message
+}
+
+var _ propagation.TextMapCarrier = headerCarrier{}
+
+func (c headerCarrier) Get(key string) string {
+	return headerValue(c.msg, key)
+}
+
+func (c headerCarrier) Set(key string, value string) {
+	setHeader(c.msg, key, []byte(value))
+}
+
+func (c headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(c.msg.Headers))
+	for _, header := range c.msg.Headers {
+		keys = append(keys, header.Key)
+	}
+	return keys
+}
+
+// InjectTraceContext 把 ctx 中的链路上下文写入消息 header。
+func InjectTraceContext(ctx context.Context, msg *kafka.Message) {
+	propagator.Inject(ctx, headerCarrier{msg: msg})
+}
+
+// ExtractTraceContext 从消息 header 中读取链路上下文。
+func ExtractTraceContext(ctx context.Context, msg *kafka.Message) context.Context {
+	return propagator.Extract(ctx, headerCarrier{msg: msg})
+}
+
+// startProduceSpan 开启消息发送 span 并把链路上下文写入消息 header。
+func startProduceSpan(ctx context.Context, msg *kafka.Message) trace.Span {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, *msg.TopicPartition.Topic+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(messageAttributes(msg)...),
+	)
+	InjectTraceContext(ctx, msg)
+	return span
+}
+
+// startConsumeSpan 以消息 header 中的链路上下文为父节点开启消息处理 span。
+func startConsumeSpan(ctx context.Context, msg *kafka.Message) (context.Context, trace.Span) {
+	ctx = ExtractTraceContext(ctx, msg)
+	return otel.Tracer(tracerName).Start(ctx, *msg.TopicPartition.Topic+" process",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(messageAttributes(msg)...),
+	)
+}
+
+func messageAttributes(msg *kafka.Message) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		attribute.String("messaging.system", "kafka"),
+		attribute.String("messaging.destination.name", *msg.TopicPartition.Topic),
+	}
+	if len(msg.Key) > 0 {
+		attrs = append(attrs, attribute.String("messaging.kafka.message.key", string(msg.Key)))
+	}
+	return append(attrs, MetadataFromMessage(msg).attributes()...)
+}
+
+func headerValue(msg *kafka.Message, key string) string {
+	for _, header := range msg.Headers {
+		if header.Key == key {
+			return string(header.Value)
+		}
+	}
+	return ""
+}
+
+// setHeader 设置 header，已存在时覆盖。
+func setHeader(msg *kafka.Message, key string, value []byte) {
+	for i := range msg.Headers {
+		if msg.Headers[i].Key == key {
+			msg.Headers[i].Value = value
+			return
+		}
+	}
+	msg.Headers = append(msg.Headers, kafka.Header{Key: key, Value: value})
+}
@@ -0,0 +1,133 @@
+package mq
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"go.uber.org/zap"
+)
+
+// LagMonitor 消费者组积压监控。
+//
+// 定时统计消费者组在 topic 上未消费的消息数 ( 各分区最新 offset 与已提交 offset 之差的和 )，
+// 积压超过阈值时 Lagging 返回 true，生产方据此拒绝新的消息，避免积压持续扩大。
+// 统计失败时保留上一次的结果。
+type LagMonitor struct {
+	admin    KafkaAdmin
+	group    string
+	topic    string
+	maxLag   int64
+	interval time.Duration
+
+	lag atomic.Int64
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+
+	logger *zap.Logger
+}
+
+func (m *LagMonitor) Start() error {
+	m.refresh()
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-m.stop:
+				return
+			case <-ticker.C:
+				m.refresh()
+			}
+		}
+	}()
+	return nil
+}
+
+func (m *LagMonitor) Stop() error {
+	close(m.stop)
+	m.wg.Wait()
+	return nil
+}
+
+// Lag 最近一次统计的积压消息数。
+func (m *LagMonitor) Lag() int64 {
+	return m.lag.Load()
+}
+
+// Lagging 积压消息数是否超过阈值。
+func (m *LagMonitor) Lagging() bool {
+	return m.lag.Load() > m.maxLag
+}
+
+func (m *LagMonitor) refresh() {
+	ctx, cancel := context.WithTimeout(context.Background(), m.interval)
+	defer cancel()
+
+	lag, err := m.countLag(ctx)
+	if err != nil {
+		m.logger.Error("[kuryr] failed to count consumer lag", zap.String("group", m.group), zap.String("topic", m.topic), zap.Error(err))
+		return
+	}
+	m.lag.Store(lag)
+}
+
+func (m *LagMonitor) countLag(ctx context.Context) (int64, error) {
+	res, err := m.admin.ListConsumerGroupOffsets(ctx, []kafka.ConsumerGroupTopicPartitions{{Group: m.group}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list consumer group offsets: %w", err)
+	}
+
+	// 只统计已经提交过 offset 的分区。
+	committed := make(map[int32]kafka.Offset)
+	latestSpecs := make(map[kafka.TopicPartition]kafka.OffsetSpec)
+	for _, gtp := range res.ConsumerGroupsTopicPartitions {
+		for _, tp := range gtp.Partitions {
+			if tp.Topic == nil || *tp.Topic != m.topic || tp.Error != nil || tp.Offset < 0 {
+				continue
+			}
+			committed[tp.Partition] = tp.Offset
+			latestSpecs[kafka.TopicPartition{Topic: &m.topic, Partition: tp.Partition}] = kafka.LatestOffsetSpec
+		}
+	}
+	if len(latestSpecs) == 0 {
+		return 0, nil
+	}
+
+	latest, err := m.admin.ListOffsets(ctx, latestSpecs)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list latest offsets: %w", err)
+	}
+
+	var lag int64
+	for tp, info := range latest.ResultInfos {
+		if info.Error.Code() != kafka.ErrNoError {
+			return 0, fmt.Errorf("failed to list latest offset of partition [ %d ]: %w", tp.Partition, info.Error)
+		}
+		if offset, ok := committed[tp.Partition]; ok && info.Offset > offset {
+			lag += int64(info.Offset - offset)
+		}
+	}
+	return lag, nil
+}
+
+func NewLagMonitor(admin KafkaAdmin, group, topic string, maxLag int64, interval time.Duration, logger *zap.Logger) *LagMonitor {
+	return &LagMonitor{
+		admin:    admin,
+		group:    group,
+		topic:    topic,
+		maxLag:   maxLag,
+		interval: interval,
+		stop:     make(chan struct{}),
+		logger:   logger,
+	}
+}
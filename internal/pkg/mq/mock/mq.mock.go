@@ -0,0 +1,908 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./types.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./types.go -destination=./mock/mq.mock.go -package=mqmock -typed Producer, Consumer, KafkaConsumer, KafkaProducer, KafkaEventProducer, KafkaAdmin
+//
+
+// Package mqmock is a generated GoMock package.
+package mqmock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	mq "github.com/JrMarcco/kuryr/internal/pkg/mq"
+	kafka "github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockProducer is a mock of Producer interface.
+type MockProducer[T any] struct {
+	ctrl     *gomock.Controller
+	recorder *MockProducerMockRecorder[T]
+	isgomock struct{}
+}
+
+// MockProducerMockRecorder is the mock recorder for MockProducer.
+type MockProducerMockRecorder[T any] struct {
+	mock *MockProducer[T]
+}
+
+// NewMockProducer creates a new mock instance.
+func NewMockProducer[T any](ctrl *gomock.Controller) *MockProducer[T] {
+	mock := &MockProducer[T]{ctrl: ctrl}
+	mock.recorder = &MockProducerMockRecorder[T]{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProducer[T]) EXPECT() *MockProducerMockRecorder[T] {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockProducer[T]) Close(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Close", ctx)
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockProducerMockRecorder[T]) Close(ctx any) *MockProducerCloseCall[T] {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockProducer[T])(nil).Close), ctx)
+	return &MockProducerCloseCall[T]{Call: call}
+}
+
+// MockProducerCloseCall wrap *gomock.Call
+type MockProducerCloseCall[T any] struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockProducerCloseCall[T]) Return() *MockProducerCloseCall[T] {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockProducerCloseCall[T]) Do(f func(context.Context)) *MockProducerCloseCall[T] {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockProducerCloseCall[T]) DoAndReturn(f func(context.Context)) *MockProducerCloseCall[T] {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Produce mocks base method.
+func (m *MockProducer[T]) Produce(ctx context.Context, event T, opts ...mq.ProduceOption) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, event}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Produce", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Produce indicates an expected call of Produce.
+func (mr *MockProducerMockRecorder[T]) Produce(ctx, event any, opts ...any) *MockProducerProduceCall[T] {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, event}, opts...)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Produce", reflect.TypeOf((*MockProducer[T])(nil).Produce), varargs...)
+	return &MockProducerProduceCall[T]{Call: call}
+}
+
+// MockProducerProduceCall wrap *gomock.Call
+type MockProducerProduceCall[T any] struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockProducerProduceCall[T]) Return(arg0 error) *MockProducerProduceCall[T] {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockProducerProduceCall[T]) Do(f func(context.Context, T, ...mq.ProduceOption) error) *MockProducerProduceCall[T] {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockProducerProduceCall[T]) DoAndReturn(f func(context.Context, T, ...mq.ProduceOption) error) *MockProducerProduceCall[T] {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// ProduceAsync mocks base method.
+func (m *MockProducer[T]) ProduceAsync(ctx context.Context, event T, opts ...mq.ProduceOption) (*mq.DeliveryFuture, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, event}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ProduceAsync", varargs...)
+	ret0, _ := ret[0].(*mq.DeliveryFuture)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProduceAsync indicates an expected call of ProduceAsync.
+func (mr *MockProducerMockRecorder[T]) ProduceAsync(ctx, event any, opts ...any) *MockProducerProduceAsyncCall[T] {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, event}, opts...)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProduceAsync", reflect.TypeOf((*MockProducer[T])(nil).ProduceAsync), varargs...)
+	return &MockProducerProduceAsyncCall[T]{Call: call}
+}
+
+// MockProducerProduceAsyncCall wrap *gomock.Call
+type MockProducerProduceAsyncCall[T any] struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockProducerProduceAsyncCall[T]) Return(arg0 *mq.DeliveryFuture, arg1 error) *MockProducerProduceAsyncCall[T] {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockProducerProduceAsyncCall[T]) Do(f func(context.Context, T, ...mq.ProduceOption) (*mq.DeliveryFuture, error)) *MockProducerProduceAsyncCall[T] {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockProducerProduceAsyncCall[T]) DoAndReturn(f func(context.Context, T, ...mq.ProduceOption) (*mq.DeliveryFuture, error)) *MockProducerProduceAsyncCall[T] {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// MockConsumer is a mock of Consumer interface.
+type MockConsumer[T any] struct {
+	ctrl     *gomock.Controller
+	recorder *MockConsumerMockRecorder[T]
+	isgomock struct{}
+}
+
+// MockConsumerMockRecorder is the mock recorder for MockConsumer.
+type MockConsumerMockRecorder[T any] struct {
+	mock *MockConsumer[T]
+}
+
+// NewMockConsumer creates a new mock instance.
+func NewMockConsumer[T any](ctrl *gomock.Controller) *MockConsumer[T] {
+	mock := &MockConsumer[T]{ctrl: ctrl}
+	mock.recorder = &MockConsumerMockRecorder[T]{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConsumer[T]) EXPECT() *MockConsumerMockRecorder[T] {
+	return m.recorder
+}
+
+// Start mocks base method.
+func (m *MockConsumer[T]) Start() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Start")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockConsumerMockRecorder[T]) Start() *MockConsumerStartCall[T] {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockConsumer[T])(nil).Start))
+	return &MockConsumerStartCall[T]{Call: call}
+}
+
+// MockConsumerStartCall wrap *gomock.Call
+type MockConsumerStartCall[T any] struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockConsumerStartCall[T]) Return(arg0 error) *MockConsumerStartCall[T] {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockConsumerStartCall[T]) Do(f func() error) *MockConsumerStartCall[T] {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockConsumerStartCall[T]) DoAndReturn(f func() error) *MockConsumerStartCall[T] {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Stop mocks base method.
+func (m *MockConsumer[T]) Stop() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stop")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Stop indicates an expected call of Stop.
+func (mr *MockConsumerMockRecorder[T]) Stop() *MockConsumerStopCall[T] {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockConsumer[T])(nil).Stop))
+	return &MockConsumerStopCall[T]{Call: call}
+}
+
+// MockConsumerStopCall wrap *gomock.Call
+type MockConsumerStopCall[T any] struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockConsumerStopCall[T]) Return(arg0 error) *MockConsumerStopCall[T] {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockConsumerStopCall[T]) Do(f func() error) *MockConsumerStopCall[T] {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockConsumerStopCall[T]) DoAndReturn(f func() error) *MockConsumerStopCall[T] {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// MockKafkaConsumer is a mock of KafkaConsumer interface.
+type MockKafkaConsumer struct {
+	ctrl     *gomock.Controller
+	recorder *MockKafkaConsumerMockRecorder
+	isgomock struct{}
+}
+
+// MockKafkaConsumerMockRecorder is the mock recorder for MockKafkaConsumer.
+type MockKafkaConsumerMockRecorder struct {
+	mock *MockKafkaConsumer
+}
+
+// NewMockKafkaConsumer creates a new mock instance.
+func NewMockKafkaConsumer(ctrl *gomock.Controller) *MockKafkaConsumer {
+	mock := &MockKafkaConsumer{ctrl: ctrl}
+	mock.recorder = &MockKafkaConsumerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKafkaConsumer) EXPECT() *MockKafkaConsumerMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockKafkaConsumer) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockKafkaConsumerMockRecorder) Close() *MockKafkaConsumerCloseCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockKafkaConsumer)(nil).Close))
+	return &MockKafkaConsumerCloseCall{Call: call}
+}
+
+// MockKafkaConsumerCloseCall wrap *gomock.Call
+type MockKafkaConsumerCloseCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockKafkaConsumerCloseCall) Return(arg0 error) *MockKafkaConsumerCloseCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockKafkaConsumerCloseCall) Do(f func() error) *MockKafkaConsumerCloseCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockKafkaConsumerCloseCall) DoAndReturn(f func() error) *MockKafkaConsumerCloseCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// CommitOffsets mocks base method.
+func (m *MockKafkaConsumer) CommitOffsets(offsets []kafka.TopicPartition) ([]kafka.TopicPartition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CommitOffsets", offsets)
+	ret0, _ := ret[0].([]kafka.TopicPartition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CommitOffsets indicates an expected call of CommitOffsets.
+func (mr *MockKafkaConsumerMockRecorder) CommitOffsets(offsets any) *MockKafkaConsumerCommitOffsetsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommitOffsets", reflect.TypeOf((*MockKafkaConsumer)(nil).CommitOffsets), offsets)
+	return &MockKafkaConsumerCommitOffsetsCall{Call: call}
+}
+
+// MockKafkaConsumerCommitOffsetsCall wrap *gomock.Call
+type MockKafkaConsumerCommitOffsetsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockKafkaConsumerCommitOffsetsCall) Return(arg0 []kafka.TopicPartition, arg1 error) *MockKafkaConsumerCommitOffsetsCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockKafkaConsumerCommitOffsetsCall) Do(f func([]kafka.TopicPartition) ([]kafka.TopicPartition, error)) *MockKafkaConsumerCommitOffsetsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockKafkaConsumerCommitOffsetsCall) DoAndReturn(f func([]kafka.TopicPartition) ([]kafka.TopicPartition, error)) *MockKafkaConsumerCommitOffsetsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Pause mocks base method.
+func (m *MockKafkaConsumer) Pause(partitions []kafka.TopicPartition) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Pause", partitions)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Pause indicates an expected call of Pause.
+func (mr *MockKafkaConsumerMockRecorder) Pause(partitions any) *MockKafkaConsumerPauseCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pause", reflect.TypeOf((*MockKafkaConsumer)(nil).Pause), partitions)
+	return &MockKafkaConsumerPauseCall{Call: call}
+}
+
+// MockKafkaConsumerPauseCall wrap *gomock.Call
+type MockKafkaConsumerPauseCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockKafkaConsumerPauseCall) Return(arg0 error) *MockKafkaConsumerPauseCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockKafkaConsumerPauseCall) Do(f func([]kafka.TopicPartition) error) *MockKafkaConsumerPauseCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockKafkaConsumerPauseCall) DoAndReturn(f func([]kafka.TopicPartition) error) *MockKafkaConsumerPauseCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// ReadMessage mocks base method.
+func (m *MockKafkaConsumer) ReadMessage(timeout time.Duration) (*kafka.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadMessage", timeout)
+	ret0, _ := ret[0].(*kafka.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadMessage indicates an expected call of ReadMessage.
+func (mr *MockKafkaConsumerMockRecorder) ReadMessage(timeout any) *MockKafkaConsumerReadMessageCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadMessage", reflect.TypeOf((*MockKafkaConsumer)(nil).ReadMessage), timeout)
+	return &MockKafkaConsumerReadMessageCall{Call: call}
+}
+
+// MockKafkaConsumerReadMessageCall wrap *gomock.Call
+type MockKafkaConsumerReadMessageCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockKafkaConsumerReadMessageCall) Return(arg0 *kafka.Message, arg1 error) *MockKafkaConsumerReadMessageCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockKafkaConsumerReadMessageCall) Do(f func(time.Duration) (*kafka.Message, error)) *MockKafkaConsumerReadMessageCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockKafkaConsumerReadMessageCall) DoAndReturn(f func(time.Duration) (*kafka.Message, error)) *MockKafkaConsumerReadMessageCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Resume mocks base method.
+func (m *MockKafkaConsumer) Resume(partitions []kafka.TopicPartition) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resume", partitions)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Resume indicates an expected call of Resume.
+func (mr *MockKafkaConsumerMockRecorder) Resume(partitions any) *MockKafkaConsumerResumeCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resume", reflect.TypeOf((*MockKafkaConsumer)(nil).Resume), partitions)
+	return &MockKafkaConsumerResumeCall{Call: call}
+}
+
+// MockKafkaConsumerResumeCall wrap *gomock.Call
+type MockKafkaConsumerResumeCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockKafkaConsumerResumeCall) Return(arg0 error) *MockKafkaConsumerResumeCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockKafkaConsumerResumeCall) Do(f func([]kafka.TopicPartition) error) *MockKafkaConsumerResumeCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockKafkaConsumerResumeCall) DoAndReturn(f func([]kafka.TopicPartition) error) *MockKafkaConsumerResumeCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// SeekPartitions mocks base method.
+func (m *MockKafkaConsumer) SeekPartitions(partitions []kafka.TopicPartition) ([]kafka.TopicPartition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SeekPartitions", partitions)
+	ret0, _ := ret[0].([]kafka.TopicPartition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SeekPartitions indicates an expected call of SeekPartitions.
+func (mr *MockKafkaConsumerMockRecorder) SeekPartitions(partitions any) *MockKafkaConsumerSeekPartitionsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SeekPartitions", reflect.TypeOf((*MockKafkaConsumer)(nil).SeekPartitions), partitions)
+	return &MockKafkaConsumerSeekPartitionsCall{Call: call}
+}
+
+// MockKafkaConsumerSeekPartitionsCall wrap *gomock.Call
+type MockKafkaConsumerSeekPartitionsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockKafkaConsumerSeekPartitionsCall) Return(arg0 []kafka.TopicPartition, arg1 error) *MockKafkaConsumerSeekPartitionsCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockKafkaConsumerSeekPartitionsCall) Do(f func([]kafka.TopicPartition) ([]kafka.TopicPartition, error)) *MockKafkaConsumerSeekPartitionsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockKafkaConsumerSeekPartitionsCall) DoAndReturn(f func([]kafka.TopicPartition) ([]kafka.TopicPartition, error)) *MockKafkaConsumerSeekPartitionsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// SubscribeTopics mocks base method.
+func (m *MockKafkaConsumer) SubscribeTopics(topics []string, rebalanceCb kafka.RebalanceCb) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeTopics", topics, rebalanceCb)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SubscribeTopics indicates an expected call of SubscribeTopics.
+func (mr *MockKafkaConsumerMockRecorder) SubscribeTopics(topics, rebalanceCb any) *MockKafkaConsumerSubscribeTopicsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeTopics", reflect.TypeOf((*MockKafkaConsumer)(nil).SubscribeTopics), topics, rebalanceCb)
+	return &MockKafkaConsumerSubscribeTopicsCall{Call: call}
+}
+
+// MockKafkaConsumerSubscribeTopicsCall wrap *gomock.Call
+type MockKafkaConsumerSubscribeTopicsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockKafkaConsumerSubscribeTopicsCall) Return(arg0 error) *MockKafkaConsumerSubscribeTopicsCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockKafkaConsumerSubscribeTopicsCall) Do(f func([]string, kafka.RebalanceCb) error) *MockKafkaConsumerSubscribeTopicsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockKafkaConsumerSubscribeTopicsCall) DoAndReturn(f func([]string, kafka.RebalanceCb) error) *MockKafkaConsumerSubscribeTopicsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// MockKafkaProducer is a mock of KafkaProducer interface.
+type MockKafkaProducer struct {
+	ctrl     *gomock.Controller
+	recorder *MockKafkaProducerMockRecorder
+	isgomock struct{}
+}
+
+// MockKafkaProducerMockRecorder is the mock recorder for MockKafkaProducer.
+type MockKafkaProducerMockRecorder struct {
+	mock *MockKafkaProducer
+}
+
+// NewMockKafkaProducer creates a new mock instance.
+func NewMockKafkaProducer(ctrl *gomock.Controller) *MockKafkaProducer {
+	mock := &MockKafkaProducer{ctrl: ctrl}
+	mock.recorder = &MockKafkaProducerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKafkaProducer) EXPECT() *MockKafkaProducerMockRecorder {
+	return m.recorder
+}
+
+// Produce mocks base method.
+func (m *MockKafkaProducer) Produce(msg *kafka.Message, deliveryChan chan kafka.Event) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Produce", msg, deliveryChan)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Produce indicates an expected call of Produce.
+func (mr *MockKafkaProducerMockRecorder) Produce(msg, deliveryChan any) *MockKafkaProducerProduceCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Produce", reflect.TypeOf((*MockKafkaProducer)(nil).Produce), msg, deliveryChan)
+	return &MockKafkaProducerProduceCall{Call: call}
+}
+
+// MockKafkaProducerProduceCall wrap *gomock.Call
+type MockKafkaProducerProduceCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockKafkaProducerProduceCall) Return(arg0 error) *MockKafkaProducerProduceCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockKafkaProducerProduceCall) Do(f func(*kafka.Message, chan kafka.Event) error) *MockKafkaProducerProduceCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockKafkaProducerProduceCall) DoAndReturn(f func(*kafka.Message, chan kafka.Event) error) *MockKafkaProducerProduceCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// MockKafkaEventProducer is a mock of KafkaEventProducer interface.
+type MockKafkaEventProducer struct {
+	ctrl     *gomock.Controller
+	recorder *MockKafkaEventProducerMockRecorder
+	isgomock struct{}
+}
+
+// MockKafkaEventProducerMockRecorder is the mock recorder for MockKafkaEventProducer.
+type MockKafkaEventProducerMockRecorder struct {
+	mock *MockKafkaEventProducer
+}
+
+// NewMockKafkaEventProducer creates a new mock instance.
+func NewMockKafkaEventProducer(ctrl *gomock.Controller) *MockKafkaEventProducer {
+	mock := &MockKafkaEventProducer{ctrl: ctrl}
+	mock.recorder = &MockKafkaEventProducerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKafkaEventProducer) EXPECT() *MockKafkaEventProducerMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockKafkaEventProducer) Close() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Close")
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockKafkaEventProducerMockRecorder) Close() *MockKafkaEventProducerCloseCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockKafkaEventProducer)(nil).Close))
+	return &MockKafkaEventProducerCloseCall{Call: call}
+}
+
+// MockKafkaEventProducerCloseCall wrap *gomock.Call
+type MockKafkaEventProducerCloseCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockKafkaEventProducerCloseCall) Return() *MockKafkaEventProducerCloseCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockKafkaEventProducerCloseCall) Do(f func()) *MockKafkaEventProducerCloseCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockKafkaEventProducerCloseCall) DoAndReturn(f func()) *MockKafkaEventProducerCloseCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Events mocks base method.
+func (m *MockKafkaEventProducer) Events() chan kafka.Event {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Events")
+	ret0, _ := ret[0].(chan kafka.Event)
+	return ret0
+}
+
+// Events indicates an expected call of Events.
+func (mr *MockKafkaEventProducerMockRecorder) Events() *MockKafkaEventProducerEventsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Events", reflect.TypeOf((*MockKafkaEventProducer)(nil).Events))
+	return &MockKafkaEventProducerEventsCall{Call: call}
+}
+
+// MockKafkaEventProducerEventsCall wrap *gomock.Call
+type MockKafkaEventProducerEventsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockKafkaEventProducerEventsCall) Return(arg0 chan kafka.Event) *MockKafkaEventProducerEventsCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockKafkaEventProducerEventsCall) Do(f func() chan kafka.Event) *MockKafkaEventProducerEventsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockKafkaEventProducerEventsCall) DoAndReturn(f func() chan kafka.Event) *MockKafkaEventProducerEventsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Flush mocks base method.
+func (m *MockKafkaEventProducer) Flush(timeoutMs int) int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Flush", timeoutMs)
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// Flush indicates an expected call of Flush.
+func (mr *MockKafkaEventProducerMockRecorder) Flush(timeoutMs any) *MockKafkaEventProducerFlushCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Flush", reflect.TypeOf((*MockKafkaEventProducer)(nil).Flush), timeoutMs)
+	return &MockKafkaEventProducerFlushCall{Call: call}
+}
+
+// MockKafkaEventProducerFlushCall wrap *gomock.Call
+type MockKafkaEventProducerFlushCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockKafkaEventProducerFlushCall) Return(arg0 int) *MockKafkaEventProducerFlushCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockKafkaEventProducerFlushCall) Do(f func(int) int) *MockKafkaEventProducerFlushCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockKafkaEventProducerFlushCall) DoAndReturn(f func(int) int) *MockKafkaEventProducerFlushCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Produce mocks base method.
+func (m *MockKafkaEventProducer) Produce(msg *kafka.Message, deliveryChan chan kafka.Event) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Produce", msg, deliveryChan)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Produce indicates an expected call of Produce.
+func (mr *MockKafkaEventProducerMockRecorder) Produce(msg, deliveryChan any) *MockKafkaEventProducerProduceCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Produce", reflect.TypeOf((*MockKafkaEventProducer)(nil).Produce), msg, deliveryChan)
+	return &MockKafkaEventProducerProduceCall{Call: call}
+}
+
+// MockKafkaEventProducerProduceCall wrap *gomock.Call
+type MockKafkaEventProducerProduceCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockKafkaEventProducerProduceCall) Return(arg0 error) *MockKafkaEventProducerProduceCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockKafkaEventProducerProduceCall) Do(f func(*kafka.Message, chan kafka.Event) error) *MockKafkaEventProducerProduceCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockKafkaEventProducerProduceCall) DoAndReturn(f func(*kafka.Message, chan kafka.Event) error) *MockKafkaEventProducerProduceCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// MockKafkaAdmin is a mock of KafkaAdmin interface.
+type MockKafkaAdmin struct {
+	ctrl     *gomock.Controller
+	recorder *MockKafkaAdminMockRecorder
+	isgomock struct{}
+}
+
+// MockKafkaAdminMockRecorder is the mock recorder for MockKafkaAdmin.
+type MockKafkaAdminMockRecorder struct {
+	mock *MockKafkaAdmin
+}
+
+// NewMockKafkaAdmin creates a new mock instance.
+func NewMockKafkaAdmin(ctrl *gomock.Controller) *MockKafkaAdmin {
+	mock := &MockKafkaAdmin{ctrl: ctrl}
+	mock.recorder = &MockKafkaAdminMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKafkaAdmin) EXPECT() *MockKafkaAdminMockRecorder {
+	return m.recorder
+}
+
+// ListConsumerGroupOffsets mocks base method.
+func (m *MockKafkaAdmin) ListConsumerGroupOffsets(ctx context.Context, groupsPartitions []kafka.ConsumerGroupTopicPartitions, options ...kafka.ListConsumerGroupOffsetsAdminOption) (kafka.ListConsumerGroupOffsetsResult, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, groupsPartitions}
+	for _, a := range options {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListConsumerGroupOffsets", varargs...)
+	ret0, _ := ret[0].(kafka.ListConsumerGroupOffsetsResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListConsumerGroupOffsets indicates an expected call of ListConsumerGroupOffsets.
+func (mr *MockKafkaAdminMockRecorder) ListConsumerGroupOffsets(ctx, groupsPartitions any, options ...any) *MockKafkaAdminListConsumerGroupOffsetsCall {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, groupsPartitions}, options...)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListConsumerGroupOffsets", reflect.TypeOf((*MockKafkaAdmin)(nil).ListConsumerGroupOffsets), varargs...)
+	return &MockKafkaAdminListConsumerGroupOffsetsCall{Call: call}
+}
+
+// MockKafkaAdminListConsumerGroupOffsetsCall wrap *gomock.Call
+type MockKafkaAdminListConsumerGroupOffsetsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockKafkaAdminListConsumerGroupOffsetsCall) Return(arg0 kafka.ListConsumerGroupOffsetsResult, arg1 error) *MockKafkaAdminListConsumerGroupOffsetsCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockKafkaAdminListConsumerGroupOffsetsCall) Do(f func(context.Context, []kafka.ConsumerGroupTopicPartitions, ...kafka.ListConsumerGroupOffsetsAdminOption) (kafka.ListConsumerGroupOffsetsResult, error)) *MockKafkaAdminListConsumerGroupOffsetsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockKafkaAdminListConsumerGroupOffsetsCall) DoAndReturn(f func(context.Context, []kafka.ConsumerGroupTopicPartitions, ...kafka.ListConsumerGroupOffsetsAdminOption) (kafka.ListConsumerGroupOffsetsResult, error)) *MockKafkaAdminListConsumerGroupOffsetsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// ListOffsets mocks base method.
+func (m *MockKafkaAdmin) ListOffsets(ctx context.Context, topicPartitionOffsets map[kafka.TopicPartition]kafka.OffsetSpec, options ...kafka.ListOffsetsAdminOption) (kafka.ListOffsetsResult, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, topicPartitionOffsets}
+	for _, a := range options {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListOffsets", varargs...)
+	ret0, _ := ret[0].(kafka.ListOffsetsResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOffsets indicates an expected call of ListOffsets.
+func (mr *MockKafkaAdminMockRecorder) ListOffsets(ctx, topicPartitionOffsets any, options ...any) *MockKafkaAdminListOffsetsCall {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, topicPartitionOffsets}, options...)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOffsets", reflect.TypeOf((*MockKafkaAdmin)(nil).ListOffsets), varargs...)
+	return &MockKafkaAdminListOffsetsCall{Call: call}
+}
+
+// MockKafkaAdminListOffsetsCall wrap *gomock.Call
+type MockKafkaAdminListOffsetsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockKafkaAdminListOffsetsCall) Return(arg0 kafka.ListOffsetsResult, arg1 error) *MockKafkaAdminListOffsetsCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockKafkaAdminListOffsetsCall) Do(f func(context.Context, map[kafka.TopicPartition]kafka.OffsetSpec, ...kafka.ListOffsetsAdminOption) (kafka.ListOffsetsResult, error)) *MockKafkaAdminListOffsetsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockKafkaAdminListOffsetsCall) DoAndReturn(f func(context.Context, map[kafka.TopicPartition]kafka.OffsetSpec, ...kafka.ListOffsetsAdminOption) (kafka.ListOffsetsResult, error)) *MockKafkaAdminListOffsetsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
@@ -2,13 +2,77 @@ package mq
 
 import (
 	"context"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
 )
 
-//go:generate mockgen -source=./types.go -destination=./mock/mq.mock.go -package=mqmock -typed Producer, Consumer
+//go:generate mockgen -source=./types.go -destination=./mock/mq.mock.go -package=mqmock -typed Producer, Consumer, KafkaConsumer, KafkaProducer, KafkaEventProducer, KafkaAdmin
 
 type Producer[T any] interface {
-	Produce(ctx context.Context, event T) error
+	// Produce 发送消息并等待投递结果。
+	Produce(ctx context.Context, event T, opts ...ProduceOption) error
+	// ProduceAsync 发送消息后立即返回，通过 DeliveryFuture 获取投递结果。
+	ProduceAsync(ctx context.Context, event T, opts ...ProduceOption) (*DeliveryFuture, error)
+	// Close 等待已发送的消息投递完成，ctx 结束后不再等待。
+	Close(ctx context.Context)
+}
+
+// ProduceOption 单条消息的发送选项。
+type ProduceOption func(msg *kafka.Message)
+
+// WithKey 设置消息 key，相同 key 的消息会写入同一个分区，保证顺序。
+func WithKey(key []byte) ProduceOption {
+	return func(msg *kafka.Message) {
+		msg.Key = key
+	}
+}
+
+// WithHeaders 追加消息 header。
+func WithHeaders(headers ...kafka.Header) ProduceOption {
+	return func(msg *kafka.Message) {
+		msg.Headers = append(msg.Headers, headers...)
+	}
+}
+
+// Handler 消息处理函数，返回错误时消息会投递到重试 topic。
+type Handler[T any] func(ctx context.Context, event T) error
+
+type Consumer[T any] interface {
+	Start() error
+	Stop() error
+}
+
+// KafkaConsumer 消费者依赖的 kafka 消费者接口，*kafka.Consumer 实现了该接口。
+type KafkaConsumer interface {
+	SubscribeTopics(topics []string, rebalanceCb kafka.RebalanceCb) error
+	ReadMessage(timeout time.Duration) (*kafka.Message, error)
+	CommitOffsets(offsets []kafka.TopicPartition) ([]kafka.TopicPartition, error)
+	Pause(partitions []kafka.TopicPartition) error
+	Resume(partitions []kafka.TopicPartition) error
+	SeekPartitions(partitions []kafka.TopicPartition) ([]kafka.TopicPartition, error)
+	Close() error
+}
+
+// KafkaProducer 消费者投递重试 / 死信消息依赖的 kafka 生产者接口，*kafka.Producer 与 AsyncProducer 实现了该接口。
+type KafkaProducer interface {
+	Produce(msg *kafka.Message, deliveryChan chan kafka.Event) error
+}
+
+// KafkaEventProducer AsyncProducer 依赖的 kafka 生产者接口，投递报告通过 Events 返回，*kafka.Producer 实现了该接口。
+type KafkaEventProducer interface {
+	KafkaProducer
+	Events() chan kafka.Event
+	Flush(timeoutMs int) int
 	Close()
 }
 
-type Conmsumer interface{}
+// KafkaAdmin LagMonitor 依赖的 kafka 管理接口，*kafka.AdminClient 实现了该接口。
+type KafkaAdmin interface {
+	ListConsumerGroupOffsets(
+		ctx context.Context, groupsPartitions []kafka.ConsumerGroupTopicPartitions, options ...kafka.ListConsumerGroupOffsetsAdminOption,
+	) (kafka.ListConsumerGroupOffsetsResult, error)
+	ListOffsets(
+		ctx context.Context, topicPartitionOffsets map[kafka.TopicPartition]kafka.OffsetSpec, options ...kafka.ListOffsetsAdminOption,
+	) (kafka.ListOffsetsResult, error)
+}
@@ -0,0 +1,116 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+var _ Bus[any] = (*RedisBus[any])(nil)
+
+// RedisBus 基于 redis pub/sub 的事件总线，事件以 json 格式发布到指定 channel。
+type RedisBus[T any] struct {
+	rc      redis.Cmdable
+	channel string
+
+	mu       sync.RWMutex
+	handlers []Handler[T]
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	logger *zap.Logger
+}
+
+func (b *RedisBus[T]) Publish(ctx context.Context, event T) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("[kuryr] failed to marshal event: %w", err)
+	}
+	return b.rc.Publish(ctx, b.channel, data).Err()
+}
+
+func (b *RedisBus[T]) Subscribe(handler Handler[T]) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers = append(b.handlers, handler)
+}
+
+// Start 订阅 redis channel 并开始分发事件。
+func (b *RedisBus[T]) Start() error {
+	redisClient, ok := b.rc.(*redis.Client)
+	if !ok {
+		return fmt.Errorf("[kuryr] failed to cast redis client to redis.Client")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	b.cancel = cancel
+
+	pubSub := redisClient.Subscribe(ctx, b.channel)
+	// 等待订阅确认，保证 Start 返回后发布的事件不会丢失。
+	if _, err := pubSub.Receive(ctx); err != nil {
+		cancel()
+		_ = pubSub.Close()
+		return fmt.Errorf("[kuryr] failed to subscribe channel [ %s ]: %w", b.channel, err)
+	}
+
+	b.wg.Add(1)
+	go b.dispatch(ctx, pubSub)
+	return nil
+}
+
+func (b *RedisBus[T]) Stop() error {
+	if b.cancel != nil {
+		b.cancel()
+	}
+	b.wg.Wait()
+	return nil
+}
+
+func (b *RedisBus[T]) dispatch(ctx context.Context, pubSub *redis.PubSub) {
+	defer b.wg.Done()
+	defer func() {
+		if err := pubSub.Close(); err != nil {
+			b.logger.Error("[kuryr] failed to close pub sub", zap.String("channel", b.channel), zap.Error(err))
+		}
+	}()
+
+	ch := pubSub.Channel()
+	for {
+		select {
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+
+			var event T
+			if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+				b.logger.Error("[kuryr] failed to unmarshal event", zap.String("channel", b.channel), zap.String("payload", msg.Payload), zap.Error(err))
+				continue
+			}
+
+			b.mu.RLock()
+			handlers := b.handlers
+			b.mu.RUnlock()
+
+			for _, handler := range handlers {
+				handler(ctx, event)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func NewRedisBus[T any](rc redis.Cmdable, channel string, logger *zap.Logger) *RedisBus[T] {
+	return &RedisBus[T]{
+		rc:      rc,
+		channel: channel,
+		logger:  logger,
+	}
+}
@@ -0,0 +1,17 @@
+package pubsub
+
+import (
+	"context"
+)
+
+// Handler 事件处理函数。
+type Handler[T any] func(ctx context.Context, event T)
+
+// Bus 跨实例的事件总线。
+//
+// 发布的事件会广播给所有实例 ( 包括发布者自身 ) 的订阅者，
+// 适合用来通知各实例刷新本地状态，不保证事件一定送达，订阅者需要配合过期时间兜底。
+type Bus[T any] interface {
+	Publish(ctx context.Context, event T) error
+	Subscribe(handler Handler[T])
+}
@@ -29,11 +29,35 @@ type ExponentialBackoffConfig struct {
 	MaxRetryTimes   int32         `json:"max_retry_times"`
 }
 
+// MaxRetryTimes 最大重试次数，小于等于 0 表示不限制。
+func (c Config) MaxRetryTimes() int32 {
+	switch {
+	case c.Type == StrategyTypeFixedInterval && c.FixedInterval != nil:
+		return c.FixedInterval.MaxRetryTimes
+	case c.Type == StrategyTypeExponentialBackoff && c.ExponentialBackoff != nil:
+		return c.ExponentialBackoff.MaxRetryTimes
+	default:
+		return 0
+	}
+}
+
+// Validate 校验配置能否创建重试策略。
+func (c Config) Validate() error {
+	_, err := NewRetryStrategy(c)
+	return err
+}
+
 func NewRetryStrategy(cfg Config) (retry.Strategy, error) {
 	switch cfg.Type {
 	case StrategyTypeFixedInterval:
+		if cfg.FixedInterval == nil {
+			return nil, fmt.Errorf("[kuryr] fixed interval retry config is nil")
+		}
 		return retry.NewFixedIntervalStrategy(cfg.FixedInterval.Interval, cfg.FixedInterval.MaxRetryTimes)
 	case StrategyTypeExponentialBackoff:
+		if cfg.ExponentialBackoff == nil {
+			return nil, fmt.Errorf("[kuryr] exponential backoff retry config is nil")
+		}
 		return retry.NewExponentialBackoffStrategy(
 			cfg.ExponentialBackoff.InitialInterval,
 			cfg.ExponentialBackoff.MaxInterval,
@@ -3,7 +3,6 @@ package sharding
 import (
 	"fmt"
 
-	"github.com/JrMarcco/kuryr/internal/pkg/hash"
 	"github.com/JrMarcco/kuryr/internal/pkg/sharding/snowflake"
 )
 
@@ -18,8 +17,12 @@ type HashSharding struct {
 }
 
 // Shard 根据 biz_id 和 biz_key 分库分表。
+//
+// 注意：
+//
+//	这里与 id 中保存的 hash 值保持一致，保证 Shard 与 DstFromId 得到相同的分库分表结果。
 func (s *HashSharding) Shard(bizId uint64, bizKey string) Dst {
-	hashVal := hash.HashUint64(bizId, bizKey)
+	hashVal := snowflake.HashOf(bizId, bizKey)
 	dbSuffix := hashVal % s.dbShardCount
 	tableSuffix := (hashVal / s.dbShardCount) % s.tableShardCount
 
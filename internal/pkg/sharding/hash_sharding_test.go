@@ -0,0 +1,37 @@
+package sharding
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/JrMarcco/kuryr/internal/pkg/sharding/snowflake"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashSharding_ShardMatchDstFromId(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name            string
+		dbShardCount    uint64
+		tableShardCount uint64
+	}{
+		{name: "2 db 4 table", dbShardCount: 2, tableShardCount: 4},
+		{name: "3 db 5 table", dbShardCount: 3, tableShardCount: 5},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := NewHashSharding("kuryr", "callback_log", tc.dbShardCount, tc.tableShardCount)
+			g := snowflake.NewGenerator()
+
+			for i := uint64(0); i < 100; i++ {
+				bizKey := "test_biz_key:" + strconv.FormatUint(i, 10)
+				id := g.NextId(i, bizKey)
+				assert.Equal(t, s.DstFromId(id), s.Shard(i, bizKey))
+			}
+		})
+	}
+}
@@ -49,11 +49,16 @@ func NewGenerator() *Generator {
 //   - 12 位自增序列
 func (g *Generator) NextId(bizId uint64, bizKey string) uint64 {
 	timestamp := uint64(time.Now().UnixMilli()) - epochMillis
-	hashVal := hash.HashUint64(bizId, bizKey)
+	hashVal := HashOf(bizId, bizKey)
 
 	seq := atomic.AddUint64(&g.sequence, 1) - 1
 
-	return (timestamp&timestampMask)<<timestampShift | (hashVal&hashMask)<<hashShift | (seq & sequenceMask)
+	return (timestamp&timestampMask)<<timestampShift | hashVal<<hashShift | (seq & sequenceMask)
+}
+
+// HashOf 返回业务 id 和业务 key 写入 id 中的 hash 值，与 ExtractHash 的结果一致。
+func HashOf(bizId uint64, bizKey string) uint64 {
+	return hash.HashUint64(bizId, bizKey) & hashMask
 }
 
 func ExtractHash(id uint64) uint64 {
@@ -0,0 +1,23 @@
+package signature
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+)
+
+// kuryr 与外部系统之间 http 请求的签名约定，回调业务方 webhook 与接收供应商推送共用：
+//   - X-Kuryr-Timestamp: 毫秒时间戳。
+//   - X-Kuryr-Signature: hex(HMAC-SHA256(secret, timestamp + "\n" + body))。
+const (
+	HeaderSignature = "X-Kuryr-Signature"
+	HeaderTimestamp = "X-Kuryr-Timestamp"
+)
+
+// Sign 计算请求签名，签名内容为 "{timestamp}\n{body}"。
+func Sign(secret []byte, timestamp string, body []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("\n"))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
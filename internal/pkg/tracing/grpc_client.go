@@ -0,0 +1,108 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const tracerName = "kuryr.grpc.client"
+
+var _ grpc.ClientConnInterface = (*ClientConn)(nil)
+
+// ClientConn 带链路追踪的 grpc 客户端连接。
+//
+// 用于无法设置 dial option ( grpc.WithStatsHandler ) 的场景，例如由 easy-grpc client.Manager 创建的连接。
+// 一元调用会开启 client span 并把链路上下文写入请求 metadata；流式调用只传递链路上下文。
+type ClientConn struct {
+	cc     grpc.ClientConnInterface
+	tracer trace.Tracer
+}
+
+func (c *ClientConn) Invoke(ctx context.Context, method string, args any, reply any, opts ...grpc.CallOption) error {
+	service, rpcMethod := splitMethod(method)
+	ctx, span := c.tracer.Start(ctx, strings.TrimPrefix(method, "/"),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("rpc.system", "grpc"),
+			attribute.String("rpc.service", service),
+			attribute.String("rpc.method", rpcMethod),
+		),
+	)
+	defer span.End()
+
+	err := c.cc.Invoke(inject(ctx), method, args, reply, opts...)
+
+	s, _ := status.FromError(err)
+	span.SetAttributes(attribute.Int64("rpc.grpc.status_code", int64(s.Code())))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, s.Message())
+	}
+	return err
+}
+
+func (c *ClientConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return c.cc.NewStream(inject(ctx), desc, method, opts...)
+}
+
+// inject 把链路上下文写入请求 metadata。
+func inject(ctx context.Context) context.Context {
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+	otel.GetTextMapPropagator().Inject(ctx, metadataCarrier(md))
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// metadataCarrier 以 grpc metadata 作为链路上下文的载体。
+type metadataCarrier metadata.MD
+
+var _ propagation.TextMapCarrier = metadataCarrier{}
+
+func (c metadataCarrier) Get(key string) string {
+	values := metadata.MD(c).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func (c metadataCarrier) Set(key string, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// splitMethod 拆分 /package.Service/Method 形式的方法名。
+func splitMethod(fullMethod string) (service, method string) {
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+	if idx := strings.LastIndex(fullMethod, "/"); idx >= 0 {
+		return fullMethod[:idx], fullMethod[idx+1:]
+	}
+	return "", fullMethod
+}
+
+func NewClientConn(cc grpc.ClientConnInterface, tp trace.TracerProvider) *ClientConn {
+	return &ClientConn{
+		cc:     cc,
+		tracer: tp.Tracer(tracerName),
+	}
+}
@@ -0,0 +1,66 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type stubClientConn struct {
+	grpc.ClientConnInterface
+
+	err error
+	md  metadata.MD
+}
+
+func (c *stubClientConn) Invoke(ctx context.Context, _ string, _ any, _ any, _ ...grpc.CallOption) error {
+	c.md, _ = metadata.FromOutgoingContext(ctx)
+	return c.err
+}
+
+func TestClientConn_Invoke(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	tcs := []struct {
+		name       string
+		err        error
+		wantStatus codes.Code
+	}{
+		{name: "ok", wantStatus: codes.Unset},
+		{name: "error", err: status.Error(grpccodes.Unavailable, "mock unavailable"), wantStatus: codes.Error},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+			cc := &stubClientConn{err: tc.err}
+			err := NewClientConn(cc, tp).Invoke(context.Background(), "/client.v1.CallbackService/Callback", nil, nil)
+			assert.Equal(t, tc.err, err)
+
+			spans := recorder.Ended()
+			require.Len(t, spans, 1)
+			assert.Equal(t, "client.v1.CallbackService/Callback", spans[0].Name())
+			assert.Equal(t, trace.SpanKindClient, spans[0].SpanKind())
+			assert.Equal(t, tc.wantStatus, spans[0].Status().Code)
+
+			// 服务端能从 metadata 中还原出同一条链路。
+			traceparent := cc.md.Get("traceparent")
+			require.Len(t, traceparent, 1)
+			assert.Contains(t, traceparent[0], spans[0].SpanContext().TraceID().String())
+		})
+	}
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/JrMarcco/kuryr/internal/domain"
 	"github.com/JrMarcco/kuryr/internal/errs"
+	"github.com/JrMarcco/kuryr/internal/pkg/pubsub"
 	pkgsql "github.com/JrMarcco/kuryr/internal/pkg/sql"
 	"github.com/JrMarcco/kuryr/internal/repository/cache"
 	"github.com/JrMarcco/kuryr/internal/repository/dao"
@@ -29,6 +31,8 @@ type DefaultBizConfigRepo struct {
 	localCache cache.BizConfigCache
 	redisCache cache.BizConfigCache
 
+	bus pubsub.Bus[domain.BizConfigEvent]
+
 	logger *zap.Logger
 }
 
@@ -44,13 +48,18 @@ func (r *DefaultBizConfigRepo) Save(ctx context.Context, bizConfig domain.BizCon
 		r.logger.Error("[biz config] failed to set biz config to redis cache", zap.Error(err))
 	}
 
-	// 注意：
-	// 	如果通过 配置中心 / MQ 来同步本地缓存
-	// 	这里则需要 更新配置中心 / 发送 MQ 消息
 	err = r.localCache.Set(ctx, d)
 	if err != nil {
 		r.logger.Error("[biz config] failed to set biz config to local cache", zap.Error(err))
 	}
+
+	// 通知其他实例刷新本地缓存的业务方配置
+	r.publish(ctx, domain.BizConfigEvent{
+		BizId:     d.BizId,
+		Action:    domain.BizConfigActionSave,
+		Version:   d.UpdatedAt,
+		BizConfig: &d,
+	})
 	return d, nil
 }
 
@@ -66,14 +75,18 @@ func (r *DefaultBizConfigRepo) Update(ctx context.Context, bizConfig domain.BizC
 		r.logger.Error("[biz config] failed to set biz config to redis cache", zap.Error(err))
 	}
 
-	// 注意：
-	// 	如果通过 配置中心 / MQ 来同步本地缓存
-	// 	这里则需要 更新配置中心 / 发送 MQ 消息
 	err = r.localCache.Set(ctx, d)
 	if err != nil {
 		r.logger.Error("[biz config] failed to set biz config to local cache", zap.Error(err))
 	}
 
+	// 通知其他实例刷新本地缓存的业务方配置
+	r.publish(ctx, domain.BizConfigEvent{
+		BizId:     d.BizId,
+		Action:    domain.BizConfigActionUpdate,
+		Version:   d.UpdatedAt,
+		BizConfig: &d,
+	})
 	return d, nil
 }
 
@@ -127,9 +140,21 @@ func (r *DefaultBizConfigRepo) DeleteInTx(ctx context.Context, tx *gorm.DB, id u
 	}
 
 	r.clearCache(ctx, id)
+	r.publish(ctx, domain.BizConfigEvent{
+		BizId:   id,
+		Action:  domain.BizConfigActionDelete,
+		Version: time.Now().UnixMilli(),
+	})
 	return nil
 }
 
+func (r *DefaultBizConfigRepo) publish(ctx context.Context, event domain.BizConfigEvent) {
+	if err := r.bus.Publish(ctx, event); err != nil {
+		// 发布失败时其他实例的本地缓存依赖过期时间兜底
+		r.logger.Error("[biz config] failed to publish biz config event", zap.Any("event", event), zap.Error(err))
+	}
+}
+
 func (r *DefaultBizConfigRepo) clearCache(ctx context.Context, id uint64) {
 	// 删 redis 缓存
 	if err := r.redisCache.Del(ctx, id); err != nil {
@@ -179,6 +204,8 @@ func (r *DefaultBizConfigRepo) toDomain(entity dao.BizConfig) domain.BizConfig {
 		BizId:     entity.BizId,
 		OwnerType: domain.OwnerType(entity.OwnerType),
 		RateLimit: entity.RateLimit,
+		CreatedAt: entity.CreatedAt,
+		UpdatedAt: entity.UpdatedAt,
 	}
 
 	if entity.ChannelConfig.Valid {
@@ -197,12 +224,17 @@ func (r *DefaultBizConfigRepo) toDomain(entity dao.BizConfig) domain.BizConfig {
 }
 
 func NewDefaultBizConfigRepo(
-	dao dao.BizConfigDao, localCache cache.BizConfigCache, redisCache cache.BizConfigCache, logger *zap.Logger,
+	dao dao.BizConfigDao,
+	localCache cache.BizConfigCache,
+	redisCache cache.BizConfigCache,
+	bus pubsub.Bus[domain.BizConfigEvent],
+	logger *zap.Logger,
 ) *DefaultBizConfigRepo {
 	return &DefaultBizConfigRepo{
 		dao:        dao,
 		localCache: localCache,
 		redisCache: redisCache,
+		bus:        bus,
 		logger:     logger,
 	}
 }
@@ -0,0 +1,29 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/JrMarcco/kuryr/internal/domain"
+)
+
+const (
+	ChannelTplCacheKeyPrefix  = "channel_tpl"
+	ChannelTplDefaultLocalExp = 5 * time.Minute
+	ChannelTplDefaultRedisExp = 30 * time.Minute
+)
+
+// ChannelTplCache 渠道模板缓存。
+//
+// 缓存内容为模板及其当前激活版本 ( 包含激活版本关联的供应商 )，
+// 即 domain.ChannelTemplate.Versions 只包含激活版本。
+type ChannelTplCache interface {
+	Set(ctx context.Context, template domain.ChannelTemplate) error
+	Get(ctx context.Context, tplId uint64) (domain.ChannelTemplate, error)
+	Del(ctx context.Context, tplId uint64) error
+}
+
+func ChannelTplCacheKey(tplId uint64) string {
+	return fmt.Sprintf("%s:%d", ChannelTplCacheKeyPrefix, tplId)
+}
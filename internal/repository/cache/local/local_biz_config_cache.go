@@ -2,41 +2,82 @@ package local
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/JrMarcco/kuryr/internal/domain"
+	"github.com/JrMarcco/kuryr/internal/pkg/pubsub"
 	"github.com/JrMarcco/kuryr/internal/repository/cache"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 
 	gocache "github.com/patrickmn/go-cache"
 )
 
+const reconcileBatchSize = 100
+
 var _ cache.BizConfigCache = (*LBizConfigCache)(nil)
 
+// bizConfigEntry 本地缓存条目。
+//
+// version 为业务方配置的更新时间，只有版本不小于当前条目的配置才会覆盖本地缓存，
+// 避免乱序到达的旧事件覆盖新配置。
+// deleted 为 true 表示业务方配置已删除 ( 墓碑 )，用于拦截删除之后才到达的旧事件。
+type bizConfigEntry struct {
+	bizConfig domain.BizConfig
+	version   int64
+	deleted   bool
+}
+
+// LBizConfigCache 业务方配置本地缓存。
+//
+// 通过业务方配置变更事件同步各实例的本地缓存，并定期与 redis 对账兜底事件丢失的情况。
 type LBizConfigCache struct {
-	rc     redis.Cmdable
-	cc     *gocache.Cache
+	rc  redis.Cmdable
+	cc  *gocache.Cache
+	bus pubsub.Bus[domain.BizConfigEvent]
+
+	mu sync.Mutex // 保证 "比较版本 - 写入" 的原子性
+
+	reconcileInterval time.Duration
+	cancel            context.CancelFunc
+	wg                sync.WaitGroup
+
+	requestCounter *prometheus.CounterVec
+	syncCounter    *prometheus.CounterVec
+
 	logger *zap.Logger
 }
 
 func (c *LBizConfigCache) Set(_ context.Context, bizConfig domain.BizConfig) error {
-	key := cache.BizConfigCacheKey(bizConfig.BizId)
-	c.cc.Set(key, bizConfig, cache.BizConfigDefaultLocalExp)
+	c.apply(bizConfig.BizId, bizConfigEntry{bizConfig: bizConfig, version: bizConfig.UpdatedAt})
 	return nil
 }
 
 func (c *LBizConfigCache) Get(_ context.Context, bizId uint64) (domain.BizConfig, error) {
 	val, ok := c.cc.Get(cache.BizConfigCacheKey(bizId))
 	if !ok {
+		c.requestCounter.WithLabelValues("miss").Inc()
 		return domain.BizConfig{}, fmt.Errorf("[biz config] biz config not found")
 	}
-	bizConfig, ok := val.(domain.BizConfig)
+
+	entry, ok := val.(bizConfigEntry)
 	if !ok {
+		c.requestCounter.WithLabelValues("miss").Inc()
 		return domain.BizConfig{}, fmt.Errorf("[biz config] biz config type mismatch")
 	}
-	return bizConfig, nil
+	if entry.deleted {
+		c.requestCounter.WithLabelValues("miss").Inc()
+		return domain.BizConfig{}, fmt.Errorf("[biz config] biz config not found")
+	}
+
+	c.requestCounter.WithLabelValues("hit").Inc()
+	return entry.bizConfig, nil
 }
 
 func (c *LBizConfigCache) Del(_ context.Context, bizId uint64) error {
@@ -44,76 +85,180 @@ func (c *LBizConfigCache) Del(_ context.Context, bizId uint64) error {
 	return nil
 }
 
-// watchRedis 监听 redis 实时更新本地缓存
-func (c *LBizConfigCache) watchRedis(ctx context.Context) {
-	redisClient, ok := c.rc.(*redis.Client)
-	if !ok {
-		c.logger.Error("[biz config] failed to cast redis client to redis.Client")
-		return
+// apply 按版本写入本地缓存，返回是否写入成功。
+func (c *LBizConfigCache) apply(bizId uint64, entry bizConfigEntry) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := cache.BizConfigCacheKey(bizId)
+	if val, ok := c.cc.Get(key); ok {
+		if current, ok := val.(bizConfigEntry); ok && current.version > entry.version {
+			return false
+		}
 	}
+	c.cc.Set(key, entry, cache.BizConfigDefaultLocalExp)
+	return true
+}
 
-	watchKey := fmt.Sprintf("%s*", cache.BizConfigCacheKeyPrefix)
-	pubSub := redisClient.Subscribe(ctx, fmt.Sprintf("__keyspace@*__:%s", watchKey))
-	defer func(pubSub *redis.PubSub) {
-		err := pubSub.Close()
-		if err != nil {
-			c.logger.Error("[biz config] failed to close pub sub", zap.Error(err))
+// handleEvent 处理业务方配置变更事件。
+func (c *LBizConfigCache) handleEvent(_ context.Context, event domain.BizConfigEvent) {
+	entry := bizConfigEntry{version: event.Version}
+	switch event.Action {
+	case domain.BizConfigActionSave, domain.BizConfigActionUpdate:
+		if event.BizConfig == nil {
+			c.logger.Warn("[biz config] biz config event without payload", zap.Any("event", event))
+			c.cc.Delete(cache.BizConfigCacheKey(event.BizId))
+			c.syncCounter.WithLabelValues("event", "evicted").Inc()
+			return
 		}
-	}(pubSub)
+		entry.bizConfig = *event.BizConfig
+	case domain.BizConfigActionDelete:
+		entry.deleted = true
+	default:
+		c.logger.Warn("[biz config] unknown biz config event action", zap.Any("event", event))
+		return
+	}
+
+	if c.apply(event.BizId, entry) {
+		c.syncCounter.WithLabelValues("event", "applied").Inc()
+		return
+	}
+	c.syncCounter.WithLabelValues("event", "stale").Inc()
+}
+
+// Start 订阅业务方配置变更事件并开始定期对账。
+func (c *LBizConfigCache) Start() error {
+	c.bus.Subscribe(c.handleEvent)
+
+	if c.reconcileInterval <= 0 {
+		return nil
+	}
 
-	c.logger.Info("[biz config] start watching redis keyspace event", zap.String("pattern", watchKey))
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
 
-	ch := pubSub.Channel()
-	for {
-		select {
-		case msg := <-ch:
-			if msg == nil {
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+
+		ticker := time.NewTicker(c.reconcileInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				c.reconcile(ctx)
+			case <-ctx.Done():
 				return
 			}
-			c.handleKeyChange(ctx, msg)
-		case <-ctx.Done():
-			c.logger.Info("[biz config] stop watching redis keyspace event", zap.String("pattern", "biz_config*"))
-			return
 		}
-	}
+	}()
+	return nil
 }
 
-func (c *LBizConfigCache) handleKeyChange(ctx context.Context, msg *redis.Message) {
-	// 解析消息
-	// msg.Channel 格式: __keyspace@0__:biz_config_xxx
-	// msg.Payload 是操作类型: set, del, expire 等
-	parts := strings.Split(msg.Channel, ":")
-	if len(parts) < 2 {
-		return
+func (c *LBizConfigCache) Stop() error {
+	if c.cancel != nil {
+		c.cancel()
 	}
+	c.wg.Wait()
+	return nil
+}
 
-	key := parts[1]
-	op := msg.Payload
+// reconcile 将本地缓存的业务方配置与 redis 对账。
+//
+// redis 中版本更新的配置覆盖本地缓存，redis 中不存在的配置淘汰本地缓存 ( 下次读取时回源 )。
+func (c *LBizConfigCache) reconcile(ctx context.Context) {
+	prefix := cache.BizConfigCacheKeyPrefix + ":"
 
-	c.logger.Info("[biz config] redis keyspace event", zap.String("key", key), zap.String("operation", op))
+	keys := make([]string, 0, reconcileBatchSize)
+	for key, item := range c.cc.Items() {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if entry, ok := item.Object.(bizConfigEntry); ok && entry.deleted {
+			continue
+		}
 
-	switch op {
-	case "set":
-		val, err := c.rc.Get(ctx, key).Result()
-		if err != nil {
-			c.logger.Error("[biz config] failed to get biz config from redis", zap.String("key", key), zap.Error(err))
-			return
+		keys = append(keys, key)
+		if len(keys) == reconcileBatchSize {
+			c.reconcileKeys(ctx, keys)
+			keys = keys[:0]
 		}
-		// 更新本地缓存
-		c.cc.Set(key, val, cache.BizConfigDefaultLocalExp)
-	case "del":
-		c.cc.Delete(key)
+	}
+	if len(keys) > 0 {
+		c.reconcileKeys(ctx, keys)
 	}
 }
 
-func NewLBizConfigCache(rc redis.Cmdable, cc *gocache.Cache, logger *zap.Logger) *LBizConfigCache {
-	bizConfigCache := &LBizConfigCache{
-		rc:     rc,
-		cc:     cc,
-		logger: logger,
+func (c *LBizConfigCache) reconcileKeys(ctx context.Context, keys []string) {
+	vals, err := c.rc.MGet(ctx, keys...).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		c.logger.Error("[biz config] failed to get biz configs from redis", zap.Error(err))
+		return
+	}
+
+	for i, key := range keys {
+		if i >= len(vals) || vals[i] == nil {
+			c.cc.Delete(key)
+			c.syncCounter.WithLabelValues("reconcile", "evicted").Inc()
+			continue
+		}
+
+		str, ok := vals[i].(string)
+		if !ok {
+			continue
+		}
+
+		var bizConfig domain.BizConfig
+		if err = json.Unmarshal([]byte(str), &bizConfig); err != nil {
+			c.logger.Error("[biz config] failed to unmarshal biz config from redis", zap.String("key", key), zap.Error(err))
+			c.cc.Delete(key)
+			c.syncCounter.WithLabelValues("reconcile", "evicted").Inc()
+			continue
+		}
+
+		if val, ok := c.cc.Get(key); ok {
+			if current, ok := val.(bizConfigEntry); ok && current.version >= bizConfig.UpdatedAt {
+				continue
+			}
+		}
+		if c.apply(bizConfig.BizId, bizConfigEntry{bizConfig: bizConfig, version: bizConfig.UpdatedAt}) {
+			c.syncCounter.WithLabelValues("reconcile", "applied").Inc()
+		}
 	}
+}
 
-	go bizConfigCache.watchRedis(context.Background())
+func NewLBizConfigCache(
+	rc redis.Cmdable,
+	cc *gocache.Cache,
+	bus pubsub.Bus[domain.BizConfigEvent],
+	reconcileInterval time.Duration,
+	registerer prometheus.Registerer,
+	logger *zap.Logger,
+) *LBizConfigCache {
+	requestCounter := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kuryr_biz_config_local_cache_request_count",
+			Help: "biz config local cache request count by result ( hit / miss )",
+		},
+		[]string{"result"},
+	)
+	syncCounter := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kuryr_biz_config_local_cache_sync_count",
+			Help: "biz config local cache sync count by source ( event / reconcile ) and result",
+		},
+		[]string{"source", "result"},
+	)
+	registerer.MustRegister(requestCounter, syncCounter)
 
-	return bizConfigCache
+	return &LBizConfigCache{
+		rc:                rc,
+		cc:                cc,
+		bus:               bus,
+		reconcileInterval: reconcileInterval,
+		requestCounter:    requestCounter,
+		syncCounter:       syncCounter,
+		logger:            logger,
+	}
 }
@@ -0,0 +1,151 @@
+package local
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/JrMarcco/kuryr/internal/domain"
+	"github.com/JrMarcco/kuryr/internal/pkg/pubsub"
+	"github.com/JrMarcco/kuryr/internal/repository/cache"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	gocache "github.com/patrickmn/go-cache"
+)
+
+// stubRedis 只实现 MGet，按 key 返回预置的值。
+type stubRedis struct {
+	redis.Cmdable
+	vals map[string]string
+}
+
+func (r *stubRedis) MGet(ctx context.Context, keys ...string) *redis.SliceCmd {
+	res := make([]any, 0, len(keys))
+	for _, key := range keys {
+		if val, ok := r.vals[key]; ok {
+			res = append(res, val)
+			continue
+		}
+		res = append(res, nil)
+	}
+	return redis.NewSliceResult(res, nil)
+}
+
+type stubBus[T any] struct {
+	handlers []pubsub.Handler[T]
+}
+
+func (b *stubBus[T]) Publish(ctx context.Context, event T) error {
+	for _, handler := range b.handlers {
+		handler(ctx, event)
+	}
+	return nil
+}
+
+func (b *stubBus[T]) Subscribe(handler pubsub.Handler[T]) {
+	b.handlers = append(b.handlers, handler)
+}
+
+func TestLBizConfigCache_HandleEvent(t *testing.T) {
+	t.Parallel()
+
+	bus := &stubBus[domain.BizConfigEvent]{}
+	c := NewLBizConfigCache(&stubRedis{}, gocache.New(gocache.NoExpiration, 0), bus, 0, prometheus.NewRegistry(), zap.NewNop())
+	require.NoError(t, c.Start())
+
+	ctx := context.Background()
+
+	_, err := c.Get(ctx, 1)
+	assert.Error(t, err)
+
+	// 新版本配置写入本地缓存。
+	err = bus.Publish(ctx, domain.BizConfigEvent{
+		BizId:     1,
+		Action:    domain.BizConfigActionUpdate,
+		Version:   200,
+		BizConfig: &domain.BizConfig{BizId: 1, RateLimit: 20, UpdatedAt: 200},
+	})
+	require.NoError(t, err)
+
+	bizConfig, err := c.Get(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int32(20), bizConfig.RateLimit)
+
+	// 乱序到达的旧版本配置被丢弃。
+	err = bus.Publish(ctx, domain.BizConfigEvent{
+		BizId:     1,
+		Action:    domain.BizConfigActionSave,
+		Version:   100,
+		BizConfig: &domain.BizConfig{BizId: 1, RateLimit: 10, UpdatedAt: 100},
+	})
+	require.NoError(t, err)
+
+	bizConfig, err = c.Get(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int32(20), bizConfig.RateLimit)
+
+	// 删除后留下墓碑，拦截删除之前的旧配置。
+	err = bus.Publish(ctx, domain.BizConfigEvent{BizId: 1, Action: domain.BizConfigActionDelete, Version: 300})
+	require.NoError(t, err)
+	require.NoError(t, c.Set(ctx, domain.BizConfig{BizId: 1, RateLimit: 20, UpdatedAt: 200}))
+
+	_, err = c.Get(ctx, 1)
+	assert.Error(t, err)
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(c.requestCounter.WithLabelValues("hit")))
+	assert.Equal(t, float64(2), testutil.ToFloat64(c.requestCounter.WithLabelValues("miss")))
+	assert.Equal(t, float64(2), testutil.ToFloat64(c.syncCounter.WithLabelValues("event", "applied")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(c.syncCounter.WithLabelValues("event", "stale")))
+}
+
+func TestLBizConfigCache_Reconcile(t *testing.T) {
+	t.Parallel()
+
+	newer, err := json.Marshal(domain.BizConfig{BizId: 1, RateLimit: 20, UpdatedAt: 200})
+	require.NoError(t, err)
+	older, err := json.Marshal(domain.BizConfig{BizId: 2, RateLimit: 10, UpdatedAt: 100})
+	require.NoError(t, err)
+
+	rc := &stubRedis{
+		vals: map[string]string{
+			cache.BizConfigCacheKey(1): string(newer),
+			cache.BizConfigCacheKey(2): string(older),
+		},
+	}
+	c := NewLBizConfigCache(rc, gocache.New(gocache.NoExpiration, 0), &stubBus[domain.BizConfigEvent]{}, 0, prometheus.NewRegistry(), zap.NewNop())
+
+	ctx := context.Background()
+	require.NoError(t, c.Set(ctx, domain.BizConfig{BizId: 1, RateLimit: 10, UpdatedAt: 100}))
+	require.NoError(t, c.Set(ctx, domain.BizConfig{BizId: 2, RateLimit: 20, UpdatedAt: 200}))
+	require.NoError(t, c.Set(ctx, domain.BizConfig{BizId: 3, RateLimit: 30, UpdatedAt: 300}))
+
+	c.reconcile(ctx)
+
+	tcs := []struct {
+		name          string
+		bizId         uint64
+		wantRateLimit int32
+		wantErr       bool
+	}{
+		{name: "refreshed by newer redis config", bizId: 1, wantRateLimit: 20},
+		{name: "kept when redis config is older", bizId: 2, wantRateLimit: 20},
+		{name: "evicted when missing in redis", bizId: 3, wantErr: true},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			bizConfig, err := c.Get(ctx, tc.bizId)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantRateLimit, bizConfig.RateLimit)
+		})
+	}
+}
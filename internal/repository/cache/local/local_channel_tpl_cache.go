@@ -0,0 +1,67 @@
+package local
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/JrMarcco/kuryr/internal/domain"
+	"github.com/JrMarcco/kuryr/internal/pkg/pubsub"
+	"github.com/JrMarcco/kuryr/internal/repository/cache"
+
+	gocache "github.com/patrickmn/go-cache"
+)
+
+var _ cache.ChannelTplCache = (*LChannelTplCache)(nil)
+
+// LChannelTplCache 渠道模板本地缓存。
+//
+// 通过渠道模板变更事件淘汰各实例的本地缓存，下次读取时再从 redis 加载，
+// 事件丢失时依赖本地缓存的过期时间兜底。
+type LChannelTplCache struct {
+	cc  *gocache.Cache
+	bus pubsub.Bus[domain.ChannelTplEvent]
+}
+
+func (c *LChannelTplCache) Set(_ context.Context, template domain.ChannelTemplate) error {
+	c.cc.Set(cache.ChannelTplCacheKey(template.Id), template, cache.ChannelTplDefaultLocalExp)
+	return nil
+}
+
+func (c *LChannelTplCache) Get(_ context.Context, tplId uint64) (domain.ChannelTemplate, error) {
+	val, ok := c.cc.Get(cache.ChannelTplCacheKey(tplId))
+	if !ok {
+		return domain.ChannelTemplate{}, fmt.Errorf("[kuryr] channel template not found in local cache")
+	}
+	template, ok := val.(domain.ChannelTemplate)
+	if !ok {
+		return domain.ChannelTemplate{}, fmt.Errorf("[kuryr] channel template type mismatch in local cache")
+	}
+	return template, nil
+}
+
+func (c *LChannelTplCache) Del(_ context.Context, tplId uint64) error {
+	c.cc.Delete(cache.ChannelTplCacheKey(tplId))
+	return nil
+}
+
+// handleEvent 处理渠道模板变更事件。
+//
+// 本地缓存只做淘汰，不在这里回源，避免事件风暴时放大 redis 请求。
+func (c *LChannelTplCache) handleEvent(_ context.Context, event domain.ChannelTplEvent) {
+	for _, tplId := range event.TplIds {
+		c.cc.Delete(cache.ChannelTplCacheKey(tplId))
+	}
+}
+
+// Start 订阅渠道模板变更事件。
+func (c *LChannelTplCache) Start() error {
+	c.bus.Subscribe(c.handleEvent)
+	return nil
+}
+
+func NewLChannelTplCache(cc *gocache.Cache, bus pubsub.Bus[domain.ChannelTplEvent]) *LChannelTplCache {
+	return &LChannelTplCache{
+		cc:  cc,
+		bus: bus,
+	}
+}
@@ -0,0 +1,37 @@
+package local
+
+import (
+	"context"
+	"testing"
+
+	"github.com/JrMarcco/kuryr/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	gocache "github.com/patrickmn/go-cache"
+)
+
+func TestLChannelTplCache_HandleEvent(t *testing.T) {
+	t.Parallel()
+
+	bus := &stubBus[domain.ChannelTplEvent]{}
+	c := NewLChannelTplCache(gocache.New(gocache.NoExpiration, 0), bus)
+	require.NoError(t, c.Start())
+
+	ctx := context.Background()
+	for _, id := range []uint64{1, 2, 3} {
+		require.NoError(t, c.Set(ctx, domain.ChannelTemplate{Id: id}))
+	}
+
+	// 其他实例变更模板后广播事件，淘汰对应的本地缓存。
+	require.NoError(t, bus.Publish(ctx, domain.ChannelTplEvent{TplIds: []uint64{1, 2}}))
+
+	_, err := c.Get(ctx, 1)
+	assert.Error(t, err)
+	_, err = c.Get(ctx, 2)
+	assert.Error(t, err)
+
+	template, err := c.Get(ctx, 3)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), template.Id)
+}
@@ -0,0 +1,53 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/JrMarcco/kuryr/internal/domain"
+	"github.com/JrMarcco/kuryr/internal/repository/cache"
+	"github.com/redis/go-redis/v9"
+)
+
+var _ cache.ChannelTplCache = (*RChannelTplCache)(nil)
+
+type RChannelTplCache struct {
+	rc redis.Cmdable
+}
+
+func (c *RChannelTplCache) Set(ctx context.Context, template domain.ChannelTemplate) error {
+	data, err := json.Marshal(template)
+	if err != nil {
+		return fmt.Errorf("[kuryr] failed to marshal channel template to json: %w", err)
+	}
+
+	err = c.rc.Set(ctx, cache.ChannelTplCacheKey(template.Id), data, cache.ChannelTplDefaultRedisExp).Err()
+	if err != nil {
+		return fmt.Errorf("[kuryr] failed to set channel template to redis: %w", err)
+	}
+	return nil
+}
+
+func (c *RChannelTplCache) Get(ctx context.Context, tplId uint64) (domain.ChannelTemplate, error) {
+	str, err := c.rc.Get(ctx, cache.ChannelTplCacheKey(tplId)).Result()
+	if err != nil {
+		return domain.ChannelTemplate{}, fmt.Errorf("[kuryr] failed to get channel template from redis: %w", err)
+	}
+
+	var template domain.ChannelTemplate
+	if err = json.Unmarshal([]byte(str), &template); err != nil {
+		return domain.ChannelTemplate{}, fmt.Errorf("[kuryr] failed to unmarshal channel template from redis: %w", err)
+	}
+	return template, nil
+}
+
+func (c *RChannelTplCache) Del(ctx context.Context, tplId uint64) error {
+	return c.rc.Del(ctx, cache.ChannelTplCacheKey(tplId)).Err()
+}
+
+func NewRChannelTplCache(rc redis.Cmdable) *RChannelTplCache {
+	return &RChannelTplCache{
+		rc: rc,
+	}
+}
@@ -6,28 +6,42 @@ import (
 	"github.com/JrMarcco/easy-kit/slice"
 	"github.com/JrMarcco/kuryr/internal/domain"
 	"github.com/JrMarcco/kuryr/internal/pkg/sharding"
+	pkgsql "github.com/JrMarcco/kuryr/internal/pkg/sql"
 	"github.com/JrMarcco/kuryr/internal/repository/dao"
 )
 
 type CallbackLogRepo interface {
 	Save(ctx context.Context, log domain.CallbackLog) error
+	BatchUpsert(ctx context.Context, dst sharding.Dst, logs []domain.CallbackLog) error
 
 	BatchUpdate(ctx context.Context, dst sharding.Dst, logs []domain.CallbackLog) error
+	ResetFailures(ctx context.Context, dst sharding.Dst, bizId uint64, ids []uint64) (int64, error)
+	ResetFailuresByTime(ctx context.Context, dst sharding.Dst, bizId uint64, startTime, endTime int64) (int64, error)
+	SaveReplayLog(ctx context.Context, log domain.CallbackReplayLog) error
 
-	FindByNotificationIds(ctx context.Context, notificationIds []uint64) ([]domain.CallbackLog, error)
+	FindByNotificationIds(ctx context.Context, dst sharding.Dst, notificationIds []string) ([]domain.CallbackLog, error)
+	FindFailures(ctx context.Context, dst sharding.Dst, bizId uint64, startTime, endTime int64, cursor uint64, limit int) ([]domain.CallbackLog, error)
 	BatchFindByTime(ctx context.Context, dst sharding.Dst, startTime int64, startId uint64, batchSize int) ([]domain.CallbackLog, uint64, error)
 }
 
 var _ CallbackLogRepo = (*DefaultCallbackLogRepo)(nil)
 
 type DefaultCallbackLogRepo struct {
-	dao dao.CallbackLogDao
+	dao          dao.CallbackLogDao
+	replayLogDao dao.CallbackReplayLogDao
 }
 
 func (r *DefaultCallbackLogRepo) Save(ctx context.Context, log domain.CallbackLog) error {
 	return r.dao.Save(ctx, r.toEntity(log))
 }
 
+func (r *DefaultCallbackLogRepo) BatchUpsert(ctx context.Context, dst sharding.Dst, logs []domain.CallbackLog) error {
+	entities := slice.Map(logs, func(_ int, log domain.CallbackLog) dao.CallbackLog {
+		return r.toEntity(log)
+	})
+	return r.dao.BatchUpsert(ctx, dst, entities)
+}
+
 func (r *DefaultCallbackLogRepo) BatchUpdate(ctx context.Context, dst sharding.Dst, logs []domain.CallbackLog) error {
 	entities := slice.Map(logs, func(_ int, log domain.CallbackLog) dao.CallbackLog {
 		return r.toEntity(log)
@@ -35,9 +49,50 @@ func (r *DefaultCallbackLogRepo) BatchUpdate(ctx context.Context, dst sharding.D
 	return r.dao.BatchUpdate(ctx, dst, entities)
 }
 
-func (r *DefaultCallbackLogRepo) FindByNotificationIds(ctx context.Context, notificationIds []uint64) ([]domain.CallbackLog, error) {
-	// TODO: implement me
-	panic("implement me")
+func (r *DefaultCallbackLogRepo) ResetFailures(ctx context.Context, dst sharding.Dst, bizId uint64, ids []uint64) (int64, error) {
+	return r.dao.ResetFailures(ctx, dst, bizId, ids)
+}
+
+func (r *DefaultCallbackLogRepo) ResetFailuresByTime(ctx context.Context, dst sharding.Dst, bizId uint64, startTime, endTime int64) (int64, error) {
+	return r.dao.ResetFailuresByTime(ctx, dst, bizId, startTime, endTime)
+}
+
+func (r *DefaultCallbackLogRepo) SaveReplayLog(ctx context.Context, log domain.CallbackReplayLog) error {
+	return r.replayLogDao.Save(ctx, dao.CallbackReplayLog{
+		BizId: log.BizId,
+		CallbackLogIds: pkgsql.JsonColumn[[]uint64]{
+			Val:   log.CallbackLogIds,
+			Valid: len(log.CallbackLogIds) > 0,
+		},
+		StartTime:     log.StartTime,
+		EndTime:       log.EndTime,
+		ReplayedCount: log.ReplayedCount,
+		OperatorId:    log.OperatorId,
+	})
+}
+
+func (r *DefaultCallbackLogRepo) FindByNotificationIds(ctx context.Context, dst sharding.Dst, notificationIds []string) ([]domain.CallbackLog, error) {
+	entities, err := r.dao.FindByNotificationIds(ctx, dst, notificationIds)
+	if err != nil {
+		return nil, err
+	}
+
+	return slice.Map(entities, func(_ int, entity dao.CallbackLog) domain.CallbackLog {
+		return r.toDomain(entity)
+	}), nil
+}
+
+func (r *DefaultCallbackLogRepo) FindFailures(
+	ctx context.Context, dst sharding.Dst, bizId uint64, startTime, endTime int64, cursor uint64, limit int,
+) ([]domain.CallbackLog, error) {
+	entities, err := r.dao.FindFailures(ctx, dst, bizId, startTime, endTime, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return slice.Map(entities, func(_ int, entity dao.CallbackLog) domain.CallbackLog {
+		return r.toDomain(entity)
+	}), nil
 }
 
 func (r *DefaultCallbackLogRepo) BatchFindByTime(ctx context.Context, dst sharding.Dst, startTime int64, startId uint64, batchSize int) ([]domain.CallbackLog, uint64, error) {
@@ -72,16 +127,21 @@ func (r *DefaultCallbackLogRepo) toDomain(entity dao.CallbackLog) domain.Callbac
 		BizKey: entity.BizKey,
 		Notification: domain.Notification{
 			Id:         entity.NotificationId,
+			BizId:      entity.BizId,
+			BizKey:     entity.BizKey,
 			SendStatus: domain.SendStatus(entity.NotificationStatus),
 		},
 		RetriedTimes: entity.RetriedTimes,
 		NextRetryAt:  entity.NextRetryAt,
 		Status:       domain.CallbackLogStatus(entity.CallbackStatus),
+		CreatedAt:    entity.CreatedAt,
+		UpdatedAt:    entity.UpdatedAt,
 	}
 }
 
-func NewCallbackLogRepo(dao dao.CallbackLogDao) CallbackLogRepo {
+func NewCallbackLogRepo(dao dao.CallbackLogDao, replayLogDao dao.CallbackReplayLogDao) CallbackLogRepo {
 	return &DefaultCallbackLogRepo{
-		dao: dao,
+		dao:          dao,
+		replayLogDao: replayLogDao,
 	}
 }
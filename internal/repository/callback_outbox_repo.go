@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/JrMarcco/easy-kit/slice"
+	"github.com/JrMarcco/kuryr/internal/domain"
+	"github.com/JrMarcco/kuryr/internal/errs"
+	"github.com/JrMarcco/kuryr/internal/repository/dao"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type CallbackOutboxRepo interface {
+	FindPending(ctx context.Context, now int64, limit int) ([]domain.CallbackOutbox, error)
+	Delete(ctx context.Context, outboxes []domain.CallbackOutbox) error
+	Delay(ctx context.Context, notificationIds []string, nextRelayAt int64) error
+}
+
+var _ CallbackOutboxRepo = (*DefaultCallbackOutboxRepo)(nil)
+
+type DefaultCallbackOutboxRepo struct {
+	dao dao.CallbackOutboxDao
+}
+
+func (r *DefaultCallbackOutboxRepo) FindPending(ctx context.Context, now int64, limit int) ([]domain.CallbackOutbox, error) {
+	entities, err := r.dao.FindPending(ctx, now, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return slice.Map(entities, func(_ int, entity dao.CallbackOutbox) domain.CallbackOutbox {
+		return r.toDomain(entity)
+	}), nil
+}
+
+func (r *DefaultCallbackOutboxRepo) Delete(ctx context.Context, outboxes []domain.CallbackOutbox) error {
+	entities := make([]dao.CallbackOutbox, 0, len(outboxes))
+	for _, outbox := range outboxes {
+		id, err := bson.ObjectIDFromHex(outbox.NotificationId)
+		if err != nil {
+			return fmt.Errorf("%w: invalid notification id [ %s ]", errs.ErrInvalidParam, outbox.NotificationId)
+		}
+		entities = append(entities, dao.CallbackOutbox{Id: id, UpdatedAt: outbox.UpdatedAt})
+	}
+	return r.dao.Delete(ctx, entities)
+}
+
+func (r *DefaultCallbackOutboxRepo) Delay(ctx context.Context, notificationIds []string, nextRelayAt int64) error {
+	ids := make([]bson.ObjectID, 0, len(notificationIds))
+	for _, notificationId := range notificationIds {
+		id, err := bson.ObjectIDFromHex(notificationId)
+		if err != nil {
+			return fmt.Errorf("%w: invalid notification id [ %s ]", errs.ErrInvalidParam, notificationId)
+		}
+		ids = append(ids, id)
+	}
+	return r.dao.Delay(ctx, ids, nextRelayAt)
+}
+
+func (r *DefaultCallbackOutboxRepo) toDomain(entity dao.CallbackOutbox) domain.CallbackOutbox {
+	return domain.CallbackOutbox{
+		NotificationId: entity.Id.Hex(),
+		BizId:          entity.BizId,
+		BizKey:         entity.BizKey,
+		SendStatus:     domain.SendStatus(entity.SendStatus),
+		RelayedTimes:   entity.RelayedTimes,
+		NextRelayAt:    entity.NextRelayAt,
+		CreatedAt:      entity.CreatedAt,
+		UpdatedAt:      entity.UpdatedAt,
+	}
+}
+
+func NewDefaultCallbackOutboxRepo(dao dao.CallbackOutboxDao) *DefaultCallbackOutboxRepo {
+	return &DefaultCallbackOutboxRepo{
+		dao: dao,
+	}
+}
@@ -4,12 +4,19 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strconv"
 
 	"github.com/JrMarcco/easy-kit/slice"
 	"github.com/JrMarcco/kuryr/internal/domain"
 	"github.com/JrMarcco/kuryr/internal/errs"
 	pkggorm "github.com/JrMarcco/kuryr/internal/pkg/gorm"
+	"github.com/JrMarcco/kuryr/internal/pkg/pubsub"
+	pkgsql "github.com/JrMarcco/kuryr/internal/pkg/sql"
+	"github.com/JrMarcco/kuryr/internal/repository/cache"
 	"github.com/JrMarcco/kuryr/internal/repository/dao"
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
 	"gorm.io/gorm"
 )
 
@@ -18,24 +25,50 @@ type ChannelTplRepo interface {
 	DeleteTemplate(ctx context.Context, id uint64) error
 	// GetDetailById 查询详情，包含版本、供应商信息。
 	GetDetailById(ctx context.Context, id uint64) (domain.ChannelTemplate, error)
+	// FindActivatedById 查询模板及其激活版本 ( 包含激活版本关联的供应商 )，优先从缓存获取。
+	// 返回结果的 Versions 只包含激活版本，没有激活版本时为空。
+	FindActivatedById(ctx context.Context, id uint64) (domain.ChannelTemplate, error)
 	FindTemplateById(ctx context.Context, id uint64) (domain.ChannelTemplate, error)
 	FindTemplateByBizId(ctx context.Context, bizId uint64, param *pkggorm.PaginationParam) (*pkggorm.PaginationResult[domain.ChannelTemplate], error)
 
 	SaveVersion(ctx context.Context, version domain.ChannelTemplateVersion) (domain.ChannelTemplateVersion, error)
 	DeleteVersion(ctx context.Context, id uint64) error
-	ActivateVersion(ctx context.Context, templateId uint64, versionId uint64) error
+	// ActivateVersion 激活版本并记录激活历史。
+	ActivateVersion(ctx context.Context, log domain.ChannelTemplateActivationLog) error
+	// FindActivationLogByTplId 查询模板激活历史，按时间倒序排列。
+	FindActivationLogByTplId(ctx context.Context, tplId uint64) ([]domain.ChannelTemplateActivationLog, error)
 	FindVersionById(ctx context.Context, id uint64) (domain.ChannelTemplateVersion, error)
 	FindVersionByTplId(ctx context.Context, tplId uint64) ([]domain.ChannelTemplateVersion, error)
+	// AuditVersion 变更版本审批状态并记录审批历史。
+	AuditVersion(ctx context.Context, version domain.ChannelTemplateVersion, log domain.ChannelTemplateAuditLog) error
+	FindAuditLogByVersionId(ctx context.Context, versionId uint64) ([]domain.ChannelTemplateAuditLog, error)
 
 	SaveProviders(ctx context.Context, providers []domain.ChannelTemplateProvider) error
 	DeleteProvider(ctx context.Context, id uint64) error
 	FindProviderByVersionId(ctx context.Context, versionId uint64) ([]domain.ChannelTemplateProvider, error)
+	FindProviderByAuditStatus(ctx context.Context, auditStatus domain.AuditStatus) ([]domain.ChannelTemplateProvider, error)
+	// UpdateProviderAudit 更新供应商侧审批信息，包括供应商侧模板 id 与审批请求 id。
+	UpdateProviderAudit(ctx context.Context, provider domain.ChannelTemplateProvider) error
+	// UpdateProviderAuditStatus 更新供应商侧审批状态。
+	UpdateProviderAuditStatus(ctx context.Context, event domain.ProviderTplAuditEvent) error
+	// AcquireProviderSubmit 占用待审核供应商的提交权，防止同一个供应商被重复提交到供应商侧。
+	AcquireProviderSubmit(ctx context.Context, id uint64, leaseMillis int64) (bool, error)
+	// ReleaseProviderSubmit 释放提交权。
+	ReleaseProviderSubmit(ctx context.Context, id uint64) error
 }
 
 var _ ChannelTplRepo = (*DefaultChannelTplRepo)(nil)
 
 type DefaultChannelTplRepo struct {
-	dao dao.ChannelTplDao
+	dao        dao.ChannelTplDao
+	localCache cache.ChannelTplCache
+	redisCache cache.ChannelTplCache
+	bus        pubsub.Bus[domain.ChannelTplEvent]
+
+	// 缓存未命中时合并同一模板的并发回源请求
+	sfg singleflight.Group
+
+	logger *zap.Logger
 }
 
 func (r *DefaultChannelTplRepo) SaveTemplate(ctx context.Context, template domain.ChannelTemplate) (domain.ChannelTemplate, error) {
@@ -47,7 +80,11 @@ func (r *DefaultChannelTplRepo) SaveTemplate(ctx context.Context, template domai
 }
 
 func (r *DefaultChannelTplRepo) DeleteTemplate(ctx context.Context, id uint64) error {
-	return r.dao.DeleteTemplate(ctx, id)
+	if err := r.dao.DeleteTemplate(ctx, id); err != nil {
+		return err
+	}
+	r.invalidate(ctx, id)
+	return nil
 }
 
 func (r *DefaultChannelTplRepo) GetDetailById(ctx context.Context, id uint64) (domain.ChannelTemplate, error) {
@@ -74,18 +111,18 @@ func (r *DefaultChannelTplRepo) GetDetailById(ctx context.Context, id uint64) (d
 func (r *DefaultChannelTplRepo) getTemplates(ctx context.Context, entities []dao.ChannelTemplate) ([]domain.ChannelTemplate, error) {
 	ids := make([]uint64, 0, len(entities))
 	for i := range entities {
-		ids[i] = entities[i].Id
+		ids = append(ids, entities[i].Id)
 	}
 
 	// 获取关联版本
-	versionEntities, err := r.dao.FindVersionByIds(ctx, ids)
+	versionEntities, err := r.dao.FindVersionByTplIds(ctx, ids)
 	if err != nil {
 		return nil, err
 	}
 
 	versionIds := make([]uint64, 0, len(versionEntities))
 	for i := range versionEntities {
-		versionIds[i] = versionEntities[i].Id
+		versionIds = append(versionIds, versionEntities[i].Id)
 	}
 
 	// 获取关联供应商
@@ -119,6 +156,83 @@ func (r *DefaultChannelTplRepo) getTemplates(ctx context.Context, entities []dao
 	return res, nil
 }
 
+func (r *DefaultChannelTplRepo) FindActivatedById(ctx context.Context, id uint64) (domain.ChannelTemplate, error) {
+	// 从本地缓存获取
+	template, err := r.localCache.Get(ctx, id)
+	if err == nil {
+		return template, nil
+	}
+
+	// 从 redis 获取
+	template, err = r.redisCache.Get(ctx, id)
+	if err == nil {
+		if err = r.localCache.Set(ctx, template); err != nil {
+			r.logger.Error("[kuryr] failed to set channel template to local cache", zap.Error(err))
+		}
+		return template, nil
+	}
+
+	// 从 db 获取并设置 redis 缓存 + 本地缓存
+	val, err, _ := r.sfg.Do(strconv.FormatUint(id, 10), func() (any, error) {
+		// 回源不受单个调用方取消的影响，避免一个请求取消导致合并的请求全部失败
+		return r.loadActivated(context.WithoutCancel(ctx), id)
+	})
+	if err != nil {
+		return domain.ChannelTemplate{}, err
+	}
+	return val.(domain.ChannelTemplate), nil
+}
+
+// loadActivated 从 db 加载模板及其激活版本并写入缓存。
+func (r *DefaultChannelTplRepo) loadActivated(ctx context.Context, id uint64) (domain.ChannelTemplate, error) {
+	template, err := r.FindTemplateById(ctx, id)
+	if err != nil {
+		return domain.ChannelTemplate{}, err
+	}
+
+	if template.ActivatedVersionId != 0 {
+		version, err := r.FindVersionById(ctx, template.ActivatedVersionId)
+		if err != nil {
+			return domain.ChannelTemplate{}, err
+		}
+		if version.Providers, err = r.FindProviderByVersionId(ctx, version.Id); err != nil {
+			return domain.ChannelTemplate{}, err
+		}
+		template.Versions = []domain.ChannelTemplateVersion{version}
+	}
+
+	if err = r.redisCache.Set(ctx, template); err != nil {
+		r.logger.Error("[kuryr] failed to set channel template to redis cache", zap.Error(err))
+	}
+	if err = r.localCache.Set(ctx, template); err != nil {
+		r.logger.Error("[kuryr] failed to set channel template to local cache", zap.Error(err))
+	}
+	return template, nil
+}
+
+// invalidate 淘汰模板缓存。
+//
+// 先删除 redis 缓存再删除本地缓存，再广播渠道模板变更事件淘汰其他实例的本地缓存。
+// 淘汰失败只记录日志，依赖缓存过期时间兜底。
+func (r *DefaultChannelTplRepo) invalidate(ctx context.Context, tplIds ...uint64) {
+	if len(tplIds) == 0 {
+		return
+	}
+
+	for _, tplId := range tplIds {
+		if err := r.redisCache.Del(ctx, tplId); err != nil {
+			r.logger.Error("[kuryr] failed to delete channel template from redis cache", zap.Uint64("tpl_id", tplId), zap.Error(err))
+		}
+		if err := r.localCache.Del(ctx, tplId); err != nil {
+			r.logger.Error("[kuryr] failed to delete channel template from local cache", zap.Uint64("tpl_id", tplId), zap.Error(err))
+		}
+	}
+
+	if err := r.bus.Publish(ctx, domain.ChannelTplEvent{TplIds: tplIds}); err != nil {
+		r.logger.Error("[kuryr] failed to publish channel template event", zap.Uint64s("tpl_ids", tplIds), zap.Error(err))
+	}
+}
+
 func (r *DefaultChannelTplRepo) FindTemplateById(ctx context.Context, id uint64) (domain.ChannelTemplate, error) {
 	entity, err := r.dao.FindTemplateById(ctx, id)
 	if err != nil {
@@ -155,6 +269,7 @@ func (r *DefaultChannelTplRepo) SaveVersion(ctx context.Context, version domain.
 	if err != nil {
 		return domain.ChannelTemplateVersion{}, err
 	}
+	r.invalidate(ctx, entity.TplId)
 	return r.toVersionDomain(entity), nil
 }
 
@@ -162,14 +277,30 @@ func (r *DefaultChannelTplRepo) DeleteVersion(ctx context.Context, id uint64) er
 	return r.dao.DeleteVersion(ctx, id)
 }
 
-func (r *DefaultChannelTplRepo) ActivateVersion(ctx context.Context, templateId uint64, versionId uint64) error {
-	return r.dao.ActivateVersion(ctx, templateId, versionId)
+func (r *DefaultChannelTplRepo) ActivateVersion(ctx context.Context, log domain.ChannelTemplateActivationLog) error {
+	if err := r.dao.ActivateVersion(ctx, r.toActivationLogEntity(log)); err != nil {
+		return err
+	}
+	r.invalidate(ctx, log.TplId)
+	return nil
 }
 
-func (r *DefaultChannelTplRepo) FindVersionById(ctx context.Context, id uint64) (domain.ChannelTemplateVersion, error) {
+func (r *DefaultChannelTplRepo) FindActivationLogByTplId(ctx context.Context, tplId uint64) ([]domain.ChannelTemplateActivationLog, error) {
+	entities, err := r.dao.FindActivationLogByTplId(ctx, tplId)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(entities, func(_ int, entity dao.ChannelTemplateActivationLog) domain.ChannelTemplateActivationLog {
+		return r.toActivationLogDomain(entity)
+	}), nil
+}
 
+func (r *DefaultChannelTplRepo) FindVersionById(ctx context.Context, id uint64) (domain.ChannelTemplateVersion, error) {
 	entity, err := r.dao.FindVersionById(ctx, id)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ChannelTemplateVersion{}, fmt.Errorf("%w: cannot find channel template version, id = %d", errs.ErrRecordNotFound, id)
+		}
 		return domain.ChannelTemplateVersion{}, err
 	}
 	return r.toVersionDomain(entity), nil
@@ -189,6 +320,24 @@ func (r *DefaultChannelTplRepo) FindVersionByTplId(ctx context.Context, tplId ui
 	}), nil
 }
 
+func (r *DefaultChannelTplRepo) AuditVersion(ctx context.Context, version domain.ChannelTemplateVersion, log domain.ChannelTemplateAuditLog) error {
+	if err := r.dao.AuditVersion(ctx, r.toVersionEntity(version), string(log.FromStatus), r.toAuditLogEntity(log)); err != nil {
+		return err
+	}
+	r.invalidate(ctx, version.TplId)
+	return nil
+}
+
+func (r *DefaultChannelTplRepo) FindAuditLogByVersionId(ctx context.Context, versionId uint64) ([]domain.ChannelTemplateAuditLog, error) {
+	entities, err := r.dao.FindAuditLogByVersionId(ctx, versionId)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(entities, func(_ int, entity dao.ChannelTemplateAuditLog) domain.ChannelTemplateAuditLog {
+		return r.toAuditLogDomain(entity)
+	}), nil
+}
+
 func (r *DefaultChannelTplRepo) SaveProviders(ctx context.Context, providers []domain.ChannelTemplateProvider) error {
 	entities := slice.Map(providers, func(_ int, provider domain.ChannelTemplateProvider) dao.ChannelTemplateProvider {
 		return r.toProviderEntity(provider)
@@ -201,7 +350,18 @@ func (r *DefaultChannelTplRepo) SaveProviders(ctx context.Context, providers []d
 		entities[i].LastReviewAt = 0
 	}
 
-	return r.dao.SaveProviders(ctx, entities)
+	if err := r.dao.SaveProviders(ctx, entities); err != nil {
+		return err
+	}
+
+	tplIds := make([]uint64, 0, len(providers))
+	for _, provider := range providers {
+		if !slices.Contains(tplIds, provider.TplId) {
+			tplIds = append(tplIds, provider.TplId)
+		}
+	}
+	r.invalidate(ctx, tplIds...)
+	return nil
 }
 
 func (r *DefaultChannelTplRepo) DeleteProvider(ctx context.Context, id uint64) error {
@@ -218,6 +378,43 @@ func (r *DefaultChannelTplRepo) FindProviderByVersionId(ctx context.Context, ver
 	}), nil
 }
 
+func (r *DefaultChannelTplRepo) FindProviderByAuditStatus(ctx context.Context, auditStatus domain.AuditStatus) ([]domain.ChannelTemplateProvider, error) {
+	entities, err := r.dao.FindProviderByAuditStatus(ctx, string(auditStatus))
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(entities, func(_ int, entity dao.ChannelTemplateProvider) domain.ChannelTemplateProvider {
+		return r.toProviderDomain(entity)
+	}), nil
+}
+
+func (r *DefaultChannelTplRepo) UpdateProviderAudit(ctx context.Context, provider domain.ChannelTemplateProvider) error {
+	if err := r.dao.UpdateProviderAudit(ctx, r.toProviderEntity(provider)); err != nil {
+		return err
+	}
+	r.invalidate(ctx, provider.TplId)
+	return nil
+}
+
+func (r *DefaultChannelTplRepo) AcquireProviderSubmit(ctx context.Context, id uint64, leaseMillis int64) (bool, error) {
+	return r.dao.AcquireProviderSubmit(ctx, id, string(domain.AuditStatusPending), leaseMillis)
+}
+
+func (r *DefaultChannelTplRepo) ReleaseProviderSubmit(ctx context.Context, id uint64) error {
+	return r.dao.ReleaseProviderSubmit(ctx, id, string(domain.AuditStatusPending))
+}
+
+func (r *DefaultChannelTplRepo) UpdateProviderAuditStatus(ctx context.Context, event domain.ProviderTplAuditEvent) error {
+	tplIds, err := r.dao.UpdateProviderAuditStatus(
+		ctx, event.ProviderId, event.ProviderTplId, string(event.AuditStatus), event.RejectionReason,
+	)
+	if err != nil {
+		return err
+	}
+	r.invalidate(ctx, tplIds...)
+	return nil
+}
+
 func (r *DefaultChannelTplRepo) toTemplateDomain(entity dao.ChannelTemplate) domain.ChannelTemplate {
 	return domain.ChannelTemplate{
 		Id:                 entity.Id,
@@ -255,6 +452,8 @@ func (r *DefaultChannelTplRepo) toVersionDomain(entity dao.ChannelTemplateVersio
 		VersionName:     entity.VersionName,
 		Signature:       entity.Signature,
 		Content:         entity.Content,
+		ParamSchema:     entity.ParamSchema.Val,
+		Variants:        entity.Variants.Val,
 		ApplyRemark:     entity.ApplyRemark,
 		AuditId:         entity.AuditId,
 		AuditorId:       entity.AuditorId,
@@ -269,11 +468,19 @@ func (r *DefaultChannelTplRepo) toVersionDomain(entity dao.ChannelTemplateVersio
 
 func (r *DefaultChannelTplRepo) toVersionEntity(version domain.ChannelTemplateVersion) dao.ChannelTemplateVersion {
 	return dao.ChannelTemplateVersion{
-		Id:              version.Id,
-		TplId:           version.TplId,
-		VersionName:     version.VersionName,
-		Signature:       version.Signature,
-		Content:         version.Content,
+		Id:          version.Id,
+		TplId:       version.TplId,
+		VersionName: version.VersionName,
+		Signature:   version.Signature,
+		Content:     version.Content,
+		ParamSchema: pkgsql.JsonColumn[domain.TplParamSchema]{
+			Val:   version.ParamSchema,
+			Valid: true,
+		},
+		Variants: pkgsql.JsonColumn[[]domain.TplVariant]{
+			Val:   version.Variants,
+			Valid: len(version.Variants) > 0,
+		},
 		ApplyRemark:     version.ApplyRemark,
 		AuditId:         version.AuditId,
 		AuditorId:       version.AuditorId,
@@ -294,6 +501,7 @@ func (r *DefaultChannelTplRepo) toProviderDomain(entity dao.ChannelTemplateProvi
 		ProviderId:      entity.ProviderId,
 		ProviderName:    entity.ProviderName,
 		ProviderChannel: domain.Channel(entity.ProviderChannel),
+		Locale:          entity.Locale,
 		ProviderTplId:   entity.ProviderTplId,
 		AuditRequestId:  entity.AuditRequestId,
 		AuditStatus:     domain.AuditStatus(entity.AuditStatus),
@@ -312,6 +520,7 @@ func (r *DefaultChannelTplRepo) toProviderEntity(provider domain.ChannelTemplate
 		ProviderId:      provider.ProviderId,
 		ProviderName:    provider.ProviderName,
 		ProviderChannel: int32(provider.ProviderChannel),
+		Locale:          provider.Locale,
 		ProviderTplId:   provider.ProviderTplId,
 		AuditRequestId:  provider.AuditRequestId,
 		AuditStatus:     string(provider.AuditStatus),
@@ -322,8 +531,70 @@ func (r *DefaultChannelTplRepo) toProviderEntity(provider domain.ChannelTemplate
 	}
 }
 
-func NewDefaultChannelTplRepo(dao dao.ChannelTplDao) *DefaultChannelTplRepo {
+func (r *DefaultChannelTplRepo) toAuditLogDomain(entity dao.ChannelTemplateAuditLog) domain.ChannelTemplateAuditLog {
+	return domain.ChannelTemplateAuditLog{
+		Id:           entity.Id,
+		TplId:        entity.TplId,
+		TplVersionId: entity.TplVersionId,
+		Action:       domain.AuditAction(entity.Action),
+		FromStatus:   domain.AuditStatus(entity.FromStatus),
+		ToStatus:     domain.AuditStatus(entity.ToStatus),
+		OperatorId:   entity.OperatorId,
+		Remark:       entity.Remark,
+		CreatedAt:    entity.CreatedAt,
+	}
+}
+
+func (r *DefaultChannelTplRepo) toAuditLogEntity(log domain.ChannelTemplateAuditLog) dao.ChannelTemplateAuditLog {
+	return dao.ChannelTemplateAuditLog{
+		Id:           log.Id,
+		TplId:        log.TplId,
+		TplVersionId: log.TplVersionId,
+		Action:       string(log.Action),
+		FromStatus:   string(log.FromStatus),
+		ToStatus:     string(log.ToStatus),
+		OperatorId:   log.OperatorId,
+		Remark:       log.Remark,
+		CreatedAt:    log.CreatedAt,
+	}
+}
+
+func (r *DefaultChannelTplRepo) toActivationLogDomain(entity dao.ChannelTemplateActivationLog) domain.ChannelTemplateActivationLog {
+	return domain.ChannelTemplateActivationLog{
+		Id:            entity.Id,
+		TplId:         entity.TplId,
+		FromVersionId: entity.FromVersionId,
+		ToVersionId:   entity.ToVersionId,
+		Action:        domain.ActivationAction(entity.Action),
+		OperatorId:    entity.OperatorId,
+		CreatedAt:     entity.CreatedAt,
+	}
+}
+
+func (r *DefaultChannelTplRepo) toActivationLogEntity(log domain.ChannelTemplateActivationLog) dao.ChannelTemplateActivationLog {
+	return dao.ChannelTemplateActivationLog{
+		Id:            log.Id,
+		TplId:         log.TplId,
+		FromVersionId: log.FromVersionId,
+		ToVersionId:   log.ToVersionId,
+		Action:        string(log.Action),
+		OperatorId:    log.OperatorId,
+		CreatedAt:     log.CreatedAt,
+	}
+}
+
+func NewDefaultChannelTplRepo(
+	dao dao.ChannelTplDao,
+	localCache cache.ChannelTplCache,
+	redisCache cache.ChannelTplCache,
+	bus pubsub.Bus[domain.ChannelTplEvent],
+	logger *zap.Logger,
+) *DefaultChannelTplRepo {
 	return &DefaultChannelTplRepo{
-		dao: dao,
+		dao:        dao,
+		localCache: localCache,
+		redisCache: redisCache,
+		bus:        bus,
+		logger:     logger,
 	}
 }
@@ -3,15 +3,12 @@ package dao
 import (
 	"context"
 	"fmt"
-	"strings"
-	"sync"
 	"time"
 
 	"github.com/JrMarcco/easy-kit/xsync"
 	"github.com/JrMarcco/kuryr/internal/domain"
 	"github.com/JrMarcco/kuryr/internal/pkg/idgen"
 	"github.com/JrMarcco/kuryr/internal/pkg/sharding"
-	"golang.org/x/sync/errgroup"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
@@ -45,10 +42,14 @@ func (CallbackLog) TableName() string {
 //	这里使用分库分表设计。
 type CallbackLogDao interface {
 	Save(ctx context.Context, log CallbackLog) error
+	BatchUpsert(ctx context.Context, dst sharding.Dst, logs []CallbackLog) error
 
 	BatchUpdate(ctx context.Context, dst sharding.Dst, logs []CallbackLog) error
+	ResetFailures(ctx context.Context, dst sharding.Dst, bizId uint64, ids []uint64) (int64, error)
+	ResetFailuresByTime(ctx context.Context, dst sharding.Dst, bizId uint64, startTime, endTime int64) (int64, error)
 
-	FindByNotificationIds(ctx context.Context, notificationIds []uint64) ([]CallbackLog, error)
+	FindByNotificationIds(ctx context.Context, dst sharding.Dst, notificationIds []string) ([]CallbackLog, error)
+	FindFailures(ctx context.Context, dst sharding.Dst, bizId uint64, startTime, endTime int64, cursor uint64, limit int) ([]CallbackLog, error)
 	BatchFindByTime(ctx context.Context, dst sharding.Dst, startTime int64, startId uint64, batchSize int) ([]CallbackLog, uint64, error)
 }
 
@@ -79,6 +80,47 @@ func (d *DefaultCallbackLogDao) Save(ctx context.Context, log CallbackLog) error
 	return db.WithContext(ctx).Table(dst.Table).Create(&log).Error
 }
 
+// BatchUpsert 批量写入回调日志，消息已经存在回调日志时更新消息发送状态。
+//
+// 依赖 notification_id 上的唯一索引保证幂等，调用方需要保证 logs 都属于 dst 分表。
+// 回调尚未结束 ( prepare / pending ) 时更新消息发送状态并重置回调状态、重试次数与下一次回调时间，
+// 使业务方收到最新的发送状态；已经结束的回调日志保持不变。
+func (d *DefaultCallbackLogDao) BatchUpsert(ctx context.Context, dst sharding.Dst, logs []CallbackLog) error {
+	if len(logs) == 0 {
+		return nil
+	}
+
+	db, ok := d.dbs.Load(dst.DB)
+	if !ok {
+		return fmt.Errorf("[kuryr] failed to load db [ %s ]", dst.DB)
+	}
+
+	now := time.Now().UnixMilli()
+	for i := range logs {
+		if logs[i].Id == 0 {
+			logs[i].Id = d.idGenerator.NextId(logs[i].BizId, logs[i].BizKey)
+		}
+		logs[i].CreatedAt = now
+		logs[i].UpdatedAt = now
+	}
+
+	return db.WithContext(ctx).Table(dst.Table).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "notification_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"notification_status", "retried_times", "next_retry_at", "callback_status", "updated_at"}),
+			Where: clause.Where{Exprs: []clause.Expression{
+				clause.Expr{
+					SQL: "? IN ?",
+					Vars: []any{
+						clause.Column{Table: dst.Table, Name: "callback_status"},
+						[]string{string(domain.CallbackLogStatusPrepare), string(domain.CallbackLogStatusPending)},
+					},
+				},
+			}},
+		}).
+		CreateInBatches(&logs, 1000).Error
+}
+
 func (d *DefaultCallbackLogDao) BatchUpdate(ctx context.Context, dst sharding.Dst, logs []CallbackLog) error {
 	if len(logs) == 0 {
 		return nil
@@ -126,62 +168,90 @@ func (d *DefaultCallbackLogDao) BatchUpdate(ctx context.Context, dst sharding.Ds
 		return nil
 	})
 }
-func (d *DefaultCallbackLogDao) FindByNotificationIds(ctx context.Context, notificationIds []uint64) ([]CallbackLog, error) {
-	tbs := make(map[string][]uint64)
-	for _, id := range notificationIds {
-		dst := d.shardingStrategy.DstFromId(id)
-		tbs[dst.FullTable()] = append(tbs[dst.FullTable()], id)
-	}
 
-	var mu sync.Mutex
-	res := make([]CallbackLog, 0, len(notificationIds))
+// ResetFailures 重置指定的失败回调日志，重新进入回调流程。
+func (d *DefaultCallbackLogDao) ResetFailures(ctx context.Context, dst sharding.Dst, bizId uint64, ids []uint64) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
 
-	eg, ctx := errgroup.WithContext(ctx)
-	sem := make(chan struct{}, 4)
+	db, ok := d.dbs.Load(dst.DB)
+	if !ok {
+		return 0, fmt.Errorf("[kuryr] failed to load db [ %s ]", dst.DB)
+	}
 
-	for fullTable, ids := range tbs {
-		seg := strings.Split(fullTable, ".")
+	res := db.WithContext(ctx).Table(dst.Table).
+		Where("biz_id = ?", bizId).
+		Where("callback_status = ?", string(domain.CallbackLogStatusFailure)).
+		Where("id IN ?", ids).
+		Updates(d.resetValues())
+	return res.RowsAffected, res.Error
+}
 
-		eg.Go(func() error {
-			select {
-			case sem <- struct{}{}:
-				defer func() { <-sem }()
-			case <-ctx.Done():
-				return ctx.Err()
-			}
+// ResetFailuresByTime 重置创建时间在 [startTime, endTime) 范围内的失败回调日志，重新进入回调流程。
+func (d *DefaultCallbackLogDao) ResetFailuresByTime(ctx context.Context, dst sharding.Dst, bizId uint64, startTime, endTime int64) (int64, error) {
+	db, ok := d.dbs.Load(dst.DB)
+	if !ok {
+		return 0, fmt.Errorf("[kuryr] failed to load db [ %s ]", dst.DB)
+	}
 
-			if len(seg) != 2 || seg[1] == "" {
-				return fmt.Errorf("[kuryr] invalid full table [ %s ]", fullTable)
-			}
+	res := db.WithContext(ctx).Table(dst.Table).
+		Where("biz_id = ?", bizId).
+		Where("callback_status = ?", string(domain.CallbackLogStatusFailure)).
+		Where("created_at >= ? AND created_at < ?", startTime, endTime).
+		Updates(d.resetValues())
+	return res.RowsAffected, res.Error
+}
 
-			db, ok := d.dbs.Load(seg[0])
-			if !ok {
-				return fmt.Errorf("[kuryr] failed to load db [ %s ]", seg[0])
-			}
+func (d *DefaultCallbackLogDao) resetValues() map[string]any {
+	now := time.Now().UnixMilli()
+	return map[string]any{
+		"retried_times":   0,
+		"next_retry_at":   now,
+		"callback_status": string(domain.CallbackLogStatusPrepare),
+		"updated_at":      now,
+	}
+}
 
-			var logs []CallbackLog
-			err := db.WithContext(ctx).Table(seg[1]).
-				Where("notification_id IN ?", ids).
-				Find(&logs).Error
-			if err != nil {
-				return err
-			}
+// FindByNotificationIds 在指定分表中查询消息对应的回调日志。
+func (d *DefaultCallbackLogDao) FindByNotificationIds(ctx context.Context, dst sharding.Dst, notificationIds []string) ([]CallbackLog, error) {
+	if len(notificationIds) == 0 {
+		return []CallbackLog{}, nil
+	}
 
-			if len(logs) != 0 {
-				mu.Lock()
-				defer mu.Unlock()
+	db, ok := d.dbs.Load(dst.DB)
+	if !ok {
+		return nil, fmt.Errorf("[kuryr] failed to load db [ %s ]", dst.DB)
+	}
 
-				res = append(res, logs...)
-			}
-			return nil
-		})
+	var logs []CallbackLog
+	err := db.WithContext(ctx).Table(dst.Table).
+		Where("notification_id IN ?", notificationIds).
+		Find(&logs).Error
+	return logs, err
+}
 
+// FindFailures 在指定分表中按 id 倒序查询创建时间在 [startTime, endTime) 范围内的失败回调日志。
+// cursor 为上一页最后一条记录的 id，为 0 表示从头开始查询。
+func (d *DefaultCallbackLogDao) FindFailures(
+	ctx context.Context, dst sharding.Dst, bizId uint64, startTime, endTime int64, cursor uint64, limit int,
+) ([]CallbackLog, error) {
+	db, ok := d.dbs.Load(dst.DB)
+	if !ok {
+		return nil, fmt.Errorf("[kuryr] failed to load db [ %s ]", dst.DB)
 	}
 
-	if err := eg.Wait(); err != nil {
-		return nil, err
+	query := db.WithContext(ctx).Table(dst.Table).
+		Where("biz_id = ?", bizId).
+		Where("callback_status = ?", string(domain.CallbackLogStatusFailure)).
+		Where("created_at >= ? AND created_at < ?", startTime, endTime)
+	if cursor > 0 {
+		query = query.Where("id < ?", cursor)
 	}
-	return res, nil
+
+	var logs []CallbackLog
+	err := query.Order("id DESC").Limit(limit).Find(&logs).Error
+	return logs, err
 }
 
 func (d *DefaultCallbackLogDao) BatchFindByTime(ctx context.Context, dst sharding.Dst, startTime int64, startId uint64, batchSize int) ([]CallbackLog, uint64, error) {
@@ -0,0 +1,101 @@
+package dao
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const callbackOutboxCollection = "callback_outbox"
+
+// CallbackOutbox 回调 outbox 数据对象。
+//
+// 与消息发送状态在同一个 mongo 事务中写入，由 relay 异步写入回调日志分表后删除。
+type CallbackOutbox struct {
+	Id           bson.ObjectID `bson:"_id"` // 消息 id
+	BizId        uint64        `bson:"biz_id"`
+	BizKey       string        `bson:"biz_key"`
+	SendStatus   string        `bson:"send_status"`
+	RelayedTimes int32         `bson:"relayed_times"` // 写入回调日志失败的次数
+	NextRelayAt  int64         `bson:"next_relay_at"`
+	CreatedAt    int64         `bson:"created_at"`
+	UpdatedAt    int64         `bson:"updated_at"`
+}
+
+// CallbackOutboxDao 回调 outbox 数据访问对象。
+type CallbackOutboxDao interface {
+	FindPending(ctx context.Context, now int64, limit int) ([]CallbackOutbox, error)
+	Delete(ctx context.Context, outboxes []CallbackOutbox) error
+	Delay(ctx context.Context, ids []bson.ObjectID, nextRelayAt int64) error
+}
+
+var _ CallbackOutboxDao = (*DefaultCallbackOutboxDao)(nil)
+
+type DefaultCallbackOutboxDao struct {
+	db *mongo.Database
+}
+
+// FindPending 按写入顺序查询到达 relay 时间的 outbox 记录。
+func (d *DefaultCallbackOutboxDao) FindPending(ctx context.Context, now int64, limit int) ([]CallbackOutbox, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "next_relay_at", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := d.db.Collection(callbackOutboxCollection).Find(
+		ctx, bson.D{{Key: "next_relay_at", Value: bson.D{{Key: "$lte", Value: now}}}}, opts,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var outboxes []CallbackOutbox
+	if err = cursor.All(ctx, &outboxes); err != nil {
+		return nil, err
+	}
+	return outboxes, nil
+}
+
+// Delete 删除已经写入回调日志的 outbox 记录。
+//
+// 只删除读取之后没有被更新过的记录，避免丢失 relay 期间写入的新发送状态。
+func (d *DefaultCallbackOutboxDao) Delete(ctx context.Context, outboxes []CallbackOutbox) error {
+	if len(outboxes) == 0 {
+		return nil
+	}
+
+	models := make([]mongo.WriteModel, 0, len(outboxes))
+	for _, outbox := range outboxes {
+		models = append(models, mongo.NewDeleteOneModel().SetFilter(bson.D{
+			{Key: "_id", Value: outbox.Id},
+			{Key: "updated_at", Value: outbox.UpdatedAt},
+		}))
+	}
+
+	_, err := d.db.Collection(callbackOutboxCollection).BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+// Delay 推迟 outbox 记录的下次 relay 时间。
+func (d *DefaultCallbackOutboxDao) Delay(ctx context.Context, ids []bson.ObjectID, nextRelayAt int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	_, err := d.db.Collection(callbackOutboxCollection).UpdateMany(
+		ctx,
+		bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: ids}}}},
+		bson.D{
+			{Key: "$set", Value: bson.D{{Key: "next_relay_at", Value: nextRelayAt}}},
+			{Key: "$inc", Value: bson.D{{Key: "relayed_times", Value: 1}}},
+		},
+	)
+	return err
+}
+
+func NewDefaultCallbackOutboxDao(db *mongo.Database) *DefaultCallbackOutboxDao {
+	return &DefaultCallbackOutboxDao{
+		db: db,
+	}
+}
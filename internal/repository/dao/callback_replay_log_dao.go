@@ -0,0 +1,52 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	pkgsql "github.com/JrMarcco/kuryr/internal/pkg/sql"
+	"gorm.io/gorm"
+)
+
+// CallbackReplayLog 回调重放记录数据对象。
+type CallbackReplayLog struct {
+	Id             uint64                      `gorm:"column:id"`
+	BizId          uint64                      `gorm:"column:biz_id"`
+	CallbackLogIds pkgsql.JsonColumn[[]uint64] `gorm:"column:callback_log_ids;type:JSON"`
+	StartTime      int64                       `gorm:"column:start_time"`
+	EndTime        int64                       `gorm:"column:end_time"`
+	ReplayedCount  int64                       `gorm:"column:replayed_count"`
+	OperatorId     uint64                      `gorm:"column:operator_id"`
+
+	CreatedAt int64 `gorm:"column:created_at"`
+}
+
+func (CallbackReplayLog) TableName() string {
+	return "callback_replay_log"
+}
+
+// CallbackReplayLogDao 回调重放记录数据访问对象。
+//
+// 注意：
+//
+//	重放记录数据量小，存放在基础库中，不做分库分表。
+type CallbackReplayLogDao interface {
+	Save(ctx context.Context, log CallbackReplayLog) error
+}
+
+var _ CallbackReplayLogDao = (*DefaultCallbackReplayLogDao)(nil)
+
+type DefaultCallbackReplayLogDao struct {
+	db *gorm.DB
+}
+
+func (d *DefaultCallbackReplayLogDao) Save(ctx context.Context, log CallbackReplayLog) error {
+	log.CreatedAt = time.Now().UnixMilli()
+	return d.db.WithContext(ctx).Model(&CallbackReplayLog{}).Create(&log).Error
+}
+
+func NewCallbackReplayLogDao(db *gorm.DB) CallbackReplayLogDao {
+	return &DefaultCallbackReplayLogDao{
+		db: db,
+	}
+}
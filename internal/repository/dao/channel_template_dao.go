@@ -2,9 +2,13 @@ package dao
 
 import (
 	"context"
+	"fmt"
 	"time"
 
+	"github.com/JrMarcco/kuryr/internal/domain"
+	"github.com/JrMarcco/kuryr/internal/errs"
 	pkggorm "github.com/JrMarcco/kuryr/internal/pkg/gorm"
+	pkgsql "github.com/JrMarcco/kuryr/internal/pkg/sql"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
@@ -39,6 +43,9 @@ type ChannelTemplateVersion struct {
 	Signature   string `gorm:"column:signature"`
 	Content     string `gorm:"column:content"`
 
+	ParamSchema pkgsql.JsonColumn[domain.TplParamSchema] `gorm:"column:param_schema;type:JSON"`
+	Variants    pkgsql.JsonColumn[[]domain.TplVariant]   `gorm:"column:variants;type:JSON"`
+
 	ApplyRemark string `gorm:"column:apply_remark"`
 
 	AuditorId       uint64 `gorm:"column:auditor_id"`
@@ -66,6 +73,7 @@ type ChannelTemplateProvider struct {
 	ProviderName    string `gorm:"column:provider_name"`
 	ProviderTplId   string `gorm:"column:provider_tpl_id"`
 	ProviderChannel int32  `gorm:"column:provider_channel"`
+	Locale          string `gorm:"column:locale"`
 
 	AuditRequestId  string `gorm:"column:audit_request_id"`
 	AuditStatus     string `gorm:"column:audit_status"`
@@ -80,6 +88,41 @@ func (ChannelTemplateProvider) TableName() string {
 	return "channel_template_provider"
 }
 
+// ChannelTemplateAuditLog 渠道模板版本审批记录表
+type ChannelTemplateAuditLog struct {
+	Id           uint64 `gorm:"column:id"`
+	TplId        uint64 `gorm:"column:tpl_id"`
+	TplVersionId uint64 `gorm:"column:tpl_version_id"`
+
+	Action     string `gorm:"column:action"`
+	FromStatus string `gorm:"column:from_status"`
+	ToStatus   string `gorm:"column:to_status"`
+	OperatorId uint64 `gorm:"column:operator_id"`
+	Remark     string `gorm:"column:remark"`
+
+	CreatedAt int64 `gorm:"column:created_at"`
+}
+
+func (ChannelTemplateAuditLog) TableName() string {
+	return "channel_template_audit_log"
+}
+
+// ChannelTemplateActivationLog 渠道模板版本激活记录表
+type ChannelTemplateActivationLog struct {
+	Id            uint64 `gorm:"column:id"`
+	TplId         uint64 `gorm:"column:tpl_id"`
+	FromVersionId uint64 `gorm:"column:from_version_id"`
+	ToVersionId   uint64 `gorm:"column:to_version_id"`
+	Action        string `gorm:"column:action"`
+	OperatorId    uint64 `gorm:"column:operator_id"`
+
+	CreatedAt int64 `gorm:"column:created_at"`
+}
+
+func (ChannelTemplateActivationLog) TableName() string {
+	return "channel_template_activation_log"
+}
+
 type ChannelTplDao interface {
 	SaveTemplate(ctx context.Context, template ChannelTemplate) (ChannelTemplate, error)
 	DeleteTemplate(ctx context.Context, id uint64) error
@@ -88,16 +131,32 @@ type ChannelTplDao interface {
 
 	SaveVersion(ctx context.Context, version ChannelTemplateVersion) (ChannelTemplateVersion, error)
 	DeleteVersion(ctx context.Context, id uint64) error
-	// ActivateVersion 激活版本
-	ActivateVersion(ctx context.Context, templateId uint64, versionId uint64) error
+	// ActivateVersion 激活版本并记录激活历史。
+	// 只有当前激活版本与 log.FromVersionId 一致时才会更新，防止并发激活。
+	ActivateVersion(ctx context.Context, log ChannelTemplateActivationLog) error
+	FindActivationLogByTplId(ctx context.Context, tplId uint64) ([]ChannelTemplateActivationLog, error)
 	FindVersionById(ctx context.Context, id uint64) (ChannelTemplateVersion, error)
-	FindVersionByIds(ctx context.Context, ids []uint64) ([]ChannelTemplateVersion, error)
+	FindVersionByTplIds(ctx context.Context, tplIds []uint64) ([]ChannelTemplateVersion, error)
 	FindVersionByTplId(ctx context.Context, tplId uint64) ([]ChannelTemplateVersion, error)
+	// AuditVersion 变更版本审批状态并记录审批历史。
+	// 只有当前审批状态与 fromStatus 一致时才会更新，防止并发审批。
+	AuditVersion(ctx context.Context, version ChannelTemplateVersion, fromStatus string, log ChannelTemplateAuditLog) error
+	FindAuditLogByVersionId(ctx context.Context, versionId uint64) ([]ChannelTemplateAuditLog, error)
 
 	SaveProviders(ctx context.Context, providers []ChannelTemplateProvider) error
 	DeleteProvider(ctx context.Context, id uint64) error
 	FindProviderByVersionId(ctx context.Context, versionId uint64) ([]ChannelTemplateProvider, error)
 	FindProviderByVersionIds(ctx context.Context, versionIds []uint64) ([]ChannelTemplateProvider, error)
+	FindProviderByAuditStatus(ctx context.Context, auditStatus string) ([]ChannelTemplateProvider, error)
+	// UpdateProviderAudit 根据 id 更新供应商侧审批信息。
+	UpdateProviderAudit(ctx context.Context, provider ChannelTemplateProvider) error
+	// UpdateProviderAuditStatus 根据供应商 id 与供应商侧模板 id 更新供应商侧审批状态，返回受影响的模板 id。
+	UpdateProviderAuditStatus(ctx context.Context, providerId uint64, providerTplId string, auditStatus string, rejectionReason string) ([]uint64, error)
+	// AcquireProviderSubmit 以 CAS 的方式占用供应商的提交权，占用期间 last_review_at 记录占用时间。
+	// 只有审批状态为 auditStatus 且没有被占用 ( 或者占用已超过 leaseMillis ) 时才能占用成功。
+	AcquireProviderSubmit(ctx context.Context, id uint64, auditStatus string, leaseMillis int64) (bool, error)
+	// ReleaseProviderSubmit 提交失败时释放提交权，审批状态已经不是 auditStatus 时不做处理。
+	ReleaseProviderSubmit(ctx context.Context, id uint64, auditStatus string) error
 }
 
 var _ ChannelTplDao = (*DefaultChannelTplDao)(nil)
@@ -155,13 +214,35 @@ func (d *DefaultChannelTplDao) DeleteVersion(ctx context.Context, id uint64) err
 		Delete(&ChannelTemplateVersion{}).Error
 }
 
-func (d *DefaultChannelTplDao) ActivateVersion(ctx context.Context, templateId uint64, versionId uint64) error {
-	return d.db.WithContext(ctx).Model(&ChannelTemplate{}).
-		Where("id = ?", templateId).
-		Updates(map[string]any{
-			"activated_version_id": versionId,
-			"updated_at":           time.Now().UnixMilli(),
-		}).Error
+func (d *DefaultChannelTplDao) ActivateVersion(ctx context.Context, log ChannelTemplateActivationLog) error {
+	now := time.Now().UnixMilli()
+	log.CreatedAt = now
+
+	return d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&ChannelTemplate{}).
+			Where("id = ? AND activated_version_id = ?", log.TplId, log.FromVersionId).
+			Updates(map[string]any{
+				"activated_version_id": log.ToVersionId,
+				"updated_at":           now,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return fmt.Errorf("%w: template activated version has been changed, id = %d", errs.ErrInvalidStatus, log.TplId)
+		}
+
+		return tx.Model(&ChannelTemplateActivationLog{}).Create(&log).Error
+	})
+}
+
+func (d *DefaultChannelTplDao) FindActivationLogByTplId(ctx context.Context, tplId uint64) ([]ChannelTemplateActivationLog, error) {
+	var logs []ChannelTemplateActivationLog
+	err := d.db.WithContext(ctx).Model(&ChannelTemplateActivationLog{}).
+		Where("tpl_id = ?", tplId).
+		Order("id DESC").
+		Find(&logs).Error
+	return logs, err
 }
 
 func (d *DefaultChannelTplDao) FindVersionById(ctx context.Context, id uint64) (ChannelTemplateVersion, error) {
@@ -172,14 +253,14 @@ func (d *DefaultChannelTplDao) FindVersionById(ctx context.Context, id uint64) (
 	return version, err
 }
 
-func (d *DefaultChannelTplDao) FindVersionByIds(ctx context.Context, ids []uint64) ([]ChannelTemplateVersion, error) {
-	if len(ids) == 0 {
+func (d *DefaultChannelTplDao) FindVersionByTplIds(ctx context.Context, tplIds []uint64) ([]ChannelTemplateVersion, error) {
+	if len(tplIds) == 0 {
 		return []ChannelTemplateVersion{}, nil
 	}
 
 	var versions []ChannelTemplateVersion
 	err := d.db.WithContext(ctx).Model(&ChannelTemplateVersion{}).
-		Where("tpl_id in (?)", ids).
+		Where("tpl_id in (?)", tplIds).
 		Find(&versions).Error
 	return versions, err
 }
@@ -192,6 +273,49 @@ func (d *DefaultChannelTplDao) FindVersionByTplId(ctx context.Context, tplId uin
 	return versions, err
 }
 
+func (d *DefaultChannelTplDao) AuditVersion(
+	ctx context.Context, version ChannelTemplateVersion, fromStatus string, log ChannelTemplateAuditLog,
+) error {
+	now := time.Now().UnixMilli()
+	log.CreatedAt = now
+
+	return d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&ChannelTemplateAuditLog{}).
+			Clauses(clause.Returning{}).
+			Create(&log).Error; err != nil {
+			return err
+		}
+
+		res := tx.Model(&ChannelTemplateVersion{}).
+			Where("id = ? AND audit_status = ?", version.Id, fromStatus).
+			Updates(map[string]any{
+				"auditor_id":       version.AuditorId,
+				"audit_id":         log.Id,
+				"audit_time":       version.AuditTime,
+				"audit_status":     version.AuditStatus,
+				"rejection_reason": version.RejectionReason,
+				"last_review_at":   version.LastReviewAt,
+				"updated_at":       now,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return fmt.Errorf("%w: version audit status has been changed, id = %d", errs.ErrInvalidStatus, version.Id)
+		}
+		return nil
+	})
+}
+
+func (d *DefaultChannelTplDao) FindAuditLogByVersionId(ctx context.Context, versionId uint64) ([]ChannelTemplateAuditLog, error) {
+	var logs []ChannelTemplateAuditLog
+	err := d.db.WithContext(ctx).Model(&ChannelTemplateAuditLog{}).
+		Where("tpl_version_id = ?", versionId).
+		Order("id DESC").
+		Find(&logs).Error
+	return logs, err
+}
+
 func (d *DefaultChannelTplDao) SaveProviders(ctx context.Context, providers []ChannelTemplateProvider) error {
 	now := time.Now().UnixMilli()
 	for i := range providers {
@@ -228,6 +352,73 @@ func (d *DefaultChannelTplDao) FindProviderByVersionIds(ctx context.Context, ver
 	return providers, err
 }
 
+func (d *DefaultChannelTplDao) FindProviderByAuditStatus(ctx context.Context, auditStatus string) ([]ChannelTemplateProvider, error) {
+	var providers []ChannelTemplateProvider
+	err := d.db.WithContext(ctx).Model(&ChannelTemplateProvider{}).
+		Where("audit_status = ?", auditStatus).
+		Find(&providers).Error
+	return providers, err
+}
+
+func (d *DefaultChannelTplDao) UpdateProviderAudit(ctx context.Context, provider ChannelTemplateProvider) error {
+	return d.db.WithContext(ctx).Model(&ChannelTemplateProvider{}).
+		Where("id = ?", provider.Id).
+		Updates(map[string]any{
+			"provider_tpl_id":  provider.ProviderTplId,
+			"audit_request_id": provider.AuditRequestId,
+			"audit_status":     provider.AuditStatus,
+			"rejection_reason": provider.RejectionReason,
+			"last_review_at":   provider.LastReviewAt,
+			"updated_at":       time.Now().UnixMilli(),
+		}).Error
+}
+
+func (d *DefaultChannelTplDao) UpdateProviderAuditStatus(
+	ctx context.Context, providerId uint64, providerTplId string, auditStatus string, rejectionReason string,
+) ([]uint64, error) {
+	var providers []ChannelTemplateProvider
+	err := d.db.WithContext(ctx).Model(&providers).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "tpl_id"}}}).
+		Where("provider_id = ? AND provider_tpl_id = ?", providerId, providerTplId).
+		Updates(map[string]any{
+			"audit_status":     auditStatus,
+			"rejection_reason": rejectionReason,
+			"updated_at":       time.Now().UnixMilli(),
+		}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	tplIds := make([]uint64, 0, len(providers))
+	for _, provider := range providers {
+		tplIds = append(tplIds, provider.TplId)
+	}
+	return tplIds, nil
+}
+
+func (d *DefaultChannelTplDao) AcquireProviderSubmit(ctx context.Context, id uint64, auditStatus string, leaseMillis int64) (bool, error) {
+	now := time.Now().UnixMilli()
+	res := d.db.WithContext(ctx).Model(&ChannelTemplateProvider{}).
+		Where("id = ? AND audit_status = ? AND last_review_at < ?", id, auditStatus, now-leaseMillis).
+		Updates(map[string]any{
+			"last_review_at": now,
+			"updated_at":     now,
+		})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+func (d *DefaultChannelTplDao) ReleaseProviderSubmit(ctx context.Context, id uint64, auditStatus string) error {
+	return d.db.WithContext(ctx).Model(&ChannelTemplateProvider{}).
+		Where("id = ? AND audit_status = ?", id, auditStatus).
+		Updates(map[string]any{
+			"last_review_at": 0,
+			"updated_at":     time.Now().UnixMilli(),
+		}).Error
+}
+
 func NewDefaultChannelTplDao(db *gorm.DB) *DefaultChannelTplDao {
 	return &DefaultChannelTplDao{
 		db: db,
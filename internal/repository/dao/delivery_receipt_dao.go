@@ -0,0 +1,79 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const deliveryReceiptCollection = "delivery_receipt"
+
+// DeliveryReceipt 供应商送达回执数据对象。
+//
+// 以 provider_id + serial_no + receiver 唯一确定一条回执，供应商重复推送时覆盖为最新的记录。
+type DeliveryReceipt struct {
+	ProviderId uint64 `bson:"provider_id"`
+	SerialNo   string `bson:"serial_no"`
+	Receiver   string `bson:"receiver"`
+	Success    bool   `bson:"success"`
+	ErrCode    string `bson:"err_code"`
+	ErrMsg     string `bson:"err_msg"`
+	ReceivedAt int64  `bson:"received_at"`
+	CreatedAt  int64  `bson:"created_at"`
+	UpdatedAt  int64  `bson:"updated_at"`
+}
+
+type DeliveryReceiptDao interface {
+	BatchUpsert(ctx context.Context, receipts []DeliveryReceipt) error
+}
+
+var _ DeliveryReceiptDao = (*DefaultDeliveryReceiptDao)(nil)
+
+type DefaultDeliveryReceiptDao struct {
+	db *mongo.Database
+}
+
+func (d *DefaultDeliveryReceiptDao) BatchUpsert(ctx context.Context, receipts []DeliveryReceipt) error {
+	if len(receipts) == 0 {
+		return nil
+	}
+
+	now := time.Now().UnixMilli()
+	models := make([]mongo.WriteModel, 0, len(receipts))
+	for _, receipt := range receipts {
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.D{
+				{Key: "provider_id", Value: receipt.ProviderId},
+				{Key: "serial_no", Value: receipt.SerialNo},
+				{Key: "receiver", Value: receipt.Receiver},
+			}).
+			SetUpdate(bson.D{
+				{Key: "$set", Value: bson.D{
+					{Key: "success", Value: receipt.Success},
+					{Key: "err_code", Value: receipt.ErrCode},
+					{Key: "err_msg", Value: receipt.ErrMsg},
+					{Key: "received_at", Value: receipt.ReceivedAt},
+					{Key: "updated_at", Value: now},
+				}},
+				{Key: "$setOnInsert", Value: bson.D{{Key: "created_at", Value: now}}},
+			}).
+			SetUpsert(true),
+		)
+	}
+
+	_, err := d.db.Collection(deliveryReceiptCollection).BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return fmt.Errorf("[kuryr] failed to save delivery receipts: %w", err)
+	}
+	return nil
+}
+
+func NewDefaultDeliveryReceiptDao(db *mongo.Database) *DefaultDeliveryReceiptDao {
+	return &DefaultDeliveryReceiptDao{
+		db: db,
+	}
+}
@@ -1,38 +1,149 @@
 package dao
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const (
+	notificationCollection = "notification"
+
+	duplicateKeyErrCode = 11000
 )
 
 type Notification struct {
 	Id             bson.ObjectID `json:"id" bson:"_id"`
-	BizId          uint64        `json:"biz_id" bson: "biz_id"`
-	BizKey         string        `json:"biz_key" bson: "biz_key"	`
-	Receivers      string        `json:"receivers" bson: "receivers"`
-	Channel        string        `json:"channel" bson: "channel"`
-	TemplateId     uint64        `json:"template_id" bson: "template_id"`
-	TemplateParams string        `json:"template_params" bson: "template_params"`
-	SendStatus     string        `json:"send_status" bson: "send_status"`
-	ScheduledStart int64         `json:"scheduled_start" bson: "scheduled_start"`
-	ScheduledEnd   int64         `json:"scheduled_end" bson: "scheduled_end"`
-	Version        int32         `json:"version" bson: "version"`
-	CreatedAt      int64         `json:"created_at" bson: "created_at"`
-	UpdatedAt      int64         `json:"updated_at" bson: "updated_at"`
+	BizId          uint64        `json:"biz_id" bson:"biz_id"`
+	BizKey         string        `json:"biz_key" bson:"biz_key"`
+	Receivers      string        `json:"receivers" bson:"receivers"`
+	Channel        int32         `json:"channel" bson:"channel"`
+	TemplateId     uint64        `json:"template_id" bson:"template_id"`
+	TemplateVer    uint64        `json:"template_version" bson:"template_version"`
+	TemplateParams string        `json:"template_params" bson:"template_params"`
+	Locale         string        `json:"locale" bson:"locale"`
+	SendStatus     string        `json:"send_status" bson:"send_status"`
+	ScheduledStart int64         `json:"scheduled_start" bson:"scheduled_start"`
+	ScheduledEnd   int64         `json:"scheduled_end" bson:"scheduled_end"`
+	Version        int32         `json:"version" bson:"version"`
+	CreatedAt      int64         `json:"created_at" bson:"created_at"`
+	UpdatedAt      int64         `json:"updated_at" bson:"updated_at"`
 }
 
 func (n Notification) HexId() string {
 	return n.Id.Hex()
 }
 
-type NotificationDao interface{}
+type NotificationDao interface {
+	// BatchInsertIfAbsent 批量写入消息，消息 id 已存在时跳过，保证重复写入幂等。
+	BatchInsertIfAbsent(ctx context.Context, ns []Notification) error
+	// UpdateSendStatusWithOutbox 更新消息发送状态，并在同一事务内写入回调 outbox 记录。
+	UpdateSendStatusWithOutbox(ctx context.Context, ns []Notification) error
+}
+
+var _ NotificationDao = (*DefaultNotificationDao)(nil)
 
 type DefaultNotificationDao struct {
-	client *mongo.Client
+	db *mongo.Database
+}
+
+func (d *DefaultNotificationDao) BatchInsertIfAbsent(ctx context.Context, ns []Notification) error {
+	if len(ns) == 0 {
+		return nil
+	}
+
+	now := time.Now().UnixMilli()
+	docs := make([]any, 0, len(ns))
+	for _, n := range ns {
+		n.CreatedAt = now
+		n.UpdatedAt = now
+		docs = append(docs, n)
+	}
+
+	_, err := d.db.Collection(notificationCollection).InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+	if err == nil || onlyDuplicateKeyErrors(err) {
+		return nil
+	}
+	return fmt.Errorf("[kuryr] failed to insert notifications: %w", err)
+}
+
+// onlyDuplicateKeyErrors 判断批量写入失败是否全部由主键冲突导致。
+func onlyDuplicateKeyErrors(err error) bool {
+	var bwe mongo.BulkWriteException
+	if !errors.As(err, &bwe) || bwe.WriteConcernError != nil || len(bwe.WriteErrors) == 0 {
+		return false
+	}
+	for _, we := range bwe.WriteErrors {
+		if !we.HasErrorCode(duplicateKeyErrCode) {
+			return false
+		}
+	}
+	return true
+}
+
+func (d *DefaultNotificationDao) UpdateSendStatusWithOutbox(ctx context.Context, ns []Notification) error {
+	if len(ns) == 0 {
+		return nil
+	}
+
+	now := time.Now().UnixMilli()
+
+	statusModels := make([]mongo.WriteModel, 0, len(ns))
+	outboxModels := make([]mongo.WriteModel, 0, len(ns))
+	for _, n := range ns {
+		statusModels = append(statusModels, mongo.NewUpdateOneModel().
+			SetFilter(bson.D{{Key: "_id", Value: n.Id}}).
+			SetUpdate(bson.D{
+				{Key: "$set", Value: bson.D{
+					{Key: "send_status", Value: n.SendStatus},
+					{Key: "updated_at", Value: now},
+				}},
+				{Key: "$inc", Value: bson.D{{Key: "version", Value: 1}}},
+			}),
+		)
+
+		// outbox 以消息 id 作为主键，重复更新发送状态时覆盖为最新的记录。
+		outboxModels = append(outboxModels, mongo.NewReplaceOneModel().
+			SetFilter(bson.D{{Key: "_id", Value: n.Id}}).
+			SetReplacement(CallbackOutbox{
+				Id:          n.Id,
+				BizId:       n.BizId,
+				BizKey:      n.BizKey,
+				SendStatus:  n.SendStatus,
+				NextRelayAt: now,
+				CreatedAt:   now,
+				UpdatedAt:   now,
+			}).
+			SetUpsert(true),
+		)
+	}
+
+	session, err := d.db.Client().StartSession()
+	if err != nil {
+		return fmt.Errorf("[kuryr] failed to start mongo session: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(ctx context.Context) (any, error) {
+		opts := options.BulkWrite().SetOrdered(false)
+		if _, err := d.db.Collection(notificationCollection).BulkWrite(ctx, statusModels, opts); err != nil {
+			return nil, fmt.Errorf("[kuryr] failed to update notification send status: %w", err)
+		}
+		if _, err := d.db.Collection(callbackOutboxCollection).BulkWrite(ctx, outboxModels, opts); err != nil {
+			return nil, fmt.Errorf("[kuryr] failed to save callback outbox: %w", err)
+		}
+		return nil, nil
+	})
+	return err
 }
 
-func NewDefaultNotificationDao(client *mongo.Client) *DefaultNotificationDao {
+func NewDefaultNotificationDao(db *mongo.Database) *DefaultNotificationDao {
 	return &DefaultNotificationDao{
-		client: client,
+		db: db,
 	}
 }
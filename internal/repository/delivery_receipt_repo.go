@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/JrMarcco/easy-kit/slice"
+	"github.com/JrMarcco/kuryr/internal/domain"
+	"github.com/JrMarcco/kuryr/internal/repository/dao"
+)
+
+type DeliveryReceiptRepo interface {
+	// BatchSave 批量保存送达回执，重复推送的回执覆盖保存。
+	BatchSave(ctx context.Context, events []domain.DeliveryReceiptEvent) error
+}
+
+var _ DeliveryReceiptRepo = (*DefaultDeliveryReceiptRepo)(nil)
+
+type DefaultDeliveryReceiptRepo struct {
+	dao dao.DeliveryReceiptDao
+}
+
+func (r *DefaultDeliveryReceiptRepo) BatchSave(ctx context.Context, events []domain.DeliveryReceiptEvent) error {
+	return r.dao.BatchUpsert(ctx, slice.Map(events, func(_ int, event domain.DeliveryReceiptEvent) dao.DeliveryReceipt {
+		return dao.DeliveryReceipt{
+			ProviderId: event.ProviderId,
+			SerialNo:   event.SerialNo,
+			Receiver:   event.Receiver,
+			Success:    event.Success,
+			ErrCode:    event.ErrCode,
+			ErrMsg:     event.ErrMsg,
+			ReceivedAt: event.ReceivedAt,
+		}
+	}))
+}
+
+func NewDefaultDeliveryReceiptRepo(dao dao.DeliveryReceiptDao) *DefaultDeliveryReceiptRepo {
+	return &DefaultDeliveryReceiptRepo{
+		dao: dao,
+	}
+}
@@ -2,9 +2,13 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 
 	"github.com/JrMarcco/kuryr/internal/domain"
+	"github.com/JrMarcco/kuryr/internal/errs"
 	"github.com/JrMarcco/kuryr/internal/repository/dao"
+	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
 type NotificationRepo interface {
@@ -14,8 +18,11 @@ type NotificationRepo interface {
 	BatchSave(ctx context.Context, ns []domain.Notification) ([]domain.Notification, error)
 	BatchSaveWithCallback(ctx context.Context, ns []domain.Notification) ([]domain.Notification, error)
 
+	// MarkSuccess / MarkFailure / BatchMarkSendStatus 更新消息发送状态，
+	// 并在同一事务中写入回调 outbox，由 relay 异步生成回调日志。
 	MarkSuccess(ctx context.Context, n domain.Notification) error
 	MarkFailure(ctx context.Context, n domain.Notification) error
+	BatchMarkSendStatus(ctx context.Context, ns []domain.Notification) error
 }
 
 var _ NotificationRepo = (*DefaultNotificationRepo)(nil)
@@ -26,33 +33,106 @@ type DefaultNotificationRepo struct {
 }
 
 func (r *DefaultNotificationRepo) Save(ctx context.Context, n domain.Notification) (domain.Notification, error) {
-	// TODO: implement me
-	panic("implement me")
+	saved, err := r.BatchSave(ctx, []domain.Notification{n})
+	if err != nil {
+		return domain.Notification{}, err
+	}
+	return saved[0], nil
 }
 
+// SaveWithCallback 保存消息。
+// 回调日志在发送状态变更时通过 outbox 生成 ( 见 BatchMarkSendStatus )，入库时无需额外处理。
 func (r *DefaultNotificationRepo) SaveWithCallback(ctx context.Context, n domain.Notification) (domain.Notification, error) {
-	// TODO: implement me
-	panic("implement me")
+	return r.Save(ctx, n)
 }
 
+// BatchSave 批量保存消息。
+// 未分配 id 的消息在入库前生成 id，已分配 id 的消息 ( 例如异步发送时提前分配 ) 重复保存时跳过。
 func (r *DefaultNotificationRepo) BatchSave(ctx context.Context, ns []domain.Notification) ([]domain.Notification, error) {
-	// TODO: implement me
-	panic("implement me")
+	saved := make([]domain.Notification, 0, len(ns))
+	entities := make([]dao.Notification, 0, len(ns))
+	for _, n := range ns {
+		if n.Id == "" {
+			n.Id = bson.NewObjectID().Hex()
+		}
+		entity, err := r.toEntity(n)
+		if err != nil {
+			return nil, err
+		}
+
+		saved = append(saved, n)
+		entities = append(entities, entity)
+	}
+
+	if err := r.notificationDao.BatchInsertIfAbsent(ctx, entities); err != nil {
+		return nil, err
+	}
+	return saved, nil
 }
 
+// BatchSaveWithCallback 批量保存消息，同 SaveWithCallback。
 func (r *DefaultNotificationRepo) BatchSaveWithCallback(ctx context.Context, ns []domain.Notification) ([]domain.Notification, error) {
-	// TODO: implement me
-	panic("implement me")
+	return r.BatchSave(ctx, ns)
 }
 
 func (r *DefaultNotificationRepo) MarkSuccess(ctx context.Context, n domain.Notification) error {
-	// TODO: implement me
-	panic("implement me")
+	n.SendStatus = domain.SendStatusSuccess
+	return r.BatchMarkSendStatus(ctx, []domain.Notification{n})
 }
 
 func (r *DefaultNotificationRepo) MarkFailure(ctx context.Context, n domain.Notification) error {
-	// TODO: implement me
-	panic("implement me")
+	n.SendStatus = domain.SendStatusFailure
+	return r.BatchMarkSendStatus(ctx, []domain.Notification{n})
+}
+
+func (r *DefaultNotificationRepo) BatchMarkSendStatus(ctx context.Context, ns []domain.Notification) error {
+	entities := make([]dao.Notification, 0, len(ns))
+	for _, n := range ns {
+		id, err := bson.ObjectIDFromHex(n.Id)
+		if err != nil {
+			return fmt.Errorf("%w: invalid notification id [ %s ]", errs.ErrInvalidParam, n.Id)
+		}
+
+		entities = append(entities, dao.Notification{
+			Id:         id,
+			BizId:      n.BizId,
+			BizKey:     n.BizKey,
+			SendStatus: string(n.SendStatus),
+		})
+	}
+	return r.notificationDao.UpdateSendStatusWithOutbox(ctx, entities)
+}
+
+func (r *DefaultNotificationRepo) toEntity(n domain.Notification) (dao.Notification, error) {
+	id, err := bson.ObjectIDFromHex(n.Id)
+	if err != nil {
+		return dao.Notification{}, fmt.Errorf("%w: invalid notification id [ %s ]", errs.ErrInvalidParam, n.Id)
+	}
+
+	receivers, err := json.Marshal(n.Receivers)
+	if err != nil {
+		return dao.Notification{}, fmt.Errorf("[kuryr] failed to marshal notification receivers: %w", err)
+	}
+	params, err := json.Marshal(n.Template.Params)
+	if err != nil {
+		return dao.Notification{}, fmt.Errorf("[kuryr] failed to marshal notification template params: %w", err)
+	}
+
+	return dao.Notification{
+		Id:             id,
+		BizId:          n.BizId,
+		BizKey:         n.BizKey,
+		Receivers:      string(receivers),
+		Channel:        int32(n.Channel),
+		TemplateId:     n.Template.Id,
+		TemplateVer:    n.Template.Version,
+		TemplateParams: string(params),
+		Locale:         n.Locale,
+		SendStatus:     string(n.SendStatus),
+		ScheduledStart: n.ScheduledStrat.UnixMilli(),
+		ScheduledEnd:   n.ScheduledEnd.UnixMilli(),
+		Version:        n.Version,
+	}, nil
 }
 
 func NewDefaultNotificationRepo(callbackLogDao dao.CallbackLogDao, notificationDao dao.NotificationDao) NotificationRepo {
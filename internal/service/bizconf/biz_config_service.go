@@ -29,6 +29,12 @@ func (s *DefaultService) Save(ctx context.Context, bizConfig domain.BizConfig) (
 		return domain.BizConfig{}, fmt.Errorf("%w: invalidate biz id [ %d ]", errs.ErrInvalidParam, bizConfig.Id)
 	}
 
+	if bizConfig.CallbackConfig != nil {
+		if err := bizConfig.CallbackConfig.Validate(); err != nil {
+			return domain.BizConfig{}, err
+		}
+	}
+
 	bizInfo, err := s.bizInfoRepo.FindById(ctx, bizConfig.BizId)
 	if err != nil {
 		return domain.BizConfig{}, fmt.Errorf("%w: failed to find biz info by id [ %d ]", errs.ErrInvalidParam, bizConfig.Id)
@@ -43,6 +49,12 @@ func (s *DefaultService) Update(ctx context.Context, bizConfig domain.BizConfig)
 		return domain.BizConfig{}, fmt.Errorf("%w: invalidate biz config id [ %d ]", errs.ErrInvalidParam, bizConfig.Id)
 	}
 
+	if bizConfig.CallbackConfig != nil {
+		if err := bizConfig.CallbackConfig.Validate(); err != nil {
+			return domain.BizConfig{}, err
+		}
+	}
+
 	return s.bizConfigRepo.Update(ctx, bizConfig)
 }
 
@@ -2,18 +2,20 @@ package callback
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"strconv"
 	"sync"
 	"time"
 
 	"github.com/JrMarcco/easy-grpc/client"
-	"github.com/JrMarcco/easy-kit/xsync"
 	clientv1 "github.com/JrMarcco/kuryr-api/api/go/client/v1"
 	commonv1 "github.com/JrMarcco/kuryr-api/api/go/common/v1"
 	notificationv1 "github.com/JrMarcco/kuryr-api/api/go/notification/v1"
 	"github.com/JrMarcco/kuryr/internal/domain"
 	"github.com/JrMarcco/kuryr/internal/errs"
+	"github.com/JrMarcco/kuryr/internal/pkg/mq"
 	"github.com/JrMarcco/kuryr/internal/pkg/retry"
 	"github.com/JrMarcco/kuryr/internal/pkg/sharding"
 	"github.com/JrMarcco/kuryr/internal/repository"
@@ -24,9 +26,12 @@ import (
 var _ Service = (*DefaultService)(nil)
 
 type DefaultService struct {
-	callbackConfigMap xsync.Map[uint64, *domain.CallbackConfig] // biz id -> callback config
+	grpcTransport    Transport
+	webhookTransport Transport
 
-	grpcClinets *client.Manager[clientv1.CallbackServiceClient]
+	breakers *CircuitBreakers
+
+	deadLetterProducer mq.Producer[domain.CallbackDeadLetterEvent] // 为 nil 时不发送死信事件
 
 	shardingStrategy sharding.Strategy
 
@@ -107,38 +112,107 @@ func (s *DefaultService) Send(ctx context.Context, startTime int64, batchSize in
 	return nil
 }
 
+// SendByNotification 立即发送消息对应的回调请求。
 func (s *DefaultService) SendByNotification(ctx context.Context, n domain.Notification) error {
-	// TODO: implement me
-	panic("implement me")
+	return s.SendByNotifications(ctx, []domain.Notification{n})
 }
 
+// SendByNotifications 立即发送消息对应的回调请求并更新回调日志状态。
+// 没有回调日志或者回调已经结束的消息直接忽略。
 func (s *DefaultService) SendByNotifications(ctx context.Context, ns []domain.Notification) error {
-	// TODO: implement me
-	panic("implement me")
+	if len(ns) == 0 {
+		return nil
+	}
+
+	// 回调日志按照 biz_id 和 biz_key 分库分表，这里按分表分组查询。
+	dsts := make(map[string]sharding.Dst)
+	dstGroup := make(map[string][]domain.Notification)
+	for _, n := range ns {
+		dst := s.shardingStrategy.Shard(n.BizId, n.BizKey)
+		dsts[dst.FullTable()] = dst
+		dstGroup[dst.FullTable()] = append(dstGroup[dst.FullTable()], n)
+	}
+
+	var errList []error
+	for fullTable, group := range dstGroup {
+		if err := s.sendDstNotifications(ctx, dsts[fullTable], group); err != nil {
+			s.logger.Error("[kuryr] failed to send callback by notifications",
+				zap.String("table", fullTable),
+				zap.Error(err),
+			)
+			errList = append(errList, fmt.Errorf("table = %s: %w", fullTable, err))
+		}
+	}
+	return errors.Join(errList...)
+}
+
+// sendDstNotifications 发送同一个分表内消息的回调请求。
+func (s *DefaultService) sendDstNotifications(ctx context.Context, dst sharding.Dst, ns []domain.Notification) error {
+	nMap := make(map[string]domain.Notification, len(ns))
+	ids := make([]string, 0, len(ns))
+	for _, n := range ns {
+		nMap[n.Id] = n
+		ids = append(ids, n.Id)
+	}
+
+	logs, err := s.logRepo.FindByNotificationIds(ctx, dst, ids)
+	if err != nil {
+		return err
+	}
+
+	needSends := make([]domain.CallbackLog, 0, len(logs))
+	for _, log := range logs {
+		if log.Status != domain.CallbackLogStatusPrepare && log.Status != domain.CallbackLogStatusPending {
+			continue
+		}
+
+		n, ok := nMap[log.Notification.Id]
+		if !ok {
+			continue
+		}
+
+		// 回调日志中只保存了消息 id 和发送状态，这里使用完整的消息构建回调请求。
+		log.Notification = n
+		needSends = append(needSends, log)
+	}
+
+	return s.batchSendAndUpdateStatus(ctx, dst, needSends)
 }
 
 func (s *DefaultService) dealDstCallbackLogs(ctx context.Context, dst sharding.Dst, startTime int64, batchSize int) error {
-	nextStartId := uint64(0)
+	var (
+		startId     uint64
+		nextStartId uint64
+		logs        []domain.CallbackLog
+		err         error
+	)
 
 	for {
-		logs, nextStartId, err := s.logRepo.BatchFindByTime(ctx, dst, startTime, nextStartId, batchSize)
+		logs, nextStartId, err = s.logRepo.BatchFindByTime(ctx, dst, startTime, startId, batchSize)
 		if err != nil {
 			s.logger.Error("[kuryr] failed to find callback logs",
 				zap.Int64("start_time", startTime),
-				zap.Uint64("start_id", nextStartId),
+				zap.Uint64("start_id", startId),
 				zap.Int("batch_size", batchSize),
 				zap.Error(err),
 			)
 			return err
 		}
 
-		if len(logs) == 0 {
+		if len(logs) == 0 || nextStartId <= startId {
 			break
 		}
 
-		if err := s.batchSendAndUpdateStatus(ctx, dst, logs); err != nil {
+		if err = s.batchSendAndUpdateStatus(ctx, dst, logs); err != nil {
 			return err
 		}
+
+		// 无论本批次是否发送成功都越过已处理的记录，
+		// 仍需重试的记录 next_retry_at 已推迟，由下一轮任务处理。
+		startId = nextStartId
+		if len(logs) < batchSize {
+			break
+		}
 	}
 	return nil
 }
@@ -153,10 +227,7 @@ func (s *DefaultService) batchSendAndUpdateStatus(ctx context.Context, dst shard
 	for i := range logs {
 		changed, err := s.sendAndSetChangedFields(ctx, &logs[i])
 		if err != nil {
-			s.logger.Warn("[kuryr] failed to send callback",
-				zap.String("notification_id", logs[i].Notification.Id),
-				zap.Error(err),
-			)
+			// 回调配置获取失败，跳过本条记录，由下一轮任务处理。
 			continue
 		}
 
@@ -165,32 +236,62 @@ func (s *DefaultService) batchSendAndUpdateStatus(ctx context.Context, dst shard
 		}
 	}
 
-	return s.logRepo.BatchUpdate(ctx, dst, needUpdates)
+	if err := s.logRepo.BatchUpdate(ctx, dst, needUpdates); err != nil {
+		return err
+	}
+
+	s.sendDeadLetters(ctx, needUpdates)
+	return nil
 }
 
 // sendAndSetChangedFields 发送请求并设置需要更新的字段。
 func (s *DefaultService) sendAndSetChangedFields(ctx context.Context, log *domain.CallbackLog) (bool, error) {
-	resp, err := s.send(ctx, log.Notification)
-	if err != nil {
+	bizId := log.Notification.BizId
+	if retryAt, ok := s.breakers.Allow(bizId); !ok {
+		// 业务方熔断中，不发送请求，只推迟下一次重试时间，不计入重试次数。
+		log.NextRetryAt = retryAt.UnixMilli()
+		return true, nil
+	}
+
+	cfg, err := s.getCallbackConfig(ctx, bizId)
+	if err != nil && !errors.Is(err, errs.ErrRecordNotFound) {
+		// 获取回调配置异常 ( 例如 db 不可用 ) 不计入重试次数，由下一轮任务处理。
+		s.logger.Warn("[kuryr] failed to get callback config",
+			zap.Uint64("biz_id", bizId),
+			zap.Error(err),
+		)
 		return false, err
 	}
 
-	if resp.Success {
+	if cfg == nil {
+		// 业务方没有回调配置，无法发送也无法重试，直接失败。
+		log.Status = domain.CallbackLogStatusFailure
+		return true, nil
+	}
+
+	success, err := s.send(ctx, cfg, log.Notification)
+	s.breakers.Report(bizId, err == nil && success)
+	if err != nil {
+		// 连接失败、超时等传输错误同样视为一次失败的回调，按重试策略处理。
+		s.logger.Warn("[kuryr] failed to send callback",
+			zap.String("notification_id", log.Notification.Id),
+			zap.Error(err),
+		)
+	} else if success {
 		log.Status = domain.CallbackLogStatusSuccess
 		return true, nil
 	}
 
-	// 发送失败意味着回调配置一定存在。
-	cfg, _ := s.getCallbackConfig(ctx, log.Notification.BizId)
-	retryStrategy, err := retry.NewRetryStrategy(*cfg.RetryPolicyConfig)
+	retryStrategy, err := retry.NewRetryStrategy(cfg.RetryPolicy())
 	if err != nil {
 		// 获取重试策略异常只可能是配置错误，直接失败。
 		log.Status = domain.CallbackLogStatusFailure
 		return true, nil
 	}
 
-	// 成功获取重试策略，计算下一次请求时间
-	interval, ok := retryStrategy.Next()
+	// 重试策略每次都是新建的，需要按日志已经重试的次数计算下一次请求时间。
+	// 重试次数耗尽后标记为失败，由 batchSendAndUpdateStatus 发送到死信队列。
+	interval, ok := retryStrategy.NextWithRetried(log.RetriedTimes + 1)
 	if ok {
 		log.NextRetryAt = time.Now().Add(interval).UnixMilli()
 		log.RetriedTimes++
@@ -200,50 +301,25 @@ func (s *DefaultService) sendAndSetChangedFields(ctx context.Context, log *domai
 	return true, nil
 }
 
-// send 调用 grpc 接口发送回调通知。
-func (s *DefaultService) send(ctx context.Context, notification domain.Notification) (*clientv1.SendResultNotifyResponse, error) {
-	cfg, err := s.getCallbackConfig(ctx, notification.BizId)
-	if err != nil {
-		s.logger.Warn("[kuryr] failed to get callback config",
-			zap.Uint64("biz_id", notification.BizId),
-			zap.Error(err),
-		)
-		return nil, err
-	}
-
-	if cfg == nil {
-		return nil, fmt.Errorf("%w: no callback config provided by the business side", errs.ErrRecordNotFound)
-	}
-
-	grpcClient, err := s.grpcClinets.Get(cfg.ServiceName)
-	if err != nil {
-		return nil, fmt.Errorf("[kuryr] failed to get grpc client: %w", err)
+// send 按业务方配置的回调方式发送回调通知，返回业务方是否处理成功。
+func (s *DefaultService) send(ctx context.Context, cfg *domain.CallbackConfig, notification domain.Notification) (bool, error) {
+	if cfg.Transport.IsWebhook() {
+		return s.webhookTransport.Notify(ctx, cfg, s.buildGrpcRequest(notification))
 	}
-
-	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
-	defer cancel()
-
-	return grpcClient.SendResultNotify(ctx, s.buildGrpcRequest(notification))
+	return s.grpcTransport.Notify(ctx, cfg, s.buildGrpcRequest(notification))
 }
 
 // getCallbackConfig 获取回调配置。
 // 包含 grpc 服务名，重试策略等。
+//
+// 回调配置直接从业务方配置读取，由 bizConfigRepo 的两级缓存 ( 按版本同步并定期对账 ) 保证时效，
+// 这里不再单独缓存，避免业务方配置变更后继续使用旧的回调配置。
 func (s *DefaultService) getCallbackConfig(ctx context.Context, bizId uint64) (*domain.CallbackConfig, error) {
-	if cfg, ok := s.callbackConfigMap.Load(bizId); ok {
-		return cfg, nil
-	}
-
 	bizConfig, err := s.bizConfigRepo.FindByBizId(ctx, bizId)
 	if err != nil {
 		return nil, err
 	}
-
-	if bizConfig.CallbackConfig != nil {
-		s.callbackConfigMap.Store(bizId, bizConfig.CallbackConfig)
-		return bizConfig.CallbackConfig, nil
-	}
-
-	return nil, nil
+	return bizConfig.CallbackConfig, nil
 }
 
 // buildGrpcRequest 构建 grpc 请求。
@@ -254,9 +330,10 @@ func (s *DefaultService) buildGrpcRequest(notification domain.Notification) *cli
 		tplPrams = notification.Template.Params
 	}
 
+	// 注意：
+	//  SendResultNotifyRequest.NotificationId 仍然是 uint64，无法承载 MongoDB 的 ObjectID，
+	//  消息 id 通过 SendResult.NotificationId 传递。
 	return &clientv1.SendResultNotifyRequest{
-		// TODO: notification uint64 -> string
-		// NotificationId: notification.Id,
 		RawRequest: &notificationv1.SendRequest{
 			Notification: &notificationv1.Notification{
 				BizKey:    notification.BizKey,
@@ -267,9 +344,8 @@ func (s *DefaultService) buildGrpcRequest(notification domain.Notification) *cli
 			},
 		},
 		Result: &notificationv1.SendResult{
-			// TODO: notification uint64 -> string
-			// NotificationId: notification.Id,
-			Status: s.transferSendStatus(notification.SendStatus),
+			NotificationId: notification.Id,
+			Status:         s.transferSendStatus(notification.SendStatus),
 		},
 	}
 }
@@ -305,17 +381,22 @@ func (s *DefaultService) transferSendStatus(sendStatus domain.SendStatus) notifi
 
 func NewDefaultService(
 	grpcClinets *client.Manager[clientv1.CallbackServiceClient],
+	webhookClient *http.Client,
 	shardingStrategy sharding.Strategy,
 	logRepo repository.CallbackLogRepo,
 	bizConfigRepo repository.BizConfigRepo,
+	breakers *CircuitBreakers,
+	deadLetterProducer mq.Producer[domain.CallbackDeadLetterEvent],
 	logger *zap.Logger,
 ) *DefaultService {
 	return &DefaultService{
-		callbackConfigMap: xsync.Map[uint64, *domain.CallbackConfig]{},
-		grpcClinets:       grpcClinets,
-		shardingStrategy:  shardingStrategy,
-		logRepo:           logRepo,
-		bizConfigRepo:     bizConfigRepo,
-		logger:            logger,
+		grpcTransport:      NewGrpcTransport(grpcClinets, 3*time.Second),
+		webhookTransport:   NewWebhookTransport(webhookClient),
+		shardingStrategy:   shardingStrategy,
+		logRepo:            logRepo,
+		bizConfigRepo:      bizConfigRepo,
+		breakers:           breakers,
+		deadLetterProducer: deadLetterProducer,
+		logger:             logger,
 	}
 }
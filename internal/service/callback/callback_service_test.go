@@ -0,0 +1,194 @@
+package callback
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/JrMarcco/kuryr/internal/domain"
+	"github.com/JrMarcco/kuryr/internal/pkg/mq"
+	mqmock "github.com/JrMarcco/kuryr/internal/pkg/mq/mock"
+	"github.com/JrMarcco/kuryr/internal/pkg/retry"
+	"github.com/JrMarcco/kuryr/internal/pkg/sharding"
+	"github.com/JrMarcco/kuryr/internal/repository"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+// stubLogRepo 按 id 升序分页返回待回调的日志，并记录每次查询的起始 id。
+type stubLogRepo struct {
+	repository.CallbackLogRepo
+
+	logs     []domain.CallbackLog
+	startIds []uint64
+	updated  []domain.CallbackLog
+}
+
+func (r *stubLogRepo) BatchFindByTime(_ context.Context, _ sharding.Dst, _ int64, startId uint64, batchSize int) ([]domain.CallbackLog, uint64, error) {
+	r.startIds = append(r.startIds, startId)
+
+	var res []domain.CallbackLog
+	for _, log := range r.logs {
+		if log.Id > startId && len(res) < batchSize {
+			res = append(res, log)
+		}
+	}
+	if len(res) == 0 {
+		return nil, 0, nil
+	}
+	return res, res[len(res)-1].Id, nil
+}
+
+func (r *stubLogRepo) BatchUpdate(_ context.Context, _ sharding.Dst, logs []domain.CallbackLog) error {
+	r.updated = append(r.updated, logs...)
+	return nil
+}
+
+type stubBizConfigRepo struct {
+	repository.BizConfigRepo
+
+	cfg *domain.CallbackConfig
+}
+
+func (r *stubBizConfigRepo) FindByBizId(_ context.Context, bizId uint64) (domain.BizConfig, error) {
+	return domain.BizConfig{BizId: bizId, CallbackConfig: r.cfg}, nil
+}
+
+func newTestBreakers() *CircuitBreakers {
+	return NewCircuitBreakers(CircuitBreakerConfig{
+		FailureThreshold: 100,
+		SuccessThreshold: 1,
+		CoolDownPeriod:   time.Minute,
+	}, prometheus.NewRegistry())
+}
+
+func TestDefaultService_dealDstCallbackLogs(t *testing.T) {
+	t.Parallel()
+
+	logRepo := &stubLogRepo{}
+	for id := uint64(1); id <= 5; id++ {
+		logRepo.logs = append(logRepo.logs, domain.CallbackLog{
+			Id:           id,
+			Notification: domain.Notification{Id: "n", BizId: 1},
+			Status:       domain.CallbackLogStatusPending,
+		})
+	}
+
+	// 业务方没有回调配置，每条日志都直接失败，游标仍然需要推进。
+	svc := NewDefaultService(nil, nil, nil, logRepo, &stubBizConfigRepo{}, newTestBreakers(), nil, zap.NewNop())
+	require.NoError(t, svc.dealDstCallbackLogs(context.Background(), sharding.Dst{}, time.Now().UnixMilli(), 2))
+	assert.Equal(t, []uint64{0, 2, 4}, logRepo.startIds)
+}
+
+func TestDefaultService_batchSendAndUpdateStatus(t *testing.T) {
+	t.Parallel()
+
+	// 业务方 webhook 始终返回失败。
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(server.Close)
+
+	// 业务方 webhook 不可达。
+	closedServer := httptest.NewServer(http.NotFoundHandler())
+	closedServer.Close()
+
+	newCfg := func(url string) *domain.CallbackConfig {
+		return &domain.CallbackConfig{
+			Transport: domain.CallbackTransportWebhook,
+			Webhook:   &domain.WebhookConfig{Url: url, Secret: "secret"},
+			RetryPolicyConfig: &retry.Config{
+				Type:          retry.StrategyTypeFixedInterval,
+				FixedInterval: &retry.FixedIntervalConfig{Interval: time.Minute, MaxRetryTimes: 2},
+			},
+		}
+	}
+
+	tcs := []struct {
+		name             string
+		cfg              *domain.CallbackConfig
+		retriedTimes     int32
+		wantStatus       domain.CallbackLogStatus
+		wantRetriedTimes int32
+		wantDeadLetter   bool
+	}{
+		{
+			name:             "retry",
+			cfg:              newCfg(server.URL),
+			retriedTimes:     1,
+			wantStatus:       domain.CallbackLogStatusPending,
+			wantRetriedTimes: 2,
+		}, {
+			name:             "retry exhausted",
+			cfg:              newCfg(server.URL),
+			retriedTimes:     2,
+			wantStatus:       domain.CallbackLogStatusFailure,
+			wantRetriedTimes: 2,
+			wantDeadLetter:   true,
+		}, {
+			name:             "transport error retry",
+			cfg:              newCfg(closedServer.URL),
+			retriedTimes:     0,
+			wantStatus:       domain.CallbackLogStatusPending,
+			wantRetriedTimes: 1,
+		}, {
+			name:             "transport error retry exhausted",
+			cfg:              newCfg(closedServer.URL),
+			retriedTimes:     2,
+			wantStatus:       domain.CallbackLogStatusFailure,
+			wantRetriedTimes: 2,
+			wantDeadLetter:   true,
+		}, {
+			name:             "no callback config",
+			cfg:              nil,
+			retriedTimes:     0,
+			wantStatus:       domain.CallbackLogStatusFailure,
+			wantRetriedTimes: 0,
+			wantDeadLetter:   true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			producer := mqmock.NewMockProducer[domain.CallbackDeadLetterEvent](ctrl)
+			if tc.wantDeadLetter {
+				producer.EXPECT().ProduceAsync(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, event domain.CallbackDeadLetterEvent, _ ...mq.ProduceOption) (*mq.DeliveryFuture, error) {
+						assert.Equal(t, uint64(1), event.CallbackLogId)
+						assert.Equal(t, tc.retriedTimes, event.RetriedTimes)
+						// 投递结果不影响回调日志状态。
+						return nil, errors.New("mock produce error")
+					},
+				)
+			}
+
+			logRepo := &stubLogRepo{}
+			svc := NewDefaultService(
+				nil, http.DefaultClient, nil, logRepo, &stubBizConfigRepo{cfg: tc.cfg}, newTestBreakers(), producer, zap.NewNop(),
+			)
+
+			logs := []domain.CallbackLog{{
+				Id:           1,
+				Notification: domain.Notification{Id: "n", BizId: 1},
+				RetriedTimes: tc.retriedTimes,
+				Status:       domain.CallbackLogStatusPending,
+			}}
+			require.NoError(t, svc.batchSendAndUpdateStatus(context.Background(), sharding.Dst{}, logs))
+
+			require.Len(t, logRepo.updated, 1)
+			assert.Equal(t, tc.wantStatus, logRepo.updated[0].Status)
+			assert.Equal(t, tc.wantRetriedTimes, logRepo.updated[0].RetriedTimes)
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package callback
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/JrMarcco/easy-kit/xsync"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type state string
+
+const (
+	stateOpen     state = "open"
+	stateClosed   state = "closed"
+	stateHalfOpen state = "half-open"
+)
+
+// metricValue 熔断器状态对应的指标值。
+func (s state) metricValue() float64 {
+	switch s {
+	case stateHalfOpen:
+		return 1
+	case stateOpen:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// CircuitBreakerConfig 熔断器配置。
+type CircuitBreakerConfig struct {
+	FailureThreshold int           // 连续失败多少次后打开熔断器
+	SuccessThreshold int           // 半开状态下连续成功多少次后关闭熔断器
+	CoolDownPeriod   time.Duration // 熔断器打开后的冷却时间
+}
+
+// circuitBreaker 单个业务方的熔断器。
+type circuitBreaker struct {
+	mu sync.Mutex
+
+	state state
+
+	failureCnt int
+	successCnt int
+
+	openedAt time.Time
+	// probeAt 半开状态下探测请求的放行时间，零值表示没有探测请求在执行。
+	// 探测请求超过冷却时间仍未上报结果 ( 例如调用方异常退出 ) 时视为丢失，允许放行新的探测请求。
+	probeAt time.Time
+}
+
+// CircuitBreakers 按业务方隔离的回调熔断器。
+//
+// 每个业务方的回调地址单独熔断，避免一个业务方的回调异常影响其他业务方。
+// 半开状态下同一时间只放行一个探测请求，其余请求按熔断处理，探测请求超过冷却时间未上报结果时重新放行。
+type CircuitBreakers struct {
+	cfg CircuitBreakerConfig
+
+	breakers xsync.Map[uint64, *circuitBreaker] // biz id -> circuit breaker
+
+	stateGauge    *prometheus.GaugeVec
+	rejectCounter *prometheus.CounterVec
+
+	nowFunc func() time.Time
+}
+
+// Allow 判断是否允许向业务方发送回调请求。
+// 不允许时返回下一次可以尝试的时间。
+func (cbs *CircuitBreakers) Allow(bizId uint64) (time.Time, bool) {
+	cb := cbs.get(bizId)
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	now := cbs.nowFunc()
+	switch cb.state {
+	case stateOpen:
+		retryAt := cb.openedAt.Add(cbs.cfg.CoolDownPeriod)
+		if now.Before(retryAt) {
+			cbs.reject(bizId)
+			return retryAt, false
+		}
+
+		// 过了冷却期，尝试半开。
+		cbs.setState(bizId, cb, stateHalfOpen)
+		cb.probeAt = now
+		return time.Time{}, true
+	case stateHalfOpen:
+		if !cb.probeAt.IsZero() {
+			if expireAt := cb.probeAt.Add(cbs.cfg.CoolDownPeriod); now.Before(expireAt) {
+				cbs.reject(bizId)
+				return expireAt, false
+			}
+		}
+		cb.probeAt = now
+		return time.Time{}, true
+	default:
+		return time.Time{}, true
+	}
+}
+
+// Report 上报回调请求结果。
+func (cbs *CircuitBreakers) Report(bizId uint64, success bool) {
+	cb := cbs.get(bizId)
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.probeAt = time.Time{}
+
+	if !success {
+		cb.successCnt = 0
+		cb.failureCnt++
+
+		if cb.state == stateHalfOpen || cb.failureCnt >= cbs.cfg.FailureThreshold {
+			cb.openedAt = cbs.nowFunc()
+			cbs.setState(bizId, cb, stateOpen)
+		}
+		return
+	}
+
+	cb.failureCnt = 0
+	if cb.state != stateHalfOpen {
+		return
+	}
+
+	cb.successCnt++
+	if cb.successCnt >= cbs.cfg.SuccessThreshold {
+		cb.successCnt = 0
+		cbs.setState(bizId, cb, stateClosed)
+	}
+}
+
+func (cbs *CircuitBreakers) get(bizId uint64) *circuitBreaker {
+	if cb, ok := cbs.breakers.Load(bizId); ok {
+		return cb
+	}
+
+	cb, _ := cbs.breakers.LoadOrStore(bizId, &circuitBreaker{state: stateClosed})
+	return cb
+}
+
+func (cbs *CircuitBreakers) setState(bizId uint64, cb *circuitBreaker, s state) {
+	cb.state = s
+	cbs.stateGauge.WithLabelValues(strconv.FormatUint(bizId, 10)).Set(s.metricValue())
+}
+
+func (cbs *CircuitBreakers) reject(bizId uint64) {
+	cbs.rejectCounter.WithLabelValues(strconv.FormatUint(bizId, 10)).Inc()
+}
+
+func NewCircuitBreakers(cfg CircuitBreakerConfig, registerer prometheus.Registerer) *CircuitBreakers {
+	stateGauge := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kuryr_callback_circuit_breaker_state",
+			Help: "The state of callback circuit breaker, 0: closed, 1: half-open, 2: open",
+		},
+		[]string{"biz_id"},
+	)
+
+	rejectCounter := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kuryr_callback_circuit_breaker_reject_count",
+			Help: "The count of callbacks rejected by circuit breaker",
+		},
+		[]string{"biz_id"},
+	)
+
+	// 注册指标
+	registerer.MustRegister(stateGauge, rejectCounter)
+
+	return &CircuitBreakers{
+		cfg:           cfg,
+		breakers:      xsync.Map[uint64, *circuitBreaker]{},
+		stateGauge:    stateGauge,
+		rejectCounter: rejectCounter,
+		nowFunc:       time.Now,
+	}
+}
@@ -0,0 +1,60 @@
+package callback
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreakers(t *testing.T) {
+	t.Parallel()
+
+	now := time.UnixMilli(1735689600000)
+	cbs := NewCircuitBreakers(CircuitBreakerConfig{
+		FailureThreshold: 2,
+		SuccessThreshold: 1,
+		CoolDownPeriod:   time.Minute,
+	}, prometheus.NewRegistry())
+	cbs.nowFunc = func() time.Time { return now }
+
+	// 连续失败达到阈值后熔断。
+	for range 2 {
+		_, ok := cbs.Allow(1)
+		assert.True(t, ok)
+		cbs.Report(1, false)
+	}
+
+	retryAt, ok := cbs.Allow(1)
+	assert.False(t, ok)
+	assert.Equal(t, now.Add(time.Minute), retryAt)
+	assert.Equal(t, float64(2), testutil.ToFloat64(cbs.stateGauge.WithLabelValues("1")))
+
+	// 其他业务方不受影响。
+	_, ok = cbs.Allow(2)
+	assert.True(t, ok)
+
+	// 冷却期后只放行一个探测请求。
+	now = now.Add(time.Minute)
+	_, ok = cbs.Allow(1)
+	assert.True(t, ok)
+	_, ok = cbs.Allow(1)
+	assert.False(t, ok)
+	assert.Equal(t, float64(1), testutil.ToFloat64(cbs.stateGauge.WithLabelValues("1")))
+
+	// 探测请求超过冷却时间未上报结果时视为丢失，重新放行一个探测请求。
+	now = now.Add(time.Minute)
+	_, ok = cbs.Allow(1)
+	assert.True(t, ok)
+	_, ok = cbs.Allow(1)
+	assert.False(t, ok)
+
+	// 探测成功后关闭熔断器。
+	cbs.Report(1, true)
+	_, ok = cbs.Allow(1)
+	assert.True(t, ok)
+	assert.Equal(t, float64(0), testutil.ToFloat64(cbs.stateGauge.WithLabelValues("1")))
+	assert.Equal(t, float64(3), testutil.ToFloat64(cbs.rejectCounter.WithLabelValues("1")))
+}
@@ -0,0 +1,202 @@
+package callback
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/JrMarcco/kuryr/internal/domain"
+	"github.com/JrMarcco/kuryr/internal/errs"
+	"github.com/JrMarcco/kuryr/internal/pkg/mq"
+	"github.com/JrMarcco/kuryr/internal/pkg/sharding"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+const maxFailuresLimit = 500
+
+// ListFailures 跨分表按 id 倒序分页查询业务方重试耗尽的回调日志。
+// cursor 为上一页最后一条记录的 id，为 0 表示查询第一页。
+//
+// 每个分表各查询 limit 条再合并取前 limit 条，
+// 由于回调日志 id 带时间戳，按 id 倒序即按创建时间倒序。
+func (s *DefaultService) ListFailures(
+	ctx context.Context, bizId uint64, startTime, endTime int64, cursor uint64, limit int,
+) ([]domain.CallbackLog, error) {
+	if bizId == 0 {
+		return nil, fmt.Errorf("%w: biz id cannot be empty", errs.ErrInvalidParam)
+	}
+	if startTime >= endTime {
+		return nil, fmt.Errorf("%w: invalid time range [ %d, %d )", errs.ErrInvalidParam, startTime, endTime)
+	}
+	if limit <= 0 || limit > maxFailuresLimit {
+		return nil, fmt.Errorf("%w: limit must be in range [ 1, %d ]", errs.ErrInvalidParam, maxFailuresLimit)
+	}
+
+	var mu sync.Mutex
+	res := make([]domain.CallbackLog, 0, limit)
+
+	err := s.broadcast(ctx, func(ctx context.Context, dst sharding.Dst) error {
+		logs, err := s.logRepo.FindFailures(ctx, dst, bizId, startTime, endTime, cursor, limit)
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		res = append(res, logs...)
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(res, func(a, b domain.CallbackLog) int {
+		switch {
+		case a.Id > b.Id:
+			return -1
+		case a.Id < b.Id:
+			return 1
+		default:
+			return 0
+		}
+	})
+	if len(res) > limit {
+		res = res[:limit]
+	}
+	return res, nil
+}
+
+// Replay 重放指定的失败回调日志，重置重试次数后由回调任务重新发送。
+func (s *DefaultService) Replay(ctx context.Context, bizId uint64, ids []uint64, operatorId uint64) (int64, error) {
+	if bizId == 0 {
+		return 0, fmt.Errorf("%w: biz id cannot be empty", errs.ErrInvalidParam)
+	}
+	if len(ids) == 0 {
+		return 0, fmt.Errorf("%w: callback log ids cannot be empty", errs.ErrInvalidParam)
+	}
+
+	dsts := make(map[string]sharding.Dst)
+	dstIds := make(map[string][]uint64)
+	for _, id := range ids {
+		dst := s.shardingStrategy.DstFromId(id)
+		dsts[dst.FullTable()] = dst
+		dstIds[dst.FullTable()] = append(dstIds[dst.FullTable()], id)
+	}
+
+	var replayed int64
+	var err error
+	for fullTable, tableIds := range dstIds {
+		cnt, resetErr := s.logRepo.ResetFailures(ctx, dsts[fullTable], bizId, tableIds)
+		if resetErr != nil {
+			err = fmt.Errorf("table = %s: %w", fullTable, resetErr)
+			break
+		}
+		replayed += cnt
+	}
+
+	// 部分分表失败时已经重放的记录同样需要审计。
+	s.saveReplayLog(ctx, domain.CallbackReplayLog{
+		BizId:          bizId,
+		CallbackLogIds: ids,
+		ReplayedCount:  replayed,
+		OperatorId:     operatorId,
+	})
+	return replayed, err
+}
+
+// ReplayByTime 重放创建时间在 [startTime, endTime) 范围内的全部失败回调日志。
+func (s *DefaultService) ReplayByTime(ctx context.Context, bizId uint64, startTime, endTime int64, operatorId uint64) (int64, error) {
+	if bizId == 0 {
+		return 0, fmt.Errorf("%w: biz id cannot be empty", errs.ErrInvalidParam)
+	}
+	if startTime >= endTime {
+		return 0, fmt.Errorf("%w: invalid time range [ %d, %d )", errs.ErrInvalidParam, startTime, endTime)
+	}
+
+	var mu sync.Mutex
+	var replayed int64
+
+	err := s.broadcast(ctx, func(ctx context.Context, dst sharding.Dst) error {
+		cnt, err := s.logRepo.ResetFailuresByTime(ctx, dst, bizId, startTime, endTime)
+
+		mu.Lock()
+		replayed += cnt
+		mu.Unlock()
+		return err
+	})
+
+	// 部分分表失败时已经重放的记录同样需要审计。
+	s.saveReplayLog(ctx, domain.CallbackReplayLog{
+		BizId:         bizId,
+		StartTime:     startTime,
+		EndTime:       endTime,
+		ReplayedCount: replayed,
+		OperatorId:    operatorId,
+	})
+	return replayed, err
+}
+
+func (s *DefaultService) saveReplayLog(ctx context.Context, log domain.CallbackReplayLog) {
+	if err := s.logRepo.SaveReplayLog(ctx, log); err != nil {
+		// 审计记录保存失败不影响重放结果。
+		s.logger.Error("[kuryr] failed to save callback replay log", zap.Any("replay_log", log), zap.Error(err))
+	}
+}
+
+// broadcast 并发在所有分表上执行 fn。
+func (s *DefaultService) broadcast(ctx context.Context, fn func(ctx context.Context, dst sharding.Dst) error) error {
+	eg, ctx := errgroup.WithContext(ctx)
+	// TODO: 这里最大并发数可以改成配置。
+	eg.SetLimit(8)
+
+	for _, dst := range s.shardingStrategy.Broadcast() {
+		eg.Go(func() error {
+			if err := fn(ctx, dst); err != nil {
+				return fmt.Errorf("table = %s: %w", dst.FullTable(), err)
+			}
+			return nil
+		})
+	}
+	return eg.Wait()
+}
+
+// sendDeadLetters 把重试耗尽的回调日志发送到死信队列。
+// 先异步发送全部死信事件，再统一等待投递结果。
+func (s *DefaultService) sendDeadLetters(ctx context.Context, logs []domain.CallbackLog) {
+	if s.deadLetterProducer == nil {
+		return
+	}
+
+	now := time.Now().UnixMilli()
+	futures := make(map[uint64]*mq.DeliveryFuture, len(logs))
+	for _, log := range logs {
+		if log.Status != domain.CallbackLogStatusFailure {
+			continue
+		}
+
+		event := domain.CallbackDeadLetterEvent{
+			CallbackLogId:      log.Id,
+			BizId:              log.BizId,
+			BizKey:             log.BizKey,
+			NotificationId:     log.Notification.Id,
+			NotificationStatus: log.Notification.SendStatus,
+			RetriedTimes:       log.RetriedTimes,
+			FailedAt:           now,
+		}
+		future, err := s.deadLetterProducer.ProduceAsync(ctx, event)
+		if err != nil {
+			s.logger.Error("[kuryr] failed to produce callback dead letter event", zap.Uint64("callback_log_id", log.Id), zap.Error(err))
+			continue
+		}
+		futures[log.Id] = future
+	}
+
+	for logId, future := range futures {
+		if _, err := future.Wait(ctx); err != nil {
+			s.logger.Error("[kuryr] failed to deliver callback dead letter event", zap.Uint64("callback_log_id", logId), zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package callback
+
+import (
+	"context"
+	"testing"
+
+	"github.com/JrMarcco/kuryr/internal/domain"
+	"github.com/JrMarcco/kuryr/internal/errs"
+	"github.com/JrMarcco/kuryr/internal/pkg/sharding"
+	"github.com/JrMarcco/kuryr/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// stubCallbackLogRepo 只实现 FindFailures，按分表返回预置的回调日志。
+type stubCallbackLogRepo struct {
+	repository.CallbackLogRepo
+	logs map[string][]domain.CallbackLog // full table -> logs ( 按 id 倒序 )
+}
+
+func (r *stubCallbackLogRepo) FindFailures(
+	_ context.Context, dst sharding.Dst, _ uint64, _, _ int64, cursor uint64, limit int,
+) ([]domain.CallbackLog, error) {
+	res := make([]domain.CallbackLog, 0, limit)
+	for _, log := range r.logs[dst.FullTable()] {
+		if cursor > 0 && log.Id >= cursor {
+			continue
+		}
+		if len(res) == limit {
+			break
+		}
+		res = append(res, log)
+	}
+	return res, nil
+}
+
+func TestDefaultService_ListFailures(t *testing.T) {
+	t.Parallel()
+
+	repo := &stubCallbackLogRepo{
+		logs: map[string][]domain.CallbackLog{
+			"kuryr_0.callback_log_0": {{Id: 9}, {Id: 6}, {Id: 2}},
+			"kuryr_0.callback_log_1": {{Id: 8}, {Id: 5}},
+			"kuryr_1.callback_log_0": {{Id: 7}, {Id: 4}, {Id: 1}},
+			"kuryr_1.callback_log_1": {{Id: 3}},
+		},
+	}
+	svc := NewDefaultService(nil, nil, sharding.NewHashSharding("kuryr", "callback_log", 2, 2), repo, nil, nil, nil, zap.NewNop())
+
+	tcs := []struct {
+		name    string
+		cursor  uint64
+		limit   int
+		wantIds []uint64
+		wantErr error
+	}{
+		{name: "first page", limit: 4, wantIds: []uint64{9, 8, 7, 6}},
+		{name: "next page", cursor: 6, limit: 4, wantIds: []uint64{5, 4, 3, 2}},
+		{name: "last page", cursor: 2, limit: 4, wantIds: []uint64{1}},
+		{name: "invalid limit", limit: 0, wantErr: errs.ErrInvalidParam},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			logs, err := svc.ListFailures(context.Background(), 1, 0, 100, tc.cursor, tc.limit)
+			assert.ErrorIs(t, err, tc.wantErr)
+			if err != nil {
+				return
+			}
+
+			ids := make([]uint64, 0, len(logs))
+			for _, log := range logs {
+				ids = append(ids, log.Id)
+			}
+			assert.Equal(t, tc.wantIds, ids)
+		})
+	}
+}
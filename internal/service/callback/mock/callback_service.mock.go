@@ -0,0 +1,273 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./types.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./types.go -destination=./mock/callback_service.mock.go -package=callbackmock -typed Service
+//
+
+// Package callbackmock is a generated GoMock package.
+package callbackmock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/JrMarcco/kuryr/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockService is a mock of Service interface.
+type MockService struct {
+	ctrl     *gomock.Controller
+	recorder *MockServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockServiceMockRecorder is the mock recorder for MockService.
+type MockServiceMockRecorder struct {
+	mock *MockService
+}
+
+// NewMockService creates a new mock instance.
+func NewMockService(ctrl *gomock.Controller) *MockService {
+	mock := &MockService{ctrl: ctrl}
+	mock.recorder = &MockServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockService) EXPECT() *MockServiceMockRecorder {
+	return m.recorder
+}
+
+// ListFailures mocks base method.
+func (m *MockService) ListFailures(ctx context.Context, bizId uint64, startTime, endTime int64, cursor uint64, limit int) ([]domain.CallbackLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFailures", ctx, bizId, startTime, endTime, cursor, limit)
+	ret0, _ := ret[0].([]domain.CallbackLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFailures indicates an expected call of ListFailures.
+func (mr *MockServiceMockRecorder) ListFailures(ctx, bizId, startTime, endTime, cursor, limit any) *MockServiceListFailuresCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFailures", reflect.TypeOf((*MockService)(nil).ListFailures), ctx, bizId, startTime, endTime, cursor, limit)
+	return &MockServiceListFailuresCall{Call: call}
+}
+
+// MockServiceListFailuresCall wrap *gomock.Call
+type MockServiceListFailuresCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceListFailuresCall) Return(arg0 []domain.CallbackLog, arg1 error) *MockServiceListFailuresCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceListFailuresCall) Do(f func(context.Context, uint64, int64, int64, uint64, int) ([]domain.CallbackLog, error)) *MockServiceListFailuresCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceListFailuresCall) DoAndReturn(f func(context.Context, uint64, int64, int64, uint64, int) ([]domain.CallbackLog, error)) *MockServiceListFailuresCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Replay mocks base method.
+func (m *MockService) Replay(ctx context.Context, bizId uint64, ids []uint64, operatorId uint64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replay", ctx, bizId, ids, operatorId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Replay indicates an expected call of Replay.
+func (mr *MockServiceMockRecorder) Replay(ctx, bizId, ids, operatorId any) *MockServiceReplayCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replay", reflect.TypeOf((*MockService)(nil).Replay), ctx, bizId, ids, operatorId)
+	return &MockServiceReplayCall{Call: call}
+}
+
+// MockServiceReplayCall wrap *gomock.Call
+type MockServiceReplayCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceReplayCall) Return(arg0 int64, arg1 error) *MockServiceReplayCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceReplayCall) Do(f func(context.Context, uint64, []uint64, uint64) (int64, error)) *MockServiceReplayCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceReplayCall) DoAndReturn(f func(context.Context, uint64, []uint64, uint64) (int64, error)) *MockServiceReplayCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// ReplayByTime mocks base method.
+func (m *MockService) ReplayByTime(ctx context.Context, bizId uint64, startTime, endTime int64, operatorId uint64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplayByTime", ctx, bizId, startTime, endTime, operatorId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReplayByTime indicates an expected call of ReplayByTime.
+func (mr *MockServiceMockRecorder) ReplayByTime(ctx, bizId, startTime, endTime, operatorId any) *MockServiceReplayByTimeCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplayByTime", reflect.TypeOf((*MockService)(nil).ReplayByTime), ctx, bizId, startTime, endTime, operatorId)
+	return &MockServiceReplayByTimeCall{Call: call}
+}
+
+// MockServiceReplayByTimeCall wrap *gomock.Call
+type MockServiceReplayByTimeCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceReplayByTimeCall) Return(arg0 int64, arg1 error) *MockServiceReplayByTimeCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceReplayByTimeCall) Do(f func(context.Context, uint64, int64, int64, uint64) (int64, error)) *MockServiceReplayByTimeCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceReplayByTimeCall) DoAndReturn(f func(context.Context, uint64, int64, int64, uint64) (int64, error)) *MockServiceReplayByTimeCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Send mocks base method.
+func (m *MockService) Send(ctx context.Context, startTime int64, batchSize int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", ctx, startTime, batchSize)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockServiceMockRecorder) Send(ctx, startTime, batchSize any) *MockServiceSendCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockService)(nil).Send), ctx, startTime, batchSize)
+	return &MockServiceSendCall{Call: call}
+}
+
+// MockServiceSendCall wrap *gomock.Call
+type MockServiceSendCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceSendCall) Return(arg0 error) *MockServiceSendCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceSendCall) Do(f func(context.Context, int64, int) error) *MockServiceSendCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceSendCall) DoAndReturn(f func(context.Context, int64, int) error) *MockServiceSendCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// SendByNotification mocks base method.
+func (m *MockService) SendByNotification(ctx context.Context, n domain.Notification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendByNotification", ctx, n)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendByNotification indicates an expected call of SendByNotification.
+func (mr *MockServiceMockRecorder) SendByNotification(ctx, n any) *MockServiceSendByNotificationCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendByNotification", reflect.TypeOf((*MockService)(nil).SendByNotification), ctx, n)
+	return &MockServiceSendByNotificationCall{Call: call}
+}
+
+// MockServiceSendByNotificationCall wrap *gomock.Call
+type MockServiceSendByNotificationCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceSendByNotificationCall) Return(arg0 error) *MockServiceSendByNotificationCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceSendByNotificationCall) Do(f func(context.Context, domain.Notification) error) *MockServiceSendByNotificationCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceSendByNotificationCall) DoAndReturn(f func(context.Context, domain.Notification) error) *MockServiceSendByNotificationCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// SendByNotifications mocks base method.
+func (m *MockService) SendByNotifications(ctx context.Context, ns []domain.Notification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendByNotifications", ctx, ns)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendByNotifications indicates an expected call of SendByNotifications.
+func (mr *MockServiceMockRecorder) SendByNotifications(ctx, ns any) *MockServiceSendByNotificationsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendByNotifications", reflect.TypeOf((*MockService)(nil).SendByNotifications), ctx, ns)
+	return &MockServiceSendByNotificationsCall{Call: call}
+}
+
+// MockServiceSendByNotificationsCall wrap *gomock.Call
+type MockServiceSendByNotificationsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceSendByNotificationsCall) Return(arg0 error) *MockServiceSendByNotificationsCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceSendByNotificationsCall) Do(f func(context.Context, []domain.Notification) error) *MockServiceSendByNotificationsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceSendByNotificationsCall) DoAndReturn(f func(context.Context, []domain.Notification) error) *MockServiceSendByNotificationsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
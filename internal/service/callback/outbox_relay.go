@@ -0,0 +1,155 @@
+package callback
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/JrMarcco/kuryr/internal/domain"
+	"github.com/JrMarcco/kuryr/internal/pkg/sharding"
+	"github.com/JrMarcco/kuryr/internal/repository"
+	"go.uber.org/zap"
+)
+
+// OutboxRelay 回调 outbox 转发任务。
+//
+// 定时把回调 outbox 记录写入回调日志分表，写入成功后删除 outbox 记录。
+// 回调日志按 notification_id 幂等写入，多副本同时转发或者删除 outbox 失败时不会产生重复的回调日志；
+// 回调尚未结束时新的发送状态会覆盖回调日志中的发送状态。
+type OutboxRelay struct {
+	outboxRepo       repository.CallbackOutboxRepo
+	logRepo          repository.CallbackLogRepo
+	shardingStrategy sharding.Strategy
+
+	interval      time.Duration
+	retryInterval time.Duration // 写入回调日志失败后的重试间隔
+	batchSize     int
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	logger *zap.Logger
+}
+
+func (r *OutboxRelay) Start() error {
+	r.wg.Add(1)
+	go r.run()
+	return nil
+}
+
+func (r *OutboxRelay) Stop() error {
+	r.cancel()
+	r.wg.Wait()
+	return nil
+}
+
+func (r *OutboxRelay) run() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-ticker.C:
+			r.relay(r.ctx)
+		}
+	}
+}
+
+// relay 循环转发到期的 outbox 记录，直到没有更多待转发的记录。
+func (r *OutboxRelay) relay(ctx context.Context) {
+	for ctx.Err() == nil {
+		now := time.Now().UnixMilli()
+		outboxes, err := r.outboxRepo.FindPending(ctx, now, r.batchSize)
+		if err != nil {
+			r.logger.Error("[kuryr] failed to find pending callback outboxes", zap.Error(err))
+			return
+		}
+		if len(outboxes) == 0 {
+			return
+		}
+
+		// 删除或推迟 outbox 记录失败时停止本轮转发，避免重复读取同一批记录。
+		if !r.relayBatch(ctx, outboxes, now) || len(outboxes) < r.batchSize {
+			return
+		}
+	}
+}
+
+// relayBatch 转发一批 outbox 记录，返回 outbox 记录是否全部删除或推迟成功。
+func (r *OutboxRelay) relayBatch(ctx context.Context, outboxes []domain.CallbackOutbox, now int64) bool {
+	// 按分表分组，回调日志 id 与消息的 biz_id + biz_key 落在同一个分表。
+	dsts := make(map[string]sharding.Dst)
+	dstOutboxes := make(map[string][]domain.CallbackOutbox)
+	for _, outbox := range outboxes {
+		dst := r.shardingStrategy.Shard(outbox.BizId, outbox.BizKey)
+		dsts[dst.FullTable()] = dst
+		dstOutboxes[dst.FullTable()] = append(dstOutboxes[dst.FullTable()], outbox)
+	}
+
+	relayed := make([]domain.CallbackOutbox, 0, len(outboxes))
+	var failedIds []string
+	for fullTable, group := range dstOutboxes {
+		logs := make([]domain.CallbackLog, 0, len(group))
+		for _, outbox := range group {
+			logs = append(logs, domain.CallbackLog{
+				Notification: domain.Notification{
+					Id:         outbox.NotificationId,
+					SendStatus: outbox.SendStatus,
+				},
+				BizId:       outbox.BizId,
+				BizKey:      outbox.BizKey,
+				NextRetryAt: now,
+				Status:      domain.CallbackLogStatusPrepare,
+			})
+		}
+
+		if err := r.logRepo.BatchUpsert(ctx, dsts[fullTable], logs); err != nil {
+			r.logger.Error("[kuryr] failed to save callback logs from outbox", zap.String("table", fullTable), zap.Error(err))
+			for _, outbox := range group {
+				failedIds = append(failedIds, outbox.NotificationId)
+			}
+			continue
+		}
+		relayed = append(relayed, group...)
+	}
+
+	ok := true
+	if err := r.outboxRepo.Delete(ctx, relayed); err != nil {
+		// 删除失败的 outbox 记录下次会重新转发，回调日志幂等写入不会重复。
+		r.logger.Error("[kuryr] failed to delete relayed callback outboxes", zap.Error(err))
+		ok = false
+	}
+	if err := r.outboxRepo.Delay(ctx, failedIds, now+r.retryInterval.Milliseconds()); err != nil {
+		r.logger.Error("[kuryr] failed to delay callback outboxes", zap.Strings("notification_ids", failedIds), zap.Error(err))
+		ok = false
+	}
+	return ok
+}
+
+func NewOutboxRelay(
+	outboxRepo repository.CallbackOutboxRepo,
+	logRepo repository.CallbackLogRepo,
+	shardingStrategy sharding.Strategy,
+	interval time.Duration,
+	retryInterval time.Duration,
+	batchSize int,
+	logger *zap.Logger,
+) *OutboxRelay {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &OutboxRelay{
+		outboxRepo:       outboxRepo,
+		logRepo:          logRepo,
+		shardingStrategy: shardingStrategy,
+		interval:         interval,
+		retryInterval:    retryInterval,
+		batchSize:        batchSize,
+		ctx:              ctx,
+		cancel:           cancel,
+		logger:           logger,
+	}
+}
@@ -0,0 +1,95 @@
+package callback
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/JrMarcco/kuryr/internal/domain"
+	"github.com/JrMarcco/kuryr/internal/pkg/sharding"
+	"github.com/JrMarcco/kuryr/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type stubOutboxRepo struct {
+	pending []domain.CallbackOutbox
+
+	deleted []string
+	delayed []string
+}
+
+func (r *stubOutboxRepo) FindPending(_ context.Context, _ int64, limit int) ([]domain.CallbackOutbox, error) {
+	n := min(limit, len(r.pending))
+	res := r.pending[:n]
+	r.pending = r.pending[n:]
+	return res, nil
+}
+
+func (r *stubOutboxRepo) Delete(_ context.Context, outboxes []domain.CallbackOutbox) error {
+	for _, outbox := range outboxes {
+		r.deleted = append(r.deleted, outbox.NotificationId)
+	}
+	return nil
+}
+
+func (r *stubOutboxRepo) Delay(_ context.Context, notificationIds []string, _ int64) error {
+	r.delayed = append(r.delayed, notificationIds...)
+	return nil
+}
+
+// stubRelayLogRepo 只实现 BatchUpsert，写入 failTable 分表时返回错误。
+type stubRelayLogRepo struct {
+	repository.CallbackLogRepo
+
+	failTable string
+	saved     map[string][]domain.CallbackLog
+}
+
+func (r *stubRelayLogRepo) BatchUpsert(_ context.Context, dst sharding.Dst, logs []domain.CallbackLog) error {
+	if dst.FullTable() == r.failTable {
+		return errors.New("mock db error")
+	}
+	r.saved[dst.FullTable()] = append(r.saved[dst.FullTable()], logs...)
+	return nil
+}
+
+func TestOutboxRelay_Relay(t *testing.T) {
+	t.Parallel()
+
+	strategy := sharding.NewHashSharding("kuryr", "callback_log", 2, 2)
+
+	outboxes := []domain.CallbackOutbox{
+		{NotificationId: "n1", BizId: 1, BizKey: "a", SendStatus: domain.SendStatusSuccess},
+		{NotificationId: "n2", BizId: 2, BizKey: "b", SendStatus: domain.SendStatusFailure},
+		{NotificationId: "n3", BizId: 3, BizKey: "c", SendStatus: domain.SendStatusSuccess},
+	}
+	failTable := strategy.Shard(2, "b").FullTable()
+
+	outboxRepo := &stubOutboxRepo{pending: slices.Clone(outboxes)}
+	logRepo := &stubRelayLogRepo{failTable: failTable, saved: make(map[string][]domain.CallbackLog)}
+
+	relay := NewOutboxRelay(outboxRepo, logRepo, strategy, time.Second, time.Second, 2, zap.NewNop())
+	relay.relay(context.Background())
+
+	var wantDeleted, wantDelayed []string
+	for _, outbox := range outboxes {
+		if strategy.Shard(outbox.BizId, outbox.BizKey).FullTable() == failTable {
+			wantDelayed = append(wantDelayed, outbox.NotificationId)
+			continue
+		}
+		wantDeleted = append(wantDeleted, outbox.NotificationId)
+	}
+	assert.ElementsMatch(t, wantDeleted, outboxRepo.deleted)
+	assert.ElementsMatch(t, wantDelayed, outboxRepo.delayed)
+
+	// 回调日志写入消息所在的分表，初始状态为 prepare。
+	for fullTable, logs := range logRepo.saved {
+		for _, log := range logs {
+			assert.Equal(t, fullTable, strategy.Shard(log.BizId, log.BizKey).FullTable())
+			assert.Equal(t, domain.CallbackLogStatusPrepare, log.Status)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package callback
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/JrMarcco/kuryr/internal/pkg/batch"
+	"github.com/JrMarcco/kuryr/internal/pkg/election"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/zap"
+)
+
+// Scheduler 回调定时任务。
+//
+// 定时扫描所有回调日志分表并发送回调请求。
+// 多副本部署时通过 etcd 选主，只有 leader 执行扫描，避免重复回调。
+// 每次扫描的批大小由 batch.Adjuster 根据扫描耗时动态调整。
+type Scheduler struct {
+	svc      Service
+	adjuster batch.Adjuster
+
+	elector *election.Elector
+
+	interval  time.Duration
+	batchSize int
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	logger *zap.Logger
+}
+
+func (s *Scheduler) Start() error {
+	s.wg.Add(1)
+	go s.run()
+	return nil
+}
+
+func (s *Scheduler) Stop() error {
+	s.cancel()
+	s.wg.Wait()
+	return nil
+}
+
+// run 参与选主，成为 leader 后开始执行定时任务。
+func (s *Scheduler) run() {
+	defer s.wg.Done()
+	s.elector.Run(s.ctx, s.lead)
+}
+
+// lead 作为 leader 定时执行回调任务，直到任务停止或者 session 失效。
+func (s *Scheduler) lead(ctx context.Context, sessionDone <-chan struct{}) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sessionDone:
+			s.logger.Warn("[kuryr] callback scheduler lost leadership, etcd session expired")
+			return
+		case <-ticker.C:
+			s.tick(ctx)
+		}
+	}
+}
+
+// tick 执行一次回调任务，并根据耗时调整下一次的批大小。
+func (s *Scheduler) tick(ctx context.Context) {
+	start := time.Now()
+
+	if err := s.svc.Send(ctx, start.UnixMilli(), s.batchSize); err != nil {
+		s.logger.Warn("[kuryr] callback scheduler failed to send callbacks", zap.Int("batch_size", s.batchSize), zap.Error(err))
+	}
+
+	batchSize, err := s.adjuster.Adjust(ctx, time.Since(start))
+	if err != nil {
+		s.logger.Warn("[kuryr] callback scheduler failed to adjust batch size", zap.Error(err))
+		return
+	}
+	s.batchSize = batchSize
+}
+
+func NewScheduler(
+	svc Service,
+	adjuster batch.Adjuster,
+	etcdClient *clientv3.Client,
+	electionKey string,
+	candidate string,
+	sessionTTL int,
+	interval time.Duration,
+	batchSize int,
+	logger *zap.Logger,
+) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		svc:       svc,
+		adjuster:  adjuster,
+		elector:   election.NewElector(etcdClient, electionKey, candidate, sessionTTL, interval, logger),
+		interval:  interval,
+		batchSize: batchSize,
+		ctx:       ctx,
+		cancel:    cancel,
+		logger:    logger,
+	}
+}
@@ -0,0 +1,58 @@
+package callback
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	batchmock "github.com/JrMarcco/kuryr/internal/pkg/batch/mock"
+	callbackmock "github.com/JrMarcco/kuryr/internal/service/callback/mock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestScheduler_tick(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name          string
+		sendErr       error
+		adjustErr     error
+		wantBatchSize int
+	}{
+		{
+			name:          "adjust batch size",
+			wantBatchSize: 200,
+		}, {
+			name:          "send failed still adjust",
+			sendErr:       errors.New("mock send error"),
+			wantBatchSize: 200,
+		}, {
+			name:          "adjust failed keep batch size",
+			adjustErr:     errors.New("mock adjust error"),
+			wantBatchSize: 100,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			svc := callbackmock.NewMockService(ctrl)
+			svc.EXPECT().Send(gomock.Any(), gomock.Any(), 100).Return(tc.sendErr)
+
+			adjuster := batchmock.NewMockAdjuster(ctrl)
+			adjuster.EXPECT().Adjust(gomock.Any(), gomock.Any()).Return(200, tc.adjustErr)
+
+			s := NewScheduler(svc, adjuster, nil, "", "", 10, time.Second, 100, zap.NewNop())
+			s.tick(context.Background())
+
+			assert.Equal(t, tc.wantBatchSize, s.batchSize)
+		})
+	}
+}
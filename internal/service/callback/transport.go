@@ -0,0 +1,48 @@
+package callback
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/JrMarcco/easy-grpc/client"
+	clientv1 "github.com/JrMarcco/kuryr-api/api/go/client/v1"
+	"github.com/JrMarcco/kuryr/internal/domain"
+)
+
+// Transport 回调请求的发送方式。
+type Transport interface {
+	// Notify 发送回调请求，返回业务方是否处理成功。
+	Notify(ctx context.Context, cfg *domain.CallbackConfig, req *clientv1.SendResultNotifyRequest) (bool, error)
+}
+
+var _ Transport = (*GrpcTransport)(nil)
+
+// GrpcTransport 通过注册中心调用业务方 grpc 服务发送回调。
+type GrpcTransport struct {
+	grpcClients *client.Manager[clientv1.CallbackServiceClient]
+	timeout     time.Duration
+}
+
+func (t *GrpcTransport) Notify(ctx context.Context, cfg *domain.CallbackConfig, req *clientv1.SendResultNotifyRequest) (bool, error) {
+	grpcClient, err := t.grpcClients.Get(cfg.ServiceName)
+	if err != nil {
+		return false, fmt.Errorf("[kuryr] failed to get grpc client: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	resp, err := grpcClient.SendResultNotify(ctx, req)
+	if err != nil {
+		return false, err
+	}
+	return resp.Success, nil
+}
+
+func NewGrpcTransport(grpcClients *client.Manager[clientv1.CallbackServiceClient], timeout time.Duration) *GrpcTransport {
+	return &GrpcTransport{
+		grpcClients: grpcClients,
+		timeout:     timeout,
+	}
+}
@@ -6,8 +6,18 @@ import (
 	"github.com/JrMarcco/kuryr/internal/domain"
 )
 
+//go:generate mockgen -source=./types.go -destination=./mock/callback_service.mock.go -package=callbackmock -typed Service
+
+// Service 回调服务，负责把消息发送结果回调给业务方。
 type Service interface {
 	Send(ctx context.Context, startTime int64, batchSize int) error
 	SendByNotification(ctx context.Context, n domain.Notification) error
 	SendByNotifications(ctx context.Context, ns []domain.Notification) error
+
+	// ListFailures 分页查询业务方重试耗尽的回调日志。
+	ListFailures(ctx context.Context, bizId uint64, startTime, endTime int64, cursor uint64, limit int) ([]domain.CallbackLog, error)
+	// Replay 重放指定的失败回调日志，返回实际重放的数量。
+	Replay(ctx context.Context, bizId uint64, ids []uint64, operatorId uint64) (int64, error)
+	// ReplayByTime 重放时间范围内的全部失败回调日志，返回实际重放的数量。
+	ReplayByTime(ctx context.Context, bizId uint64, startTime, endTime int64, operatorId uint64) (int64, error)
 }
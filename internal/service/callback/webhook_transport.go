@@ -0,0 +1,85 @@
+package callback
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	clientv1 "github.com/JrMarcco/kuryr-api/api/go/client/v1"
+	"github.com/JrMarcco/kuryr/internal/domain"
+	"github.com/JrMarcco/kuryr/internal/errs"
+	"github.com/JrMarcco/kuryr/internal/pkg/signature"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const defaultWebhookTimeout = 3 * time.Second
+
+var _ Transport = (*WebhookTransport)(nil)
+
+// WebhookTransport 通过 http 请求调用业务方 webhook 发送回调。
+//
+// 请求体为 json 格式的 SendResultNotifyRequest，请求头携带时间戳与签名，见 signature.Sign。
+//
+// 只有 2xx 响应视为回调成功。
+type WebhookTransport struct {
+	httpClient *http.Client
+	nowFunc    func() time.Time
+}
+
+func (t *WebhookTransport) Notify(ctx context.Context, cfg *domain.CallbackConfig, req *clientv1.SendResultNotifyRequest) (bool, error) {
+	webhook := cfg.Webhook
+	if webhook == nil || webhook.Url == "" {
+		return false, fmt.Errorf("%w: no webhook config provided by the business side", errs.ErrRecordNotFound)
+	}
+
+	body, err := protojson.Marshal(req)
+	if err != nil {
+		return false, fmt.Errorf("[kuryr] failed to marshal callback request: %w", err)
+	}
+
+	timeout := defaultWebhookTimeout
+	if webhook.Timeout > 0 {
+		timeout = time.Duration(webhook.Timeout) * time.Millisecond
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	method := webhook.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, method, webhook.Url, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("[kuryr] failed to build webhook request: %w", err)
+	}
+
+	ts := strconv.FormatInt(t.nowFunc().UnixMilli(), 10)
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set(signature.HeaderTimestamp, ts)
+	httpReq.Header.Set(signature.HeaderSignature, hex.EncodeToString(signature.Sign([]byte(webhook.Secret), ts, body)))
+
+	resp, err := t.httpClient.Do(httpReq)
+	if err != nil {
+		return false, fmt.Errorf("[kuryr] failed to send webhook request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	// 读完响应体以便复用连接。
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	return resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices, nil
+}
+
+func NewWebhookTransport(httpClient *http.Client) *WebhookTransport {
+	return &WebhookTransport{
+		httpClient: httpClient,
+		nowFunc:    time.Now,
+	}
+}
@@ -0,0 +1,100 @@
+package callback
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	clientv1 "github.com/JrMarcco/kuryr-api/api/go/client/v1"
+	notificationv1 "github.com/JrMarcco/kuryr-api/api/go/notification/v1"
+	"github.com/JrMarcco/kuryr/internal/domain"
+	"github.com/JrMarcco/kuryr/internal/pkg/signature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func TestWebhookTransport_Notify(t *testing.T) {
+	t.Parallel()
+
+	const secret = "webhook_secret"
+
+	tcs := []struct {
+		name        string
+		method      string
+		statusCode  int
+		wantMethod  string
+		wantSuccess bool
+	}{
+		{
+			name:        "default method",
+			statusCode:  http.StatusOK,
+			wantMethod:  http.MethodPost,
+			wantSuccess: true,
+		}, {
+			name:        "custom method",
+			method:      http.MethodPut,
+			statusCode:  http.StatusNoContent,
+			wantMethod:  http.MethodPut,
+			wantSuccess: true,
+		}, {
+			name:        "not modified",
+			statusCode:  http.StatusNotModified,
+			wantMethod:  http.MethodPost,
+			wantSuccess: false,
+		}, {
+			name:        "server error",
+			statusCode:  http.StatusInternalServerError,
+			wantMethod:  http.MethodPost,
+			wantSuccess: false,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, tc.wantMethod, r.Method)
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+				body, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+
+				sig, err := hex.DecodeString(r.Header.Get(signature.HeaderSignature))
+				assert.NoError(t, err)
+				assert.True(t, hmac.Equal(sig, signature.Sign([]byte(secret), r.Header.Get(signature.HeaderTimestamp), body)))
+
+				req := &clientv1.SendResultNotifyRequest{}
+				assert.NoError(t, protojson.Unmarshal(body, req))
+				assert.Equal(t, "notification_id", req.GetResult().GetNotificationId())
+
+				w.WriteHeader(tc.statusCode)
+			}))
+			defer server.Close()
+
+			cfg := &domain.CallbackConfig{
+				Transport: domain.CallbackTransportWebhook,
+				Webhook: &domain.WebhookConfig{
+					Url:    server.URL,
+					Method: tc.method,
+					Secret: secret,
+				},
+			}
+			req := &clientv1.SendResultNotifyRequest{
+				Result: &notificationv1.SendResult{
+					NotificationId: "notification_id",
+					Status:         notificationv1.SendStatus_SUCCESS,
+				},
+			}
+
+			success, err := NewWebhookTransport(server.Client()).Notify(context.Background(), cfg, req)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantSuccess, success)
+		})
+	}
+}
@@ -2,8 +2,16 @@ package notification
 
 import (
 	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strconv"
 
 	"github.com/JrMarcco/kuryr/internal/domain"
+	"github.com/JrMarcco/kuryr/internal/errs"
+	"github.com/JrMarcco/kuryr/internal/pkg/mq"
+	"github.com/JrMarcco/kuryr/internal/service/sendstrategy"
+	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
 // SendService 消息发送服务接口，负责处理消息发送前的准备工作。
@@ -17,5 +25,187 @@ type SendService interface {
 	AsyncSend(ctx context.Context, n domain.Notification) (domain.Notification, error)
 
 	BatchSend(ctx context.Context, ns []domain.Notification) (domain.BatchSendResp, error)
-	BatchAsyncSend(ctx context.Context, ns []domain.Notification) (domain.BatchSendResp, error)
+	BatchAsyncSend(ctx context.Context, ns []domain.Notification) (domain.BatchAsyncSendResp, error)
+}
+
+// LagChecker 异步发送消息的消费积压检查，*mq.LagMonitor 实现了该接口。
+type LagChecker interface {
+	Lagging() bool
+}
+
+// TplValidator 异步发送消息的模板参数校验，*sendstrategy.TplValidateStrategy 实现了该接口。
+type TplValidator interface {
+	Validate(ctx context.Context, ns []domain.Notification) ([]domain.Notification, error)
+}
+
+var _ SendService = (*DefaultSendService)(nil)
+
+// DefaultSendService 消息发送服务默认实现。
+//
+// 同步发送直接交给发送策略处理。
+// 异步发送在请求链路中只做校验 ( 包括模板参数 ) 并按 biz_id + biz_key 分配消息 id，按业务 id 分批写入 kafka 后立即返回，
+// 由消费者 ( HandleAsyncSendEvent ) 经发送策略入库并等待调度发送。
+// 部分批次写入失败后客户端重试时消息 id 不变，已经写入的消息不会重复入库。
+// 消费积压超过阈值时拒绝新的异步发送请求。
+type DefaultSendService struct {
+	strategy     sendstrategy.SendStrategy
+	tplValidator TplValidator
+	producer     mq.Producer[domain.AsyncSendEvent]
+	lagChecker   LagChecker
+
+	batchSize int // 单条 kafka 消息包含的最大通知数
+}
+
+func (s *DefaultSendService) Send(ctx context.Context, n domain.Notification) (domain.SendResp, error) {
+	if err := n.Validate(); err != nil {
+		return domain.SendResp{}, err
+	}
+	return s.strategy.Send(ctx, n)
+}
+
+func (s *DefaultSendService) AsyncSend(ctx context.Context, n domain.Notification) (domain.Notification, error) {
+	ns, err := s.publish(ctx, []domain.Notification{n})
+	if err != nil {
+		return domain.Notification{}, err
+	}
+	return ns[0], nil
+}
+
+func (s *DefaultSendService) BatchSend(ctx context.Context, ns []domain.Notification) (domain.BatchSendResp, error) {
+	if len(ns) == 0 {
+		return domain.BatchSendResp{}, fmt.Errorf("%w: notifications cannot be empty", errs.ErrInvalidParam)
+	}
+	for i := range ns {
+		if err := ns[i].Validate(); err != nil {
+			return domain.BatchSendResp{}, err
+		}
+	}
+	return s.strategy.BatchSend(ctx, ns)
+}
+
+func (s *DefaultSendService) BatchAsyncSend(ctx context.Context, ns []domain.Notification) (domain.BatchAsyncSendResp, error) {
+	if len(ns) == 0 {
+		return domain.BatchAsyncSendResp{}, fmt.Errorf("%w: notifications cannot be empty", errs.ErrInvalidParam)
+	}
+
+	published, err := s.publish(ctx, ns)
+	if err != nil {
+		return domain.BatchAsyncSendResp{}, err
+	}
+
+	ids := make([]string, 0, len(published))
+	for _, n := range published {
+		ids = append(ids, n.Id)
+	}
+	return domain.BatchAsyncSendResp{NotificationIds: ids}, nil
+}
+
+// publish 校验消息并分配 id，按业务 id 分批写入 kafka，等待全部投递成功后返回。
+// 部分批次投递失败时返回错误，已投递的批次仍会被消费入库，客户端重试时按消息 id 幂等。
+func (s *DefaultSendService) publish(ctx context.Context, ns []domain.Notification) ([]domain.Notification, error) {
+	if s.lagChecker.Lagging() {
+		return nil, fmt.Errorf("%w: too many pending async notifications", errs.ErrSystemBusy)
+	}
+
+	ids := make(map[string]struct{}, len(ns))
+	prepared := make([]domain.Notification, 0, len(ns))
+	for _, n := range ns {
+		if err := n.Validate(); err != nil {
+			return nil, err
+		}
+
+		n.Id = asyncNotificationId(n.BizId, n.BizKey)
+		if _, ok := ids[n.Id]; ok {
+			return nil, fmt.Errorf("%w: duplicate biz key [ %s ]", errs.ErrInvalidParam, n.BizKey)
+		}
+		ids[n.Id] = struct{}{}
+
+		// 异步发送不支持立即发送，发送时间窗口以请求时间为准。
+		n.ReplaceAsyncImmediate()
+		n.SetSendTime()
+		n.SendStatus = domain.SendStatusPending
+		prepared = append(prepared, n)
+	}
+
+	prepared, err := s.tplValidator.Validate(ctx, prepared)
+	if err != nil {
+		return nil, err
+	}
+
+	var bizIds []uint64
+	groups := make(map[uint64][]domain.Notification)
+	for _, n := range prepared {
+		if _, ok := groups[n.BizId]; !ok {
+			bizIds = append(bizIds, n.BizId)
+		}
+		groups[n.BizId] = append(groups[n.BizId], n)
+	}
+
+	futures := make([]*mq.DeliveryFuture, 0, len(prepared)/s.batchSize+len(bizIds))
+	for _, bizId := range bizIds {
+		group := groups[bizId]
+		for start := 0; start < len(group); start += s.batchSize {
+			end := min(start+s.batchSize, len(group))
+			future, err := s.producer.ProduceAsync(ctx, domain.AsyncSendEvent{
+				BizId:         bizId,
+				Notifications: group[start:end],
+			})
+			if err != nil {
+				return nil, fmt.Errorf("[kuryr] failed to publish async send event: %w", err)
+			}
+			futures = append(futures, future)
+		}
+	}
+
+	for _, future := range futures {
+		if _, err := future.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("[kuryr] failed to deliver async send event: %w", err)
+		}
+	}
+	return prepared, nil
+}
+
+// HandleAsyncSendEvent 消费异步发送事件，经发送策略入库。
+// 消息 id 已经提前分配，重复消费时不会重复入库。参数错误无法通过重试恢复，直接投递到死信 topic。
+func (s *DefaultSendService) HandleAsyncSendEvent(ctx context.Context, event domain.AsyncSendEvent) error {
+	if len(event.Notifications) == 0 {
+		return nil
+	}
+
+	_, err := s.strategy.BatchSend(ctx, event.Notifications)
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, errs.ErrInvalidParam) ||
+		errors.Is(err, errs.ErrInvalidChannel) ||
+		errors.Is(err, errs.ErrNoActivatedTplVersion) {
+		return fmt.Errorf("%w: %w", mq.ErrNonRetryable, err)
+	}
+	return err
+}
+
+// asyncNotificationId 按 biz_id + biz_key 生成确定的异步发送消息 id。
+func asyncNotificationId(bizId uint64, bizKey string) string {
+	sum := sha256.Sum256([]byte(strconv.FormatUint(bizId, 10) + ":" + bizKey))
+
+	var id bson.ObjectID
+	copy(id[:], sum[:])
+	return id.Hex()
+}
+
+func NewDefaultSendService(
+	strategy sendstrategy.SendStrategy,
+	tplValidator TplValidator,
+	producer mq.Producer[domain.AsyncSendEvent],
+	lagChecker LagChecker,
+	batchSize int,
+) *DefaultSendService {
+	return &DefaultSendService{
+		strategy:     strategy,
+		tplValidator: tplValidator,
+		producer:     producer,
+		lagChecker:   lagChecker,
+		batchSize:    max(batchSize, 1),
+	}
 }
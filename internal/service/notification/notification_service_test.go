@@ -0,0 +1,206 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/JrMarcco/kuryr/internal/domain"
+	"github.com/JrMarcco/kuryr/internal/errs"
+	"github.com/JrMarcco/kuryr/internal/pkg/mq"
+	mqmock "github.com/JrMarcco/kuryr/internal/pkg/mq/mock"
+	sendstrategymock "github.com/JrMarcco/kuryr/internal/service/sendstrategy/mock"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+type stubLagChecker bool
+
+func (s stubLagChecker) Lagging() bool {
+	return bool(s)
+}
+
+// stubTplValidator 模板参数校验失败时返回 err，否则原样返回消息。
+type stubTplValidator struct {
+	err error
+}
+
+func (s stubTplValidator) Validate(_ context.Context, ns []domain.Notification) ([]domain.Notification, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return ns, nil
+}
+
+func validNotification(bizId uint64, bizKey string) domain.Notification {
+	return domain.Notification{
+		BizId:          bizId,
+		BizKey:         bizKey,
+		Receivers:      []string{"13800000000"},
+		Channel:        domain.ChannelSms,
+		Template:       domain.Template{Id: 1, Version: 1},
+		StrategyConfig: domain.SendStrategyConfig{StrategyType: domain.SendStrategyImmediate},
+	}
+}
+
+func TestDefaultSendService_BatchAsyncSend(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name       string
+		ns         []domain.Notification
+		lagging    bool
+		tplErr     error
+		wantEvents int
+		wantErr    error
+	}{
+		{
+			name: "publish by biz id",
+			ns: []domain.Notification{
+				validNotification(1, "a"), validNotification(2, "a"), validNotification(1, "b"), validNotification(1, "c"),
+			},
+			wantEvents: 3, // biz 1: 2 + 1, biz 2: 1
+		}, {
+			name:    "reject when consumer lagging",
+			ns:      []domain.Notification{validNotification(1, "a")},
+			lagging: true,
+			wantErr: errs.ErrSystemBusy,
+		}, {
+			name:    "invalid notification",
+			ns:      []domain.Notification{validNotification(1, "a"), {BizId: 1}},
+			wantErr: errs.ErrInvalidParam,
+		}, {
+			name:    "duplicate biz key",
+			ns:      []domain.Notification{validNotification(1, "a"), validNotification(1, "a")},
+			wantErr: errs.ErrInvalidParam,
+		}, {
+			name:    "invalid template params",
+			ns:      []domain.Notification{validNotification(1, "a")},
+			tplErr:  fmt.Errorf("%w: mock invalid template params", errs.ErrInvalidParam),
+			wantErr: errs.ErrInvalidParam,
+		}, {
+			name:    "empty notifications",
+			wantErr: errs.ErrInvalidParam,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			events := make(chan kafka.Event, len(tc.ns))
+			kp := mqmock.NewMockKafkaEventProducer(ctrl)
+			kp.EXPECT().Events().Return(events)
+			kp.EXPECT().Flush(gomock.Any()).Return(0)
+			kp.EXPECT().Close().Do(func() { close(events) })
+
+			var published []domain.AsyncSendEvent
+			kp.EXPECT().Produce(gomock.Any(), gomock.Nil()).
+				DoAndReturn(func(msg *kafka.Message, _ chan kafka.Event) error {
+					var event domain.AsyncSendEvent
+					require.NoError(t, json.Unmarshal(msg.Value, &event))
+					assert.Equal(t, fmt.Sprintf("%d", event.BizId), string(msg.Key))
+					published = append(published, event)
+
+					events <- msg
+					return nil
+				}).
+				Times(tc.wantEvents)
+
+			ap := mq.NewAsyncProducer(kp, zap.NewNop())
+			require.NoError(t, ap.Start())
+			defer func() {
+				require.NoError(t, ap.Stop(context.Background()))
+			}()
+
+			svc := NewDefaultSendService(
+				sendstrategymock.NewMockSendStrategy(ctrl),
+				stubTplValidator{err: tc.tplErr},
+				mq.NewGeneralProducer("test_topic", ap, mq.WithKeyFunc(func(event domain.AsyncSendEvent) []byte {
+					return []byte(fmt.Sprintf("%d", event.BizId))
+				})),
+				stubLagChecker(tc.lagging),
+				2,
+			)
+
+			resp, err := svc.BatchAsyncSend(context.Background(), tc.ns)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, resp.NotificationIds, len(tc.ns))
+
+			var ids []string
+			for _, event := range published {
+				for _, n := range event.Notifications {
+					assert.Equal(t, event.BizId, n.BizId)
+					assert.Equal(t, domain.SendStatusPending, n.SendStatus)
+					// 异步发送不保留立即发送策略。
+					assert.Equal(t, domain.SendStrategyDeadline, n.StrategyConfig.StrategyType)
+					ids = append(ids, n.Id)
+				}
+			}
+			assert.ElementsMatch(t, resp.NotificationIds, ids)
+		})
+	}
+}
+
+func TestAsyncNotificationId(t *testing.T) {
+	t.Parallel()
+
+	// 客户端重试时消息 id 不变，入库时按 id 幂等。
+	assert.Equal(t, asyncNotificationId(1, "a"), asyncNotificationId(1, "a"))
+	assert.NotEqual(t, asyncNotificationId(1, "a"), asyncNotificationId(1, "b"))
+	assert.NotEqual(t, asyncNotificationId(1, "a"), asyncNotificationId(2, "a"))
+
+	_, err := bson.ObjectIDFromHex(asyncNotificationId(1, "a"))
+	assert.NoError(t, err)
+}
+
+func TestDefaultSendService_HandleAsyncSendEvent(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name            string
+		strategyErr     error
+		wantErr         bool
+		wantNonRetrying bool
+	}{
+		{name: "saved"},
+		{name: "retryable error", strategyErr: errors.New("mock db error"), wantErr: true},
+		{name: "invalid param", strategyErr: fmt.Errorf("%w: mock", errs.ErrInvalidParam), wantErr: true, wantNonRetrying: true},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			event := domain.AsyncSendEvent{BizId: 1, Notifications: []domain.Notification{validNotification(1, "a")}}
+
+			strategy := sendstrategymock.NewMockSendStrategy(ctrl)
+			strategy.EXPECT().BatchSend(gomock.Any(), event.Notifications).Return(domain.BatchSendResp{}, tc.strategyErr)
+
+			svc := NewDefaultSendService(strategy, nil, nil, stubLagChecker(false), 1)
+			err := svc.HandleAsyncSendEvent(context.Background(), event)
+			if !tc.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Error(t, err)
+			assert.Equal(t, tc.wantNonRetrying, errors.Is(err, mq.ErrNonRetryable))
+		})
+	}
+}
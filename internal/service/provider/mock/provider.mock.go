@@ -42,6 +42,44 @@ func (m *MockProvider) EXPECT() *MockProviderMockRecorder {
 	return m.recorder
 }
 
+// Id mocks base method.
+func (m *MockProvider) Id() uint64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Id")
+	ret0, _ := ret[0].(uint64)
+	return ret0
+}
+
+// Id indicates an expected call of Id.
+func (mr *MockProviderMockRecorder) Id() *MockProviderIdCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Id", reflect.TypeOf((*MockProvider)(nil).Id))
+	return &MockProviderIdCall{Call: call}
+}
+
+// MockProviderIdCall wrap *gomock.Call
+type MockProviderIdCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockProviderIdCall) Return(arg0 uint64) *MockProviderIdCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockProviderIdCall) Do(f func() uint64) *MockProviderIdCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockProviderIdCall) DoAndReturn(f func() uint64) *MockProviderIdCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // Send mocks base method.
 func (m *MockProvider) Send(ctx context.Context, n domain.Notification) (domain.SendResp, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,69 @@
+package selector
+
+import (
+	"context"
+
+	"github.com/JrMarcco/kuryr/internal/domain"
+	"github.com/JrMarcco/kuryr/internal/repository"
+	"github.com/JrMarcco/kuryr/internal/service/provider"
+)
+
+var _ provider.Selector = (*ApprovedSelector)(nil)
+
+// ApprovedSelector 供应商选择器装饰器。
+//
+// 跳过没有为消息模板 ( 激活版本中消息语言区域对应的变体 ) 关联、或者供应商侧审批未通过的供应商，
+// 避免把模板发送到没有对应模板 id 的供应商。
+type ApprovedSelector struct {
+	next    provider.Selector
+	tplRepo repository.ChannelTplRepo
+}
+
+func (s *ApprovedSelector) Next(ctx context.Context, n domain.Notification) (provider.Provider, error) {
+	tpl, err := s.tplRepo.FindActivatedById(ctx, n.Template.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := tpl.GetActivatedVersion()
+	if err != nil {
+		return nil, err
+	}
+	locale := version.ResolveVariant(n.Locale).Locale
+
+	for {
+		p, err := s.next.Next(ctx, n)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := version.FindApprovedProvider(p.Id(), locale); ok {
+			return p, nil
+		}
+	}
+}
+
+var _ provider.SelectorBuilder = (*ApprovedSelectorBuilder)(nil)
+
+type ApprovedSelectorBuilder struct {
+	next    provider.SelectorBuilder
+	tplRepo repository.ChannelTplRepo
+}
+
+func (b *ApprovedSelectorBuilder) Build() (provider.Selector, error) {
+	next, err := b.next.Build()
+	if err != nil {
+		return nil, err
+	}
+	return &ApprovedSelector{
+		next:    next,
+		tplRepo: b.tplRepo,
+	}, nil
+}
+
+func NewApprovedSelectorBuilder(next provider.SelectorBuilder, tplRepo repository.ChannelTplRepo) *ApprovedSelectorBuilder {
+	return &ApprovedSelectorBuilder{
+		next:    next,
+		tplRepo: tplRepo,
+	}
+}
@@ -0,0 +1,87 @@
+package selector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/JrMarcco/kuryr/internal/domain"
+	"github.com/JrMarcco/kuryr/internal/errs"
+	"github.com/JrMarcco/kuryr/internal/repository"
+	"github.com/JrMarcco/kuryr/internal/service/provider"
+	providermock "github.com/JrMarcco/kuryr/internal/service/provider/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// stubTplRepo 只实现 FindActivatedById。
+type stubTplRepo struct {
+	repository.ChannelTplRepo
+	tpl domain.ChannelTemplate
+}
+
+func (r *stubTplRepo) FindActivatedById(_ context.Context, _ uint64) (domain.ChannelTemplate, error) {
+	return r.tpl, nil
+}
+
+func TestApprovedSelector_Next(t *testing.T) {
+	t.Parallel()
+
+	tpl := domain.ChannelTemplate{
+		Id:                 1,
+		ActivatedVersionId: 10,
+		Versions: []domain.ChannelTemplateVersion{{
+			Id:          10,
+			AuditStatus: domain.AuditStatusApproved,
+			Variants:    []domain.TplVariant{{Locale: "en", Signature: "kuryr", Content: "hello"}},
+			Providers: []domain.ChannelTemplateProvider{
+				{ProviderId: 1, AuditStatus: domain.AuditStatusAuditing},
+				{ProviderId: 2, AuditStatus: domain.AuditStatusApproved},
+				{ProviderId: 3, Locale: "en", AuditStatus: domain.AuditStatusApproved},
+			},
+		}},
+	}
+
+	tcs := []struct {
+		name    string
+		locale  string
+		wantIds []uint64
+	}{
+		{name: "default variant", locale: "", wantIds: []uint64{2}},
+		{name: "en variant", locale: "en-US", wantIds: []uint64{3}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			providers := make([]provider.Provider, 0, 3)
+			for _, id := range []uint64{1, 2, 3} {
+				p := providermock.NewMockProvider(ctrl)
+				p.EXPECT().Id().Return(id).AnyTimes()
+				providers = append(providers, p)
+			}
+
+			selector, err := NewApprovedSelectorBuilder(
+				NewSeqSelectorBuilder(providers), &stubTplRepo{tpl: tpl},
+			).Build()
+			require.NoError(t, err)
+
+			n := domain.Notification{Template: domain.Template{Id: tpl.Id}, Locale: tc.locale}
+
+			var ids []uint64
+			for {
+				p, err := selector.Next(t.Context(), n)
+				if err != nil {
+					assert.ErrorIs(t, err, errs.ErrRecordNotFound)
+					break
+				}
+				ids = append(ids, p.Id())
+			}
+			assert.Equal(t, tc.wantIds, ids)
+		})
+	}
+}
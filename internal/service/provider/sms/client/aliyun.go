@@ -1,4 +1,258 @@
 package client
 
-// AliyunSmsClient 阿里云短信客户端
-type AliyunSmsClient struct{}
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/JrMarcco/kuryr/internal/domain"
+	"github.com/JrMarcco/kuryr/internal/errs"
+)
+
+const (
+	aliyunDefaultEndpoint = "https://dysmsapi.aliyuncs.com"
+	aliyunApiVersion      = "2017-05-25"
+	aliyunCodeOk          = "OK"
+)
+
+// aliyunAuditStatusMap 阿里云模板审核状态：0 审核中，1 审核通过，2 审核失败，10 取消审核。
+var aliyunAuditStatusMap = map[int]domain.AuditStatus{
+	0:  domain.AuditStatusAuditing,
+	1:  domain.AuditStatusApproved,
+	2:  domain.AuditStatusRejected,
+	10: domain.AuditStatusRejected,
+}
+
+var _ SmsClient = (*AliyunSmsClient)(nil)
+
+// AliyunSmsClient 阿里云短信客户端实现。
+//
+// 直接调用阿里云 RPC 风格接口 ( 签名算法 v1 )，不依赖 SDK。
+//
+// https://help.aliyun.com/zh/sdk/product-overview/rpc-mechanism
+type AliyunSmsClient struct {
+	endpoint        string
+	regionId        string
+	accessKeyId     string
+	accessKeySecret string
+
+	httpClient *http.Client
+	nowFunc    func() time.Time
+	nonceFunc  func() string
+}
+
+type aliyunResp struct {
+	RequestId string `json:"RequestId"`
+	Code      string `json:"Code"`
+	Message   string `json:"Message"`
+}
+
+// Send 调用阿里云短信发送接口。
+//
+// 阿里云模板使用命名占位符 ( ${name} )，参数以 json 对象传入，不依赖参数顺序。
+//
+// https://help.aliyun.com/zh/sms/developer-reference/api-dysmsapi-2017-05-25-sendsms
+func (ac *AliyunSmsClient) Send(req SendReq) (SendResp, error) {
+	if len(req.PhoneNumbers) == 0 {
+		return SendResp{}, fmt.Errorf("%w: phone number should not be empty", errs.ErrInvalidParam)
+	}
+
+	params := map[string]string{
+		"PhoneNumbers":  strings.Join(req.PhoneNumbers, ","),
+		"SignName":      req.SignName,
+		"TemplateCode":  req.TemplateId,
+		"TemplateParam": "{}",
+	}
+	if len(req.TemplateParams) > 0 {
+		tplParams, err := json.Marshal(req.TemplateParams)
+		if err != nil {
+			return SendResp{}, fmt.Errorf("%w: %w", errs.ErrInvalidParam, err)
+		}
+		params["TemplateParam"] = string(tplParams)
+	}
+
+	var resp struct {
+		aliyunResp
+		BizId string `json:"BizId"`
+	}
+	if err := ac.call("SendSms", params, &resp); err != nil {
+		return SendResp{}, fmt.Errorf("%w: %w", ErrFailedToSendSms, err)
+	}
+
+	// 阿里云一次请求只返回一个结果，所有手机号共用。
+	sendResp := SendResp{
+		RequestId: resp.RequestId,
+		Results:   make(map[string]SendResult, len(req.PhoneNumbers)),
+	}
+	for _, phoneNumber := range req.PhoneNumbers {
+		sendResp.Results[phoneNumber] = SendResult{
+			Code:    resp.Code,
+			Message: resp.Message,
+		}
+	}
+	return sendResp, nil
+}
+
+// CreateTemplate 调用阿里云短信申请模板接口。
+//
+// https://help.aliyun.com/zh/sms/developer-reference/api-dysmsapi-2017-05-25-addsmstemplate
+func (ac *AliyunSmsClient) CreateTemplate(req CreateTplReq) (CreateTplResp, error) {
+	var resp struct {
+		aliyunResp
+		TemplateCode string `json:"TemplateCode"`
+	}
+	err := ac.call("AddSmsTemplate", map[string]string{
+		"TemplateType":    fmt.Sprintf("%d", req.TplType),
+		"TemplateName":    req.TplName,
+		"TemplateContent": req.TplContent,
+		"Remark":          req.Remark,
+	}, &resp)
+	if err != nil {
+		return CreateTplResp{}, fmt.Errorf("%w: %w", ErrFailedToCreateTpl, err)
+	}
+	if resp.Code != aliyunCodeOk {
+		return CreateTplResp{}, fmt.Errorf("%w: code = %s, message = %s", ErrFailedToCreateTpl, resp.Code, resp.Message)
+	}
+
+	return CreateTplResp{
+		RequestId:  resp.RequestId,
+		TemplateId: resp.TemplateCode,
+	}, nil
+}
+
+// QueryTemplateStatus 调用阿里云短信查询模板审核状态接口。
+// 阿里云接口一次只能查询一个模板，这里逐个查询。
+//
+// https://help.aliyun.com/zh/sms/developer-reference/api-dysmsapi-2017-05-25-querysmstemplate
+func (ac *AliyunSmsClient) QueryTemplateStatus(req QueryTplStatusReq) (QueryTplStatusResp, error) {
+	results := make(map[string]TplStatus, len(req.TemplateIds))
+	for _, templateId := range req.TemplateIds {
+		var resp struct {
+			aliyunResp
+			TemplateCode   string `json:"TemplateCode"`
+			TemplateStatus int    `json:"TemplateStatus"`
+			Reason         string `json:"Reason"`
+		}
+		if err := ac.call("QuerySmsTemplate", map[string]string{"TemplateCode": templateId}, &resp); err != nil {
+			return QueryTplStatusResp{}, fmt.Errorf("%w: %w", ErrFailedToQueryTplStatus, err)
+		}
+		if resp.Code != aliyunCodeOk {
+			return QueryTplStatusResp{}, fmt.Errorf("%w: code = %s, message = %s", ErrFailedToQueryTplStatus, resp.Code, resp.Message)
+		}
+
+		auditStatus, ok := aliyunAuditStatusMap[resp.TemplateStatus]
+		if !ok {
+			auditStatus = domain.AuditStatusAuditing
+		}
+		results[templateId] = TplStatus{
+			RequestId:   resp.RequestId,
+			TemplateId:  templateId,
+			AuditStatus: auditStatus,
+			Reason:      resp.Reason,
+		}
+	}
+
+	return QueryTplStatusResp{
+		Results: results,
+	}, nil
+}
+
+// call 发起 RPC 请求并解析响应。
+// 业务错误 ( Code 不为 OK ) 由调用方处理，这里只处理网络与协议错误。
+func (ac *AliyunSmsClient) call(action string, params map[string]string, resp any) error {
+	query := url.Values{}
+	for k, v := range params {
+		query.Set(k, v)
+	}
+	query.Set("Action", action)
+	query.Set("Version", aliyunApiVersion)
+	query.Set("Format", "JSON")
+	query.Set("RegionId", ac.regionId)
+	query.Set("AccessKeyId", ac.accessKeyId)
+	query.Set("SignatureMethod", "HMAC-SHA1")
+	query.Set("SignatureVersion", "1.0")
+	query.Set("SignatureNonce", ac.nonceFunc())
+	query.Set("Timestamp", ac.nowFunc().UTC().Format("2006-01-02T15:04:05Z"))
+	query.Set("Signature", aliyunSign(http.MethodGet, query, ac.accessKeySecret))
+
+	httpResp, err := ac.httpClient.Get(ac.endpoint + "/?" + query.Encode())
+	if err != nil {
+		return err
+	}
+	defer func() { _ = httpResp.Body.Close() }()
+
+	if err = json.NewDecoder(httpResp.Body).Decode(resp); err != nil {
+		return fmt.Errorf("failed to decode response, http status = %d: %w", httpResp.StatusCode, err)
+	}
+	return nil
+}
+
+// aliyunSign 计算 RPC 接口签名。
+//
+//	StringToSign = HTTPMethod + "&" + percentEncode("/") + "&" + percentEncode(CanonicalizedQueryString)
+//	Signature    = Base64(HMAC-SHA1(AccessKeySecret + "&", StringToSign))
+func aliyunSign(method string, query url.Values, accessKeySecret string) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		if k == "Signature" {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, aliyunPercentEncode(k)+"="+aliyunPercentEncode(query.Get(k)))
+	}
+
+	stringToSign := method + "&" + aliyunPercentEncode("/") + "&" + aliyunPercentEncode(strings.Join(pairs, "&"))
+
+	mac := hmac.New(sha1.New, []byte(accessKeySecret+"&"))
+	mac.Write([]byte(stringToSign))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// aliyunPercentEncode 按 RFC 3986 编码：空格编码为 %20，* 编码为 %2A，~ 不编码。
+func aliyunPercentEncode(s string) string {
+	encoded := url.QueryEscape(s)
+	encoded = strings.ReplaceAll(encoded, "+", "%20")
+	encoded = strings.ReplaceAll(encoded, "*", "%2A")
+	encoded = strings.ReplaceAll(encoded, "%7E", "~")
+	return encoded
+}
+
+func newAliyunNonce() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// NewAliyunSmsClient 创建阿里云短信客户端，endpoint 为空时使用默认接入地址。
+func NewAliyunSmsClient(endpoint, regionId, accessKeyId, accessKeySecret string) *AliyunSmsClient {
+	if endpoint == "" {
+		endpoint = aliyunDefaultEndpoint
+	}
+	if !strings.HasPrefix(endpoint, "http://") && !strings.HasPrefix(endpoint, "https://") {
+		endpoint = "https://" + endpoint
+	}
+
+	return &AliyunSmsClient{
+		endpoint:        strings.TrimSuffix(endpoint, "/"),
+		regionId:        regionId,
+		accessKeyId:     accessKeyId,
+		accessKeySecret: accessKeySecret,
+		httpClient:      &http.Client{Timeout: 5 * time.Second},
+		nowFunc:         time.Now,
+		nonceFunc:       newAliyunNonce,
+	}
+}
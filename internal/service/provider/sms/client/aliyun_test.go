@@ -0,0 +1,57 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAliyunSign(t *testing.T) {
+	t.Parallel()
+
+	// 阿里云文档中的签名示例。
+	query := url.Values{}
+	query.Set("AccessKeyId", "testid")
+	query.Set("Action", "DescribeRegions")
+	query.Set("Format", "XML")
+	query.Set("SignatureMethod", "HMAC-SHA1")
+	query.Set("SignatureNonce", "3ee8c1b8-83d3-44af-a94f-4e0ad82fd6cf")
+	query.Set("SignatureVersion", "1.0")
+	query.Set("Timestamp", "2016-02-23T12:46:24Z")
+	query.Set("Version", "2014-05-26")
+
+	assert.Equal(t, "OLeaidS1JvxuMvnyHOwuJ+uX5qY=", aliyunSign(http.MethodGet, query, "testsecret"))
+}
+
+func TestAliyunSmsClient_Send(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		assert.Equal(t, "SendSms", query.Get("Action"))
+		assert.Equal(t, "13800000000,13800000001", query.Get("PhoneNumbers"))
+		assert.Equal(t, "SMS_001", query.Get("TemplateCode"))
+		assert.JSONEq(t, `{"code":"1234"}`, query.Get("TemplateParam"))
+		assert.Equal(t, aliyunSign(http.MethodGet, query, "secret"), query.Get("Signature"))
+
+		_, _ = w.Write([]byte(`{"RequestId":"req-1","Code":"OK","Message":"OK","BizId":"900619746936498440^0"}`))
+	}))
+	defer server.Close()
+
+	resp, err := NewAliyunSmsClient(server.URL, "cn-hangzhou", "key", "secret").Send(SendReq{
+		PhoneNumbers:   []string{"13800000000", "13800000001"},
+		SignName:       "kuryr",
+		TemplateId:     "SMS_001",
+		TemplateParams: map[string]string{"code": "1234"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "req-1", resp.RequestId)
+	assert.Equal(t, map[string]SendResult{
+		"13800000000": {Code: "OK", Message: "OK"},
+		"13800000001": {Code: "OK", Message: "OK"},
+	}, resp.Results)
+}
@@ -0,0 +1,27 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/JrMarcco/kuryr/internal/domain"
+	"github.com/JrMarcco/kuryr/internal/errs"
+)
+
+const (
+	ProviderNameTencent = "tencent"
+	ProviderNameAliyun  = "aliyun"
+)
+
+// NewSmsClient 根据供应商信息创建对应的短信客户端。
+// 供应商名称 ( ProviderName ) 用于区分供应商平台。
+func NewSmsClient(provider domain.Provider) (SmsClient, error) {
+	switch strings.ToLower(provider.ProviderName) {
+	case ProviderNameTencent:
+		return NewTencentSmsClient(provider.RegionId, provider.ApiKey, provider.ApiSecret, provider.AppId), nil
+	case ProviderNameAliyun:
+		return NewAliyunSmsClient(provider.Endpoint, provider.RegionId, provider.ApiKey, provider.ApiSecret), nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported sms provider [ %s ]", errs.ErrInvalidParam, provider.ProviderName)
+	}
+}
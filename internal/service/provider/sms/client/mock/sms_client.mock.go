@@ -0,0 +1,158 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./types.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./types.go -destination=./mock/sms_client.mock.go -package=clientmock -typed SmsClient
+//
+
+// Package clientmock is a generated GoMock package.
+package clientmock
+
+import (
+	reflect "reflect"
+
+	client "github.com/JrMarcco/kuryr/internal/service/provider/sms/client"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSmsClient is a mock of SmsClient interface.
+type MockSmsClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockSmsClientMockRecorder
+	isgomock struct{}
+}
+
+// MockSmsClientMockRecorder is the mock recorder for MockSmsClient.
+type MockSmsClientMockRecorder struct {
+	mock *MockSmsClient
+}
+
+// NewMockSmsClient creates a new mock instance.
+func NewMockSmsClient(ctrl *gomock.Controller) *MockSmsClient {
+	mock := &MockSmsClient{ctrl: ctrl}
+	mock.recorder = &MockSmsClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSmsClient) EXPECT() *MockSmsClientMockRecorder {
+	return m.recorder
+}
+
+// CreateTemplate mocks base method.
+func (m *MockSmsClient) CreateTemplate(req client.CreateTplReq) (client.CreateTplResp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTemplate", req)
+	ret0, _ := ret[0].(client.CreateTplResp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTemplate indicates an expected call of CreateTemplate.
+func (mr *MockSmsClientMockRecorder) CreateTemplate(req any) *MockSmsClientCreateTemplateCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTemplate", reflect.TypeOf((*MockSmsClient)(nil).CreateTemplate), req)
+	return &MockSmsClientCreateTemplateCall{Call: call}
+}
+
+// MockSmsClientCreateTemplateCall wrap *gomock.Call
+type MockSmsClientCreateTemplateCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockSmsClientCreateTemplateCall) Return(arg0 client.CreateTplResp, arg1 error) *MockSmsClientCreateTemplateCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockSmsClientCreateTemplateCall) Do(f func(client.CreateTplReq) (client.CreateTplResp, error)) *MockSmsClientCreateTemplateCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockSmsClientCreateTemplateCall) DoAndReturn(f func(client.CreateTplReq) (client.CreateTplResp, error)) *MockSmsClientCreateTemplateCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// QueryTemplateStatus mocks base method.
+func (m *MockSmsClient) QueryTemplateStatus(req client.QueryTplStatusReq) (client.QueryTplStatusResp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryTemplateStatus", req)
+	ret0, _ := ret[0].(client.QueryTplStatusResp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryTemplateStatus indicates an expected call of QueryTemplateStatus.
+func (mr *MockSmsClientMockRecorder) QueryTemplateStatus(req any) *MockSmsClientQueryTemplateStatusCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryTemplateStatus", reflect.TypeOf((*MockSmsClient)(nil).QueryTemplateStatus), req)
+	return &MockSmsClientQueryTemplateStatusCall{Call: call}
+}
+
+// MockSmsClientQueryTemplateStatusCall wrap *gomock.Call
+type MockSmsClientQueryTemplateStatusCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockSmsClientQueryTemplateStatusCall) Return(arg0 client.QueryTplStatusResp, arg1 error) *MockSmsClientQueryTemplateStatusCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockSmsClientQueryTemplateStatusCall) Do(f func(client.QueryTplStatusReq) (client.QueryTplStatusResp, error)) *MockSmsClientQueryTemplateStatusCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockSmsClientQueryTemplateStatusCall) DoAndReturn(f func(client.QueryTplStatusReq) (client.QueryTplStatusResp, error)) *MockSmsClientQueryTemplateStatusCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Send mocks base method.
+func (m *MockSmsClient) Send(req client.SendReq) (client.SendResp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", req)
+	ret0, _ := ret[0].(client.SendResp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockSmsClientMockRecorder) Send(req any) *MockSmsClientSendCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockSmsClient)(nil).Send), req)
+	return &MockSmsClientSendCall{Call: call}
+}
+
+// MockSmsClientSendCall wrap *gomock.Call
+type MockSmsClientSendCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockSmsClientSendCall) Return(arg0 client.SendResp, arg1 error) *MockSmsClientSendCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockSmsClientSendCall) Do(f func(client.SendReq) (client.SendResp, error)) *MockSmsClientSendCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockSmsClientSendCall) DoAndReturn(f func(client.SendReq) (client.SendResp, error)) *MockSmsClientSendCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
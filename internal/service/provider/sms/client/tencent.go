@@ -39,14 +39,19 @@ func (tc *TencentSmsClient) Send(req SendReq) (SendResp, error) {
 		return SendResp{}, fmt.Errorf("%w: phone number should not be empty", errs.ErrInvalidParam)
 	}
 
-	phoneNumberSet := make([]*string, len(req.PhoneNumbers))
+	phoneNumberSet := make([]*string, 0, len(req.PhoneNumbers))
 	for _, phoneNumber := range req.PhoneNumbers {
 		fullPhoneNum := phoneNumber
 		if !strings.HasPrefix(phoneNumber, "+") {
 			fullPhoneNum = "+86" + phoneNumber
 		}
-		phoneNumPtr := fullPhoneNum
-		phoneNumberSet = append(phoneNumberSet, &phoneNumPtr)
+		phoneNumberSet = append(phoneNumberSet, &fullPhoneNum)
+	}
+
+	// 腾讯云模板使用位置占位符 ( {1}、{2} ... )，参数必须按位置顺序传入。
+	params, err := req.OrderedParams()
+	if err != nil {
+		return SendResp{}, err
 	}
 
 	request := sms.NewSendSmsRequest()
@@ -55,11 +60,10 @@ func (tc *TencentSmsClient) Send(req SendReq) (SendResp, error) {
 	request.TemplateId = &req.TemplateId
 	request.SignName = &req.SignName
 
-	if req.TemplateParams != nil {
-		templateParamSet := make([]*string, len(req.TemplateParams))
-		for _, param := range req.TemplateParams {
-			tplParam := param
-			templateParamSet = append(templateParamSet, &tplParam)
+	if len(params) > 0 {
+		templateParamSet := make([]*string, 0, len(params))
+		for i := range params {
+			templateParamSet = append(templateParamSet, &params[i])
 		}
 		request.TemplateParamSet = templateParamSet
 	}
@@ -127,7 +131,7 @@ func (tc *TencentSmsClient) QueryTemplateStatus(req QueryTplStatusReq) (QueryTpl
 	request := sms.NewDescribeSmsTemplateListRequest()
 
 	request.International = &req.International
-	request.TemplateIdSet = make([]*uint64, len(req.TemplateIds))
+	request.TemplateIdSet = make([]*uint64, 0, len(req.TemplateIds))
 
 	for i := range req.TemplateIds {
 		templateId, err := strconv.ParseUint(req.TemplateIds[i], 10, 64)
@@ -2,8 +2,10 @@ package client
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/JrMarcco/kuryr/internal/domain"
+	"github.com/JrMarcco/kuryr/internal/errs"
 )
 
 //go:generate mockgen -source=./types.go -destination=./mock/sms_client.mock.go -package=clientmock -typed SmsClient
@@ -30,6 +32,53 @@ type SendReq struct {
 	SignName       string
 	TemplateId     string
 	TemplateParams map[string]string
+	// ParamNames 模板参数名，按模板版本中参数的位置排列。
+	// 位置占位符风格的供应商 ( 如腾讯云 ) 依此顺序组装参数列表。
+	ParamNames []string
+}
+
+// OrderedParams 按 ParamNames 的顺序返回模板参数值。
+//
+// 参数定义上线前保存的版本没有 ParamNames，此时按位置占位符的序号 ( "1"、"2" ... ) 排列参数。
+func (r SendReq) OrderedParams() ([]string, error) {
+	if len(r.ParamNames) == 0 && len(r.TemplateParams) > 0 {
+		return r.positionalParams()
+	}
+
+	if len(r.ParamNames) != len(r.TemplateParams) {
+		return nil, fmt.Errorf(
+			"%w: template params count [ %d ] mismatch param names count [ %d ]",
+			errs.ErrInvalidParam, len(r.TemplateParams), len(r.ParamNames),
+		)
+	}
+
+	params := make([]string, 0, len(r.ParamNames))
+	for _, name := range r.ParamNames {
+		val, ok := r.TemplateParams[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: missing template param [ %s ]", errs.ErrInvalidParam, name)
+		}
+		params = append(params, val)
+	}
+	return params, nil
+}
+
+// positionalParams 按参数名的数字序号排列参数值，参数名必须是从 1 开始的连续序号。
+func (r SendReq) positionalParams() ([]string, error) {
+	params := make([]string, len(r.TemplateParams))
+	filled := make([]bool, len(r.TemplateParams))
+	for name, val := range r.TemplateParams {
+		pos, err := strconv.Atoi(name)
+		if err != nil || pos < 1 || pos > len(params) || filled[pos-1] {
+			return nil, fmt.Errorf(
+				"%w: template param [ %s ] is not a positional index and the template version has no param schema",
+				errs.ErrInvalidParam, name,
+			)
+		}
+		params[pos-1] = val
+		filled[pos-1] = true
+	}
+	return params, nil
 }
 
 // SendResp 发送短信响应。
@@ -0,0 +1,84 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/JrMarcco/kuryr/internal/errs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSendReq_OrderedParams(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name    string
+		req     SendReq
+		want    []string
+		wantErr error
+	}{
+		{
+			name: "positional",
+			req: SendReq{
+				TemplateParams: map[string]string{"2": "5", "1": "1234"},
+				ParamNames:     []string{"1", "2"},
+			},
+			want: []string{"1234", "5"},
+		}, {
+			name: "named",
+			req: SendReq{
+				TemplateParams: map[string]string{"minute": "5", "code": "1234"},
+				ParamNames:     []string{"code", "minute"},
+			},
+			want: []string{"1234", "5"},
+		}, {
+			name: "positional without schema",
+			req: SendReq{
+				TemplateParams: map[string]string{"2": "5", "1": "1234"},
+			},
+			want: []string{"1234", "5"},
+		}, {
+			name: "named without schema",
+			req: SendReq{
+				TemplateParams: map[string]string{"code": "1234"},
+			},
+			wantErr: errs.ErrInvalidParam,
+		}, {
+			name: "discontinuous without schema",
+			req: SendReq{
+				TemplateParams: map[string]string{"1": "1234", "3": "5"},
+			},
+			wantErr: errs.ErrInvalidParam,
+		}, {
+			name: "no params",
+			req:  SendReq{},
+			want: []string{},
+		}, {
+			name: "missing param",
+			req: SendReq{
+				TemplateParams: map[string]string{"1": "1234", "3": "5"},
+				ParamNames:     []string{"1", "2"},
+			},
+			wantErr: errs.ErrInvalidParam,
+		}, {
+			name: "count mismatch",
+			req: SendReq{
+				TemplateParams: map[string]string{"1": "1234", "2": "5"},
+				ParamNames:     []string{"1"},
+			},
+			wantErr: errs.ErrInvalidParam,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			params, err := tc.req.OrderedParams()
+			assert.ErrorIs(t, err, tc.wantErr)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.want, params)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package sms
+
+import (
+	"context"
+
+	"github.com/JrMarcco/easy-kit/xsync"
+	"github.com/JrMarcco/kuryr/internal/domain"
+	"github.com/JrMarcco/kuryr/internal/repository"
+	"github.com/JrMarcco/kuryr/internal/service/provider/sms/client"
+)
+
+// ClientFunc 根据供应商信息创建短信客户端，生产环境为 client.NewSmsClient。
+type ClientFunc func(provider domain.Provider) (client.SmsClient, error)
+
+// ClientManager 按供应商 id 创建并缓存短信客户端。
+//
+// 供应商信息通过 repository.ProviderRepo 获取 ( ApiSecret 已解密 )，
+// 模板同步、测试发送以及消息发送都通过这里获取客户端，保证使用同一份供应商配置。
+type ClientManager struct {
+	providerRepo repository.ProviderRepo
+	clientFunc   ClientFunc
+
+	clients xsync.Map[uint64, client.SmsClient] // provider id -> sms client
+}
+
+// Get 获取供应商对应的短信客户端，首次获取时创建。
+func (m *ClientManager) Get(ctx context.Context, providerId uint64) (client.SmsClient, error) {
+	if smsClient, ok := m.clients.Load(providerId); ok {
+		return smsClient, nil
+	}
+
+	provider, err := m.providerRepo.FindById(ctx, providerId)
+	if err != nil {
+		return nil, err
+	}
+
+	smsClient, err := m.clientFunc(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	smsClient, _ = m.clients.LoadOrStore(providerId, smsClient)
+	return smsClient, nil
+}
+
+func NewClientManager(providerRepo repository.ProviderRepo, clientFunc ClientFunc) *ClientManager {
+	return &ClientManager{
+		providerRepo: providerRepo,
+		clientFunc:   clientFunc,
+		clients:      xsync.Map[uint64, client.SmsClient]{},
+	}
+}
@@ -15,6 +15,7 @@ import (
 var _ provider.Provider = (*Provider)(nil)
 
 type Provider struct {
+	id     uint64
 	name   string
 	client client.SmsClient
 
@@ -22,10 +23,14 @@ type Provider struct {
 	channelTplRepo repository.ChannelTplRepo
 }
 
+func (p *Provider) Id() uint64 {
+	return p.id
+}
+
 func (p *Provider) Send(ctx context.Context, n domain.Notification) (domain.SendResp, error) {
 	templateId := n.Template.Id
 
-	template, err := p.channelTplRepo.GetDetailById(ctx, templateId)
+	template, err := p.channelTplRepo.FindActivatedById(ctx, templateId)
 	if err != nil {
 		return domain.SendResp{}, err
 	}
@@ -39,18 +44,23 @@ func (p *Provider) Send(ctx context.Context, n domain.Notification) (domain.Send
 		return domain.SendResp{}, err
 	}
 
-	if len(version.Providers) == 0 {
-		return domain.SendResp{}, fmt.Errorf("%w: cannot find provider for channel template version, version id = %d", errs.ErrRecordNotFound, version.Id)
+	// 按消息的语言区域选择变体，每个变体在每个供应商侧都有独立的模板，
+	// 这里只能使用当前供应商自己的模板 id。
+	variant := version.ResolveVariant(n.Locale)
+	tplProvider, ok := version.FindApprovedProvider(p.id, variant.Locale)
+	if !ok {
+		return domain.SendResp{}, fmt.Errorf(
+			"%w: cannot find approved provider binding for channel template version, version id = %d, provider id = %d, locale = %s",
+			errs.ErrRecordNotFound, version.Id, p.id, variant.Locale,
+		)
 	}
 
-	const first = 0
-	// 获取供应商侧的模板 id
-	providerTplId := version.Providers[first].ProviderTplId
 	resp, err := p.client.Send(client.SendReq{
 		PhoneNumbers:   n.Receivers,
-		SignName:       version.Signature,
-		TemplateId:     providerTplId,
+		SignName:       variant.Signature,
+		TemplateId:     tplProvider.ProviderTplId,
 		TemplateParams: n.Template.Params,
+		ParamNames:     variant.ParamSchema.ParamNames(),
 	})
 	if err != nil {
 		return domain.SendResp{}, fmt.Errorf("%w: %w", errs.ErrFailedToSendNotification, err)
@@ -66,17 +76,20 @@ func (p *Provider) Send(ctx context.Context, n domain.Notification) (domain.Send
 		Result: domain.SendResult{
 			NotificationId: n.Id,
 			SendStatus:     domain.SendStatusSuccess,
+			Provider:       p.name,
 		},
 	}, nil
 }
 
 func NewProvider(
+	id uint64,
 	name string,
 	client client.SmsClient,
 	providerRepo repository.ProviderRepo,
 	channelTplRepo repository.ChannelTplRepo,
 ) *Provider {
 	return &Provider{
+		id:             id,
 		name:           name,
 		client:         client,
 		providerRepo:   providerRepo,
@@ -10,6 +10,8 @@ import (
 
 // Provider 供应商接口
 type Provider interface {
+	// Id 供应商 id，对应 domain.Provider.Id。
+	Id() uint64
 	Send(ctx context.Context, n domain.Notification) (domain.SendResp, error)
 }
 
@@ -0,0 +1,30 @@
+-- 扣减数量
+local cnt = tonumber(ARGV[1])
+-- 每日配额 ( 小于等于 0 表示不限制 )
+local daily_limit = tonumber(ARGV[2])
+-- 每月配额 ( 小于等于 0 表示不限制 )
+local monthly_limit = tonumber(ARGV[3])
+-- 每日计数过期时间（秒）
+local daily_ttl = tonumber(ARGV[4])
+-- 每月计数过期时间（秒）
+local monthly_ttl = tonumber(ARGV[5])
+
+local daily_key = KEYS[1]
+local monthly_key = KEYS[2]
+
+-- 先检查再扣减，保证每日、每月配额同时满足时才扣减
+local daily_used = tonumber(redis.call('GET', daily_key) or '0')
+if daily_limit > 0 and daily_used + cnt > daily_limit then
+    return ""
+end
+
+local monthly_used = tonumber(redis.call('GET', monthly_key) or '0')
+if monthly_limit > 0 and monthly_used + cnt > monthly_limit then
+    return ""
+end
+
+redis.call('INCRBY', daily_key, cnt)
+redis.call('EXPIRE', daily_key, daily_ttl)
+redis.call('INCRBY', monthly_key, cnt)
+redis.call('EXPIRE', monthly_key, monthly_ttl)
+return "ok"
@@ -0,0 +1,114 @@
+package quota
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/JrMarcco/kuryr/internal/domain"
+	"github.com/JrMarcco/kuryr/internal/errs"
+	"github.com/JrMarcco/kuryr/internal/repository"
+	"github.com/redis/go-redis/v9"
+)
+
+//go:embed lua/deduct.lua
+var deductLua string
+
+const (
+	dailyTtl   = 25 * time.Hour      // 每日计数多保留 1 小时，避免跨天时计数提前过期
+	monthlyTtl = 32 * 24 * time.Hour // 每月计数多保留 1 天
+)
+
+// Service 业务方配额服务，按 BizConfig.QuotaConfig 控制每日、每月发送量。
+type Service interface {
+	// Deduct 扣减配额，配额不足时返回 errs.ErrQuotaExceeded。
+	Deduct(ctx context.Context, bizId uint64, channel domain.Channel, cnt int32) error
+	// Refund 回退已扣减的配额。
+	Refund(ctx context.Context, bizId uint64, channel domain.Channel, cnt int32) error
+}
+
+var _ Service = (*RedisService)(nil)
+
+// RedisService 基于 redis 计数的配额服务实现。
+type RedisService struct {
+	rc            redis.Cmdable
+	bizConfigRepo repository.BizConfigRepo
+}
+
+func (s *RedisService) Deduct(ctx context.Context, bizId uint64, channel domain.Channel, cnt int32) error {
+	if cnt <= 0 {
+		return nil
+	}
+
+	quotaConfig, err := s.findQuotaConfig(ctx, bizId)
+	if err != nil {
+		return err
+	}
+	if quotaConfig == nil {
+		// 未配置配额，不限制
+		return nil
+	}
+
+	now := time.Now()
+	res, err := s.rc.Eval(
+		ctx,
+		deductLua,
+		[]string{s.dailyKey(bizId, channel, now), s.monthlyKey(bizId, channel, now)},
+		cnt,
+		quotaConfig.Daily.Of(channel),
+		quotaConfig.Monthly.Of(channel),
+		int64(dailyTtl.Seconds()),
+		int64(monthlyTtl.Seconds()),
+	).Result()
+	if err != nil {
+		return fmt.Errorf("[kuryr] failed to deduct quota: %w", err)
+	}
+
+	if res != "ok" {
+		return fmt.Errorf("%w: biz id = %d, channel = %d", errs.ErrQuotaExceeded, bizId, channel)
+	}
+	return nil
+}
+
+func (s *RedisService) Refund(ctx context.Context, bizId uint64, channel domain.Channel, cnt int32) error {
+	if cnt <= 0 {
+		return nil
+	}
+
+	now := time.Now()
+	pipe := s.rc.TxPipeline()
+	pipe.DecrBy(ctx, s.dailyKey(bizId, channel, now), int64(cnt))
+	pipe.DecrBy(ctx, s.monthlyKey(bizId, channel, now), int64(cnt))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("[kuryr] failed to refund quota: %w", err)
+	}
+	return nil
+}
+
+func (s *RedisService) findQuotaConfig(ctx context.Context, bizId uint64) (*domain.QuotaConfig, error) {
+	bizConfig, err := s.bizConfigRepo.FindByBizId(ctx, bizId)
+	if err != nil {
+		if errors.Is(err, errs.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return bizConfig.QuotaConfig, nil
+}
+
+func (s *RedisService) dailyKey(bizId uint64, channel domain.Channel, now time.Time) string {
+	return fmt.Sprintf("kuryr:quota:%d:%d:daily:%s", bizId, channel, now.Format("20060102"))
+}
+
+func (s *RedisService) monthlyKey(bizId uint64, channel domain.Channel, now time.Time) string {
+	return fmt.Sprintf("kuryr:quota:%d:%d:monthly:%s", bizId, channel, now.Format("200601"))
+}
+
+func NewRedisService(rc redis.Cmdable, bizConfigRepo repository.BizConfigRepo) *RedisService {
+	return &RedisService{
+		rc:            rc,
+		bizConfigRepo: bizConfigRepo,
+	}
+}
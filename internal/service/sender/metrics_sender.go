@@ -26,6 +26,13 @@ const (
 	metricsP99Error      = 0.001
 )
 
+// 指标标签取值
+const (
+	labelMixedChannel    = "mixed"   // 批量发送中包含多个渠道
+	labelUnknownProvider = "unknown" // 未发送到供应商
+	labelStatusError     = "error"   // 发送过程出错，没有发送结果
+)
+
 var _ ports.NotificationSender = (*MetricsSender)(nil)
 
 // MetricsSender 带指标的发送器。
@@ -33,31 +40,30 @@ var _ ports.NotificationSender = (*MetricsSender)(nil)
 type MetricsSender struct {
 	sender ports.NotificationSender
 
-	durationSummary *prometheus.SummaryVec
-	sendCounter     *prometheus.CounterVec // 发送次数
-	statusCounter   *prometheus.CounterVec // 发送状态计数器
+	durationSummary      *prometheus.SummaryVec
+	sendCounter          *prometheus.CounterVec   // 发送次数
+	statusCounter        *prometheus.CounterVec   // 发送状态计数器
+	batchSizeHistogram   *prometheus.HistogramVec // 批量发送的消息数
+	batchDurationSummary *prometheus.SummaryVec   // 批量发送耗时
 }
 
 func (s *MetricsSender) Send(ctx context.Context, n domain.Notification) (domain.SendResp, error) {
 	start := time.Now()
 
+	channel := n.Channel.String()
+
 	// 增加计数
-	s.sendCounter.WithLabelValues(string(n.Channel)).Inc()
+	s.sendCounter.WithLabelValues(channel).Inc()
 
 	resp, err := s.sender.Send(ctx, n)
 
+	status, provider := resultLabels(resp.Result, err)
+
 	// 记录耗时
-	duration := time.Since(start).Seconds()
-	s.durationSummary.WithLabelValues(
-		string(n.Channel),
-		string(n.SendStatus),
-	).Observe(duration)
+	s.durationSummary.WithLabelValues(channel, status, provider).Observe(time.Since(start).Seconds())
 
 	// 记录状态
-	s.statusCounter.WithLabelValues(
-		string(n.Channel),
-		string(resp.Result.SendStatus),
-	).Inc()
+	s.statusCounter.WithLabelValues(channel, status, provider).Inc()
 
 	return resp, err
 }
@@ -67,24 +73,71 @@ func (s *MetricsSender) BatchSend(ctx context.Context, ns []domain.Notification)
 		return domain.BatchSendResp{}, nil
 	}
 
-	// TODO: implement me
-	panic("implement me")
+	start := time.Now()
+
+	channels := make(map[string]string, len(ns)) // notification id -> channel
+	for _, n := range ns {
+		channel := n.Channel.String()
+		channels[n.Id] = channel
+		s.sendCounter.WithLabelValues(channel).Inc()
+	}
+
+	batchChannel := ns[0].Channel.String()
+	for _, n := range ns[1:] {
+		if n.Channel != ns[0].Channel {
+			batchChannel = labelMixedChannel
+			break
+		}
+	}
+	s.batchSizeHistogram.WithLabelValues(batchChannel).Observe(float64(len(ns)))
+
+	resp, err := s.sender.BatchSend(ctx, ns)
+
+	if err != nil {
+		s.batchDurationSummary.WithLabelValues(batchChannel, labelStatusError).Observe(time.Since(start).Seconds())
+		for _, n := range ns {
+			s.statusCounter.WithLabelValues(channels[n.Id], labelStatusError, labelUnknownProvider).Inc()
+		}
+		return resp, err
+	}
+
+	s.batchDurationSummary.WithLabelValues(batchChannel, "ok").Observe(time.Since(start).Seconds())
+	for _, res := range resp.Results {
+		status, provider := resultLabels(res, nil)
+		s.statusCounter.WithLabelValues(channels[res.NotificationId], status, provider).Inc()
+	}
+	return resp, nil
+}
+
+func resultLabels(res domain.SendResult, err error) (status, provider string) {
+	status = string(res.SendStatus)
+	if err != nil || status == "" {
+		status = labelStatusError
+	}
+
+	provider = res.Provider
+	if provider == "" {
+		provider = labelUnknownProvider
+	}
+	return status, provider
 }
 
-func NewMetricsSender(sender ports.NotificationSender) *MetricsSender {
+func NewMetricsSender(sender ports.NotificationSender, registerer prometheus.Registerer) *MetricsSender {
+	objectives := map[float64]float64{
+		metricsP50Percentile: metricsP50Error,
+		metricsP90Percentile: metricsP90Error,
+		metricsP95Percentile: metricsP95Error,
+		metricsP99Pencentile: metricsP99Error,
+	}
+
 	durationSummary := prometheus.NewSummaryVec(
 		prometheus.SummaryOpts{
-			Name: "kuryr_notification_send_duration_seconds",
-			Help: "The duration of sending a notification",
-			Objectives: map[float64]float64{
-				metricsP50Percentile: metricsP50Error,
-				metricsP90Percentile: metricsP90Error,
-				metricsP95Percentile: metricsP95Error,
-				metricsP99Pencentile: metricsP99Error,
-			},
-			MaxAge: metricsMaxAge,
+			Name:       "kuryr_notification_send_duration_seconds",
+			Help:       "The duration of sending a notification",
+			Objectives: objectives,
+			MaxAge:     metricsMaxAge,
 		},
-		[]string{"channel", "status"},
+		[]string{"channel", "status", "provider"},
 	)
 
 	sendCounter := prometheus.NewCounterVec(
@@ -100,16 +153,37 @@ func NewMetricsSender(sender ports.NotificationSender) *MetricsSender {
 			Name: "kuryr_notification_send_status_count",
 			Help: "The count of sending a notification by status",
 		},
-		[]string{"channel", "status"},
+		[]string{"channel", "status", "provider"},
+	)
+
+	batchSizeHistogram := prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "kuryr_notification_batch_send_size",
+			Help:    "The number of notifications in a batch send",
+			Buckets: prometheus.ExponentialBuckets(1, 4, 7), // 1 ~ 4096
+		},
+		[]string{"channel"},
+	)
+
+	batchDurationSummary := prometheus.NewSummaryVec(
+		prometheus.SummaryOpts{
+			Name:       "kuryr_notification_batch_send_duration_seconds",
+			Help:       "The duration of sending a batch of notifications",
+			Objectives: objectives,
+			MaxAge:     metricsMaxAge,
+		},
+		[]string{"channel", "result"},
 	)
 
 	// 注册指标
-	prometheus.MustRegister(durationSummary, sendCounter, statusCounter)
+	registerer.MustRegister(durationSummary, sendCounter, statusCounter, batchSizeHistogram, batchDurationSummary)
 
 	return &MetricsSender{
-		sender:          sender,
-		durationSummary: durationSummary,
-		sendCounter:     sendCounter,
-		statusCounter:   statusCounter,
+		sender:               sender,
+		durationSummary:      durationSummary,
+		sendCounter:          sendCounter,
+		statusCounter:        statusCounter,
+		batchSizeHistogram:   batchSizeHistogram,
+		batchDurationSummary: batchDurationSummary,
 	}
 }
@@ -0,0 +1,81 @@
+package sender
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/JrMarcco/kuryr/internal/domain"
+	portsmock "github.com/JrMarcco/kuryr/internal/service/ports/mock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestMetricsSender_BatchSend(t *testing.T) {
+	t.Parallel()
+
+	ns := []domain.Notification{
+		{Id: "1", Channel: domain.ChannelSms},
+		{Id: "2", Channel: domain.ChannelSms},
+		{Id: "3", Channel: domain.ChannelEmail},
+	}
+
+	tcs := []struct {
+		name      string
+		resp      domain.BatchSendResp
+		err       error
+		wantCount map[[3]string]float64 // channel, status, provider -> count
+	}{
+		{
+			name: "per status and provider",
+			resp: domain.BatchSendResp{Results: []domain.SendResult{
+				{NotificationId: "1", SendStatus: domain.SendStatusSuccess, Provider: "aliyun"},
+				{NotificationId: "2", SendStatus: domain.SendStatusFailure},
+				{NotificationId: "3", SendStatus: domain.SendStatusSuccess, Provider: "smtp"},
+			}},
+			wantCount: map[[3]string]float64{
+				{"sms", "success", "aliyun"}:  1,
+				{"sms", "failure", "unknown"}: 1,
+				{"email", "success", "smtp"}:  1,
+			},
+		}, {
+			name: "batch error",
+			err:  errors.New("mock error"),
+			wantCount: map[[3]string]float64{
+				{"sms", "error", "unknown"}:   2,
+				{"email", "error", "unknown"}: 1,
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSender := portsmock.NewMockNotificationSender(ctrl)
+			mockSender.EXPECT().BatchSend(gomock.Any(), ns).Return(tc.resp, tc.err)
+
+			registry := prometheus.NewRegistry()
+			s := NewMetricsSender(mockSender, registry)
+
+			_, err := s.BatchSend(context.Background(), ns)
+			assert.Equal(t, tc.err, err)
+
+			assert.Equal(t, float64(2), testutil.ToFloat64(s.sendCounter.WithLabelValues("sms")))
+			assert.Equal(t, float64(1), testutil.ToFloat64(s.sendCounter.WithLabelValues("email")))
+			for labels, want := range tc.wantCount {
+				assert.Equal(t, want, testutil.ToFloat64(s.statusCounter.WithLabelValues(labels[0], labels[1], labels[2])), labels)
+			}
+
+			count, err := testutil.GatherAndCount(registry, "kuryr_notification_batch_send_size", "kuryr_notification_batch_send_duration_seconds")
+			require.NoError(t, err)
+			assert.Equal(t, 2, count)
+		})
+	}
+}
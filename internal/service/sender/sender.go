@@ -16,7 +16,6 @@ import (
 var _ ports.NotificationSender = (*DefaultSender)(nil)
 
 type DefaultSender struct {
-	callbackLogRepo  repository.CallbackLogRepo
 	notificationRepo repository.NotificationRepo
 
 	channelSender ports.ChannelSender
@@ -31,7 +30,7 @@ func (s *DefaultSender) Send(ctx context.Context, n domain.Notification) (domain
 		NotificationId: n.Id,
 	}
 
-	_, err := s.channelSender.Send(ctx, n)
+	resp, err := s.channelSender.Send(ctx, n)
 	if err != nil {
 		s.logger.Error("[kuryr] failed to send notification", zap.Error(err))
 
@@ -43,33 +42,17 @@ func (s *DefaultSender) Send(ctx context.Context, n domain.Notification) (domain
 		err = s.notificationRepo.MarkFailure(ctx, n)
 	} else {
 		res.SendStatus = domain.SendStatusSuccess
+		res.Provider = resp.Result.Provider
 		n.SendStatus = domain.SendStatusSuccess
 		err = s.notificationRepo.MarkSuccess(ctx, n)
 	}
 
+	// 发送状态与回调 outbox 在同一事务中写入，回调日志由 outbox relay 异步生成。
 	if err != nil {
 		// 更新发送状态失败
 		return domain.SendResp{}, err
 	}
 
-	// 写入 callback log 记录。
-	callbackLog := domain.CallbackLog{
-		Notification: domain.Notification{
-			Id:         n.Id,
-			SendStatus: n.SendStatus,
-		},
-		BizId:        n.BizId,
-		BizKey:       n.BizKey,
-		RetriedTimes: 0,
-		NextRetryAt:  0,
-		Status:       domain.CallbackLogStatusPrepare,
-	}
-	err = s.callbackLogRepo.Save(ctx, callbackLog)
-	if err != nil {
-		// 回调保存失败记录日志，不影响发送结果。
-		s.logger.Error("[kuryr] failed to save callback log for notification [ %d ]", zap.String("notification_id", n.Id), zap.Error(err))
-	}
-
 	return domain.SendResp{
 		Result: res,
 	}, nil
@@ -83,13 +66,18 @@ func (s *DefaultSender) BatchSend(ctx context.Context, ns []domain.Notification)
 	var successMu, failureMu sync.Mutex
 	var successResults, failureResults []domain.SendResult
 
+	// eg 等待任务提交，wg 等待已提交的任务执行完成。
 	var eg errgroup.Group
+	var wg sync.WaitGroup
 	for i := range ns {
 		n := ns[i]
 
+		wg.Add(1)
 		eg.Go(func() error {
 			err := s.taskPool.Submit(ctx, pool.TaskFunc(func(ctx context.Context) error {
-				_, err := s.channelSender.Send(ctx, n)
+				defer wg.Done()
+
+				resp, err := s.channelSender.Send(ctx, n)
 				if err != nil {
 					res := domain.SendResult{
 						NotificationId: n.Id,
@@ -106,6 +94,7 @@ func (s *DefaultSender) BatchSend(ctx context.Context, ns []domain.Notification)
 				res := domain.SendResult{
 					NotificationId: n.Id,
 					SendStatus:     domain.SendStatusSuccess,
+					Provider:       resp.Result.Provider,
 				}
 
 				successMu.Lock()
@@ -116,43 +105,65 @@ func (s *DefaultSender) BatchSend(ctx context.Context, ns []domain.Notification)
 			}))
 
 			if err != nil {
+				wg.Done()
 				s.logger.Warn("[kuryr] failed to submit task to task pool", zap.Error(err), zap.String("notification_id", n.Id))
 			}
 			return err
 		})
 	}
 
-	if err := eg.Wait(); err != nil {
-		s.logger.Warn("[kuryr] failed to send notifications", zap.Error(err))
-		return domain.BatchSendResp{}, fmt.Errorf("[kuryr] failed to send notifications: %w", err)
-	}
+	submitErr := eg.Wait()
+	wg.Wait()
 
-	allId := make([]string, 0, len(successResults)+len(failureResults))
-	for _, res := range successResults {
-		allId = append(allId, res.NotificationId)
-	}
+	results := append(successResults, failureResults...)
 
-	for _, res := range failureResults {
-		allId = append(allId, res.NotificationId)
+	// 即使部分任务提交失败，已经执行的发送结果也需要落库，避免已发出的消息被重复发送。
+	if err := s.batchMarkSendStatus(ctx, ns, results); err != nil {
+		return domain.BatchSendResp{}, err
 	}
 
-	// TODO: 写入 callback log 记录
+	if submitErr != nil {
+		s.logger.Warn("[kuryr] failed to send notifications", zap.Error(submitErr))
+		return domain.BatchSendResp{}, fmt.Errorf("[kuryr] failed to send notifications: %w", submitErr)
+	}
 
 	// 合并结果并返回。
 	return domain.BatchSendResp{
-		Results: append(successResults, failureResults...),
+		Results: results,
 	}, nil
 }
 
+// batchMarkSendStatus 批量更新有发送结果的消息的发送状态，并在同一事务中写入回调 outbox。
+// 没有发送结果 ( 任务提交失败 ) 的消息保持原状态不变。
+func (s *DefaultSender) batchMarkSendStatus(ctx context.Context, ns []domain.Notification, results []domain.SendResult) error {
+	if len(results) == 0 {
+		return nil
+	}
+
+	statuses := make(map[string]domain.SendStatus, len(results))
+	for _, res := range results {
+		statuses[res.NotificationId] = res.SendStatus
+	}
+
+	marked := make([]domain.Notification, 0, len(results))
+	for _, n := range ns {
+		status, ok := statuses[n.Id]
+		if !ok {
+			continue
+		}
+		n.SendStatus = status
+		marked = append(marked, n)
+	}
+	return s.notificationRepo.BatchMarkSendStatus(ctx, marked)
+}
+
 func NewDefaultSender(
-	callbackLogRepo repository.CallbackLogRepo,
 	notificationRepo repository.NotificationRepo,
 	channelSender ports.ChannelSender,
 	taskPool pool.TaskPool,
 	logger *zap.Logger,
 ) *DefaultSender {
 	return &DefaultSender{
-		callbackLogRepo:  callbackLogRepo,
 		notificationRepo: notificationRepo,
 		channelSender:    channelSender,
 		taskPool:         taskPool,
@@ -0,0 +1,108 @@
+package sender
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/JrMarcco/easy-kit/pool"
+	"github.com/JrMarcco/kuryr/internal/domain"
+	"github.com/JrMarcco/kuryr/internal/repository"
+	"github.com/JrMarcco/kuryr/internal/service/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// stubTaskPool 同步执行前 limit 个任务，之后的任务提交失败。
+type stubTaskPool struct {
+	pool.TaskPool
+
+	mu        sync.Mutex
+	limit     int
+	submitted int
+}
+
+func (p *stubTaskPool) Submit(ctx context.Context, task pool.Task) error {
+	p.mu.Lock()
+	if p.submitted >= p.limit {
+		p.mu.Unlock()
+		return errors.New("mock task pool full")
+	}
+	p.submitted++
+	p.mu.Unlock()
+
+	return task.Run(ctx)
+}
+
+type stubChannelSender struct {
+	ports.ChannelSender
+}
+
+func (s *stubChannelSender) Send(_ context.Context, n domain.Notification) (domain.SendResp, error) {
+	if n.BizKey == "failure" {
+		return domain.SendResp{}, errors.New("mock send error")
+	}
+	return domain.SendResp{Result: domain.SendResult{NotificationId: n.Id, SendStatus: domain.SendStatusSuccess}}, nil
+}
+
+type stubNotificationRepo struct {
+	repository.NotificationRepo
+
+	marked []domain.Notification
+}
+
+func (r *stubNotificationRepo) BatchMarkSendStatus(_ context.Context, ns []domain.Notification) error {
+	r.marked = append(r.marked, ns...)
+	return nil
+}
+
+func TestDefaultSender_BatchSend(t *testing.T) {
+	t.Parallel()
+
+	ns := []domain.Notification{
+		{Id: "1", BizKey: "success"},
+		{Id: "2", BizKey: "failure"},
+		{Id: "3", BizKey: "success"},
+		{Id: "4", BizKey: "failure"},
+	}
+
+	tcs := []struct {
+		name       string
+		limit      int
+		wantMarked int
+		wantErr    bool
+	}{
+		{name: "all submitted", limit: len(ns), wantMarked: len(ns)},
+		{name: "submit failed part-way", limit: 2, wantMarked: 2, wantErr: true},
+		{name: "submit all failed", limit: 0, wantMarked: 0, wantErr: true},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &stubNotificationRepo{}
+			s := NewDefaultSender(repo, &stubChannelSender{}, &stubTaskPool{limit: tc.limit}, zap.NewNop())
+
+			resp, err := s.BatchSend(context.Background(), ns)
+			if tc.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Len(t, resp.Results, len(ns))
+			}
+
+			// 已经执行的发送结果都需要落库，且状态与发送结果一致。
+			require.Len(t, repo.marked, tc.wantMarked)
+			for _, n := range repo.marked {
+				want := domain.SendStatusSuccess
+				if n.BizKey == "failure" {
+					want = domain.SendStatusFailure
+				}
+				assert.Equal(t, want, n.SendStatus, n.Id)
+			}
+		})
+	}
+}
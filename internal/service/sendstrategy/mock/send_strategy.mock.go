@@ -0,0 +1,120 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./types.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./types.go -destination=./mock/send_strategy.mock.go -package=sendstrategymock -typed SendStrategy
+//
+
+// Package sendstrategymock is a generated GoMock package.
+package sendstrategymock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/JrMarcco/kuryr/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSendStrategy is a mock of SendStrategy interface.
+type MockSendStrategy struct {
+	ctrl     *gomock.Controller
+	recorder *MockSendStrategyMockRecorder
+	isgomock struct{}
+}
+
+// MockSendStrategyMockRecorder is the mock recorder for MockSendStrategy.
+type MockSendStrategyMockRecorder struct {
+	mock *MockSendStrategy
+}
+
+// NewMockSendStrategy creates a new mock instance.
+func NewMockSendStrategy(ctrl *gomock.Controller) *MockSendStrategy {
+	mock := &MockSendStrategy{ctrl: ctrl}
+	mock.recorder = &MockSendStrategyMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSendStrategy) EXPECT() *MockSendStrategyMockRecorder {
+	return m.recorder
+}
+
+// BatchSend mocks base method.
+func (m *MockSendStrategy) BatchSend(ctx context.Context, ns []domain.Notification) (domain.BatchSendResp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchSend", ctx, ns)
+	ret0, _ := ret[0].(domain.BatchSendResp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchSend indicates an expected call of BatchSend.
+func (mr *MockSendStrategyMockRecorder) BatchSend(ctx, ns any) *MockSendStrategyBatchSendCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchSend", reflect.TypeOf((*MockSendStrategy)(nil).BatchSend), ctx, ns)
+	return &MockSendStrategyBatchSendCall{Call: call}
+}
+
+// MockSendStrategyBatchSendCall wrap *gomock.Call
+type MockSendStrategyBatchSendCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockSendStrategyBatchSendCall) Return(arg0 domain.BatchSendResp, arg1 error) *MockSendStrategyBatchSendCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockSendStrategyBatchSendCall) Do(f func(context.Context, []domain.Notification) (domain.BatchSendResp, error)) *MockSendStrategyBatchSendCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockSendStrategyBatchSendCall) DoAndReturn(f func(context.Context, []domain.Notification) (domain.BatchSendResp, error)) *MockSendStrategyBatchSendCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Send mocks base method.
+func (m *MockSendStrategy) Send(ctx context.Context, n domain.Notification) (domain.SendResp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", ctx, n)
+	ret0, _ := ret[0].(domain.SendResp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockSendStrategyMockRecorder) Send(ctx, n any) *MockSendStrategySendCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockSendStrategy)(nil).Send), ctx, n)
+	return &MockSendStrategySendCall{Call: call}
+}
+
+// MockSendStrategySendCall wrap *gomock.Call
+type MockSendStrategySendCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockSendStrategySendCall) Return(arg0 domain.SendResp, arg1 error) *MockSendStrategySendCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockSendStrategySendCall) Do(f func(context.Context, domain.Notification) (domain.SendResp, error)) *MockSendStrategySendCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockSendStrategySendCall) DoAndReturn(f func(context.Context, domain.Notification) (domain.SendResp, error)) *MockSendStrategySendCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
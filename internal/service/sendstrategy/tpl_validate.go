@@ -0,0 +1,94 @@
+package sendstrategy
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/JrMarcco/kuryr/internal/domain"
+	"github.com/JrMarcco/kuryr/internal/errs"
+	"github.com/JrMarcco/kuryr/internal/repository"
+)
+
+var _ SendStrategy = (*TplValidateStrategy)(nil)
+
+// TplValidateStrategy 发送策略装饰器，
+// 在消息入库前按模板激活版本的参数定义校验模板参数，避免发送到供应商侧才发现参数错误。
+// 同时将消息的模板版本设置为当前激活版本，消息总是按激活版本发送。
+type TplValidateStrategy struct {
+	next    SendStrategy
+	tplRepo repository.ChannelTplRepo
+}
+
+func (s *TplValidateStrategy) Send(ctx context.Context, n domain.Notification) (domain.SendResp, error) {
+	version, err := s.findActivatedVersion(ctx, n)
+	if err != nil {
+		return domain.SendResp{}, err
+	}
+	if err = version.ResolveVariant(n.Locale).ParamSchema.Validate(n.Template.Params); err != nil {
+		return domain.SendResp{}, err
+	}
+
+	n.Template.Version = version.Id
+	return s.next.Send(ctx, n)
+}
+
+func (s *TplValidateStrategy) BatchSend(ctx context.Context, ns []domain.Notification) (domain.BatchSendResp, error) {
+	validated, err := s.Validate(ctx, ns)
+	if err != nil {
+		return domain.BatchSendResp{}, err
+	}
+	return s.next.BatchSend(ctx, validated)
+}
+
+// Validate 按模板激活版本的参数定义校验消息的模板参数，返回模板版本设置为激活版本的消息副本。
+// 异步发送在写入 kafka 前通过这里提前校验，参数错误直接返回给调用方。
+func (s *TplValidateStrategy) Validate(ctx context.Context, ns []domain.Notification) ([]domain.Notification, error) {
+	type versionKey struct {
+		tplId   uint64
+		channel domain.Channel
+	}
+
+	// 不修改调用方的切片
+	validated := slices.Clone(ns)
+	versions := make(map[versionKey]domain.ChannelTemplateVersion)
+	for i := range validated {
+		key := versionKey{tplId: validated[i].Template.Id, channel: validated[i].Channel}
+		version, ok := versions[key]
+		if !ok {
+			var err error
+			if version, err = s.findActivatedVersion(ctx, validated[i]); err != nil {
+				return nil, err
+			}
+			versions[key] = version
+		}
+
+		if err := version.ResolveVariant(validated[i].Locale).ParamSchema.Validate(validated[i].Template.Params); err != nil {
+			return nil, err
+		}
+		validated[i].Template.Version = version.Id
+	}
+	return validated, nil
+}
+
+// findActivatedVersion 获取模板当前激活版本，并校验模板渠道与消息渠道一致。
+func (s *TplValidateStrategy) findActivatedVersion(ctx context.Context, n domain.Notification) (domain.ChannelTemplateVersion, error) {
+	tpl, err := s.tplRepo.FindActivatedById(ctx, n.Template.Id)
+	if err != nil {
+		return domain.ChannelTemplateVersion{}, err
+	}
+
+	if tpl.Channel != n.Channel {
+		return domain.ChannelTemplateVersion{}, fmt.Errorf(
+			"%w: template channel [ %d ] mismatch notification channel [ %d ]", errs.ErrInvalidChannel, tpl.Channel, n.Channel,
+		)
+	}
+	return tpl.GetActivatedVersion()
+}
+
+func NewTplValidateStrategy(next SendStrategy, tplRepo repository.ChannelTplRepo) *TplValidateStrategy {
+	return &TplValidateStrategy{
+		next:    next,
+		tplRepo: tplRepo,
+	}
+}
@@ -0,0 +1,96 @@
+package sendstrategy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/JrMarcco/kuryr/internal/domain"
+	"github.com/JrMarcco/kuryr/internal/errs"
+	"github.com/JrMarcco/kuryr/internal/repository"
+	sendstrategymock "github.com/JrMarcco/kuryr/internal/service/sendstrategy/mock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type stubTplRepo struct {
+	repository.ChannelTplRepo
+
+	tpl domain.ChannelTemplate
+}
+
+func (r *stubTplRepo) FindActivatedById(_ context.Context, _ uint64) (domain.ChannelTemplate, error) {
+	return r.tpl, nil
+}
+
+func TestTplValidateStrategy_Send(t *testing.T) {
+	t.Parallel()
+
+	tplRepo := &stubTplRepo{
+		tpl: domain.ChannelTemplate{
+			Id:                 1,
+			Channel:            domain.ChannelSms,
+			ActivatedVersionId: 10,
+			Versions: []domain.ChannelTemplateVersion{
+				{
+					Id:          10,
+					AuditStatus: domain.AuditStatusApproved,
+					ParamSchema: domain.TplParamSchema{
+						Style:  domain.TplParamStyleNamed,
+						Params: []domain.TplParam{{Name: "code", Type: domain.TplParamTypeNumber}},
+					},
+				},
+			},
+		},
+	}
+
+	tcs := []struct {
+		name        string
+		n           domain.Notification
+		wantVersion uint64
+		wantErr     error
+	}{
+		{
+			name: "basic",
+			n: domain.Notification{
+				Channel:  domain.ChannelSms,
+				Template: domain.Template{Id: 1, Params: map[string]string{"code": "123456"}},
+			},
+			wantVersion: 10,
+		}, {
+			name: "missing param",
+			n: domain.Notification{
+				Channel:  domain.ChannelSms,
+				Template: domain.Template{Id: 1, Params: map[string]string{}},
+			},
+			wantErr: errs.ErrInvalidParam,
+		}, {
+			name: "channel mismatch",
+			n: domain.Notification{
+				Channel:  domain.ChannelEmail,
+				Template: domain.Template{Id: 1, Params: map[string]string{"code": "123456"}},
+			},
+			wantErr: errs.ErrInvalidChannel,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			next := sendstrategymock.NewMockSendStrategy(ctrl)
+			if tc.wantErr == nil {
+				next.EXPECT().Send(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, n domain.Notification) (domain.SendResp, error) {
+						// 消息模板版本设置为激活版本。
+						assert.Equal(t, tc.wantVersion, n.Template.Version)
+						return domain.SendResp{}, nil
+					},
+				)
+			}
+
+			_, err := NewTplValidateStrategy(next, tplRepo).Send(context.Background(), tc.n)
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
@@ -2,12 +2,20 @@ package template
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/JrMarcco/kuryr/internal/domain"
 	"github.com/JrMarcco/kuryr/internal/errs"
 	pkggorm "github.com/JrMarcco/kuryr/internal/pkg/gorm"
+	"github.com/JrMarcco/kuryr/internal/pkg/mq"
 	"github.com/JrMarcco/kuryr/internal/repository"
+	"github.com/JrMarcco/kuryr/internal/service/provider/sms"
+	"github.com/JrMarcco/kuryr/internal/service/provider/sms/client"
+	"github.com/JrMarcco/kuryr/internal/service/quota"
 )
 
 //go:generate mockgen -source=./template_service.go -destination=./mock/template_service.mock.go -package=templatemock -typed Service
@@ -19,9 +27,26 @@ type Service interface {
 
 	SaveVersion(ctx context.Context, version domain.ChannelTemplateVersion) (domain.ChannelTemplateVersion, error)
 	DeleteVersion(ctx context.Context, id uint64) error
-	ActivateVersion(ctx context.Context, templateId uint64, versionId uint64) error
+	// ActivateVersion 激活版本。
+	ActivateVersion(ctx context.Context, templateId uint64, versionId uint64, operatorId uint64) error
+	// RollbackVersion 回滚到上一个可用的激活版本，返回回滚后的激活版本 id。
+	RollbackVersion(ctx context.Context, templateId uint64, operatorId uint64) (uint64, error)
+	FindActivationLogByTplId(ctx context.Context, tplId uint64) ([]domain.ChannelTemplateActivationLog, error)
 	FindVersionByTplId(ctx context.Context, tplId uint64) ([]domain.ChannelTemplateVersion, error)
 
+	// SubmitForReview 提交版本审批。
+	SubmitForReview(ctx context.Context, versionId uint64, operatorId uint64) error
+	// ApproveVersion 审批通过。
+	ApproveVersion(ctx context.Context, versionId uint64, auditorId uint64) error
+	// RejectVersion 审批拒绝。
+	RejectVersion(ctx context.Context, versionId uint64, auditorId uint64, reason string) error
+	FindAuditLogByVersionId(ctx context.Context, versionId uint64) ([]domain.ChannelTemplateAuditLog, error)
+
+	// PreviewVersion 使用示例参数渲染版本内容，locale 为空时使用默认变体。
+	PreviewVersion(ctx context.Context, versionId uint64, locale string, params map[string]string) (domain.TplPreview, error)
+	// TestSend 使用未激活的版本向测试接收者发送消息。
+	TestSend(ctx context.Context, versionId uint64, locale string, receivers []string, params map[string]string) error
+
 	SaveProviders(ctx context.Context, providers []domain.ChannelTemplateProvider) error
 	DeleteProvider(ctx context.Context, id uint64) error
 	FindProviderByVersionId(ctx context.Context, versionId uint64) ([]domain.ChannelTemplateProvider, error)
@@ -30,7 +55,14 @@ type Service interface {
 var _ Service = (*DefaultService)(nil)
 
 type DefaultService struct {
-	repo repository.ChannelTplRepo
+	repo         repository.ChannelTplRepo
+	providerRepo repository.ProviderRepo
+	quotaSvc     quota.Service
+	bindProducer mq.Producer[domain.TplProviderBindEvent]
+	smsClients   *sms.ClientManager
+
+	reviewCooldown time.Duration // 审批拒绝后重新提交审批的冷却时间
+	testReceivers  []string      // 测试发送允许的接收者
 }
 
 // SaveTemplate 新增渠道模板。
@@ -55,25 +87,118 @@ func (s *DefaultService) FindTemplateByBizId(ctx context.Context, bizId uint64,
 }
 
 func (s *DefaultService) SaveVersion(ctx context.Context, version domain.ChannelTemplateVersion) (domain.ChannelTemplateVersion, error) {
+	for i := range version.Variants {
+		version.Variants[i].Locale = domain.NormalizeLocale(version.Variants[i].Locale)
+	}
+
 	if err := version.Validate(); err != nil {
 		return domain.ChannelTemplateVersion{}, err
 	}
+
+	// 从模板内容中解析参数定义，请求中携带的参数定义只作为类型、长度约束。
+	var err error
+	if version.ParamSchema, err = s.parseParamSchema(version.Content, version.ParamSchema); err != nil {
+		return domain.ChannelTemplateVersion{}, err
+	}
+	for i := range version.Variants {
+		variant := &version.Variants[i]
+		if variant.ParamSchema, err = s.parseParamSchema(variant.Content, variant.ParamSchema); err != nil {
+			return domain.ChannelTemplateVersion{}, fmt.Errorf("variant [ %s ]: %w", variant.Locale, err)
+		}
+	}
+
+	// 新版本统一为“待审核”，审批相关字段只能通过审批流程变更。
+	version.AuditStatus = domain.AuditStatusPending
+	version.AuditorId = 0
+	version.AuditId = 0
+	version.AuditTime = 0
+	version.RejectionReason = ""
+	version.LastReviewAt = 0
 	return s.repo.SaveVersion(ctx, version)
 }
 
+func (s *DefaultService) parseParamSchema(content string, constraints domain.TplParamSchema) (domain.TplParamSchema, error) {
+	schema, err := domain.ParseTplParamSchema(content)
+	if err != nil {
+		return domain.TplParamSchema{}, err
+	}
+	return schema.WithConstraints(constraints.Params)
+}
+
 func (s *DefaultService) DeleteVersion(ctx context.Context, id uint64) error {
 	return s.repo.DeleteVersion(ctx, id)
 }
 
-// TODO: 这里需要做一些业务上的判断。
 // ActivateVersion 激活版本。
-func (s *DefaultService) ActivateVersion(ctx context.Context, templateId uint64, versionId uint64) error {
+//
+// 只允许激活审批通过，且关联了供应商侧审批通过、供应商处于启用状态的版本。
+func (s *DefaultService) ActivateVersion(ctx context.Context, templateId uint64, versionId uint64, operatorId uint64) error {
 	template, err := s.repo.FindTemplateById(ctx, templateId)
 	if err != nil {
 		return err
 	}
 
-	// 查询版本
+	if template.ActivatedVersionId == versionId {
+		return fmt.Errorf("%w: version is already activated", errs.ErrInvalidStatus)
+	}
+
+	if err = s.checkActivatable(ctx, template, versionId); err != nil {
+		return err
+	}
+
+	return s.repo.ActivateVersion(ctx, domain.ChannelTemplateActivationLog{
+		TplId:         templateId,
+		FromVersionId: template.ActivatedVersionId,
+		ToVersionId:   versionId,
+		Action:        domain.ActivationActionActivate,
+		OperatorId:    operatorId,
+	})
+}
+
+// RollbackVersion 回滚激活版本。
+//
+// 沿激活历史向前查找第一个仍然可以激活的版本，被回滚掉的版本不会再作为回滚目标。
+func (s *DefaultService) RollbackVersion(ctx context.Context, templateId uint64, operatorId uint64) (uint64, error) {
+	template, err := s.repo.FindTemplateById(ctx, templateId)
+	if err != nil {
+		return 0, err
+	}
+	if template.ActivatedVersionId == 0 {
+		return 0, fmt.Errorf("%w: channel template id = %d", errs.ErrNoActivatedTplVersion, templateId)
+	}
+
+	logs, err := s.repo.FindActivationLogByTplId(ctx, templateId)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, versionId := range domain.RollbackCandidates(template.ActivatedVersionId, logs) {
+		err = s.checkActivatable(ctx, template, versionId)
+		if err != nil {
+			// 版本已删除或已不满足激活条件，继续向前查找
+			if errors.Is(err, errs.ErrRecordNotFound) || errors.Is(err, errs.ErrInvalidStatus) {
+				continue
+			}
+			return 0, err
+		}
+
+		err = s.repo.ActivateVersion(ctx, domain.ChannelTemplateActivationLog{
+			TplId:         templateId,
+			FromVersionId: template.ActivatedVersionId,
+			ToVersionId:   versionId,
+			Action:        domain.ActivationActionRollback,
+			OperatorId:    operatorId,
+		})
+		if err != nil {
+			return 0, err
+		}
+		return versionId, nil
+	}
+	return 0, fmt.Errorf("%w: no previous version can be rolled back to, channel template id = %d", errs.ErrRecordNotFound, templateId)
+}
+
+// checkActivatable 检查版本是否满足激活条件。
+func (s *DefaultService) checkActivatable(ctx context.Context, template domain.ChannelTemplate, versionId uint64) error {
 	version, err := s.repo.FindVersionById(ctx, versionId)
 	if err != nil {
 		return err
@@ -88,20 +213,240 @@ func (s *DefaultService) ActivateVersion(ctx context.Context, templateId uint64,
 		return fmt.Errorf("%w: version is not approved", errs.ErrInvalidStatus)
 	}
 
-	return s.repo.ActivateVersion(ctx, templateId, versionId)
+	if _, _, err = s.findActiveProvider(ctx, versionId, nil); err != nil {
+		if errors.Is(err, errs.ErrRecordNotFound) {
+			return fmt.Errorf("%w: %w", errs.ErrInvalidStatus, err)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *DefaultService) FindActivationLogByTplId(ctx context.Context, tplId uint64) ([]domain.ChannelTemplateActivationLog, error) {
+	return s.repo.FindActivationLogByTplId(ctx, tplId)
 }
 
 func (s *DefaultService) FindVersionByTplId(ctx context.Context, tplId uint64) ([]domain.ChannelTemplateVersion, error) {
 	return s.repo.FindVersionByTplId(ctx, tplId)
 }
 
+func (s *DefaultService) SubmitForReview(ctx context.Context, versionId uint64, operatorId uint64) error {
+	version, err := s.repo.FindVersionById(ctx, versionId)
+	if err != nil {
+		return err
+	}
+
+	from := version.AuditStatus
+	if err = version.SubmitForReview(time.Now(), s.reviewCooldown); err != nil {
+		return err
+	}
+	return s.audit(ctx, version, from, domain.AuditActionSubmit, operatorId, "")
+}
+
+func (s *DefaultService) ApproveVersion(ctx context.Context, versionId uint64, auditorId uint64) error {
+	if auditorId == 0 {
+		return fmt.Errorf("%w: auditor id cannot be zero", errs.ErrInvalidParam)
+	}
+
+	version, err := s.repo.FindVersionById(ctx, versionId)
+	if err != nil {
+		return err
+	}
+
+	from := version.AuditStatus
+	if err = version.Approve(auditorId, time.Now()); err != nil {
+		return err
+	}
+	return s.audit(ctx, version, from, domain.AuditActionApprove, auditorId, "")
+}
+
+func (s *DefaultService) RejectVersion(ctx context.Context, versionId uint64, auditorId uint64, reason string) error {
+	if auditorId == 0 {
+		return fmt.Errorf("%w: auditor id cannot be zero", errs.ErrInvalidParam)
+	}
+
+	version, err := s.repo.FindVersionById(ctx, versionId)
+	if err != nil {
+		return err
+	}
+
+	from := version.AuditStatus
+	if err = version.Reject(auditorId, reason, time.Now()); err != nil {
+		return err
+	}
+	return s.audit(ctx, version, from, domain.AuditActionReject, auditorId, reason)
+}
+
+// audit 持久化审批状态变更，同时记录审批历史。
+func (s *DefaultService) audit(
+	ctx context.Context,
+	version domain.ChannelTemplateVersion,
+	from domain.AuditStatus,
+	action domain.AuditAction,
+	operatorId uint64,
+	remark string,
+) error {
+	return s.repo.AuditVersion(ctx, version, domain.ChannelTemplateAuditLog{
+		TplId:        version.TplId,
+		TplVersionId: version.Id,
+		Action:       action,
+		FromStatus:   from,
+		ToStatus:     version.AuditStatus,
+		OperatorId:   operatorId,
+		Remark:       remark,
+	})
+}
+
+func (s *DefaultService) FindAuditLogByVersionId(ctx context.Context, versionId uint64) ([]domain.ChannelTemplateAuditLog, error) {
+	return s.repo.FindAuditLogByVersionId(ctx, versionId)
+}
+
+func (s *DefaultService) PreviewVersion(ctx context.Context, versionId uint64, locale string, params map[string]string) (domain.TplPreview, error) {
+	version, err := s.repo.FindVersionById(ctx, versionId)
+	if err != nil {
+		return domain.TplPreview{}, err
+	}
+
+	template, err := s.repo.FindTemplateById(ctx, version.TplId)
+	if err != nil {
+		return domain.TplPreview{}, err
+	}
+	return version.Preview(template.Channel, locale, params)
+}
+
+// TestSend 测试发送。
+//
+// 版本不需要激活，但不能是审批拒绝的版本，同时需要关联供应商侧审批通过的模板。
+// 接收者必须在测试白名单中，发送量计入业务方配额。
+// 目前只支持短信渠道。
+func (s *DefaultService) TestSend(ctx context.Context, versionId uint64, locale string, receivers []string, params map[string]string) error {
+	if len(receivers) == 0 {
+		return fmt.Errorf("%w: receivers cannot be empty", errs.ErrInvalidParam)
+	}
+	for _, receiver := range receivers {
+		if !slices.Contains(s.testReceivers, receiver) {
+			return fmt.Errorf("%w: receiver [ %s ] is not in test allow list", errs.ErrInvalidParam, receiver)
+		}
+	}
+
+	version, err := s.repo.FindVersionById(ctx, versionId)
+	if err != nil {
+		return err
+	}
+	if version.AuditStatus == domain.AuditStatusRejected {
+		return fmt.Errorf("%w: version is rejected", errs.ErrInvalidStatus)
+	}
+
+	template, err := s.repo.FindTemplateById(ctx, version.TplId)
+	if err != nil {
+		return err
+	}
+	if !template.Channel.IsSms() {
+		return fmt.Errorf("%w: test send only support sms channel", errs.ErrInvalidChannel)
+	}
+
+	// 渲染一次用于校验参数
+	variant := version.ResolveVariant(locale)
+	if _, err = variant.Preview(template.Channel, params); err != nil {
+		return err
+	}
+
+	provider, tplProvider, err := s.findActiveProvider(ctx, versionId, func(tplProvider domain.ChannelTemplateProvider) bool {
+		return tplProvider.Locale == variant.Locale
+	})
+	if err != nil {
+		return err
+	}
+
+	smsClient, err := s.smsClients.Get(ctx, provider.Id)
+	if err != nil {
+		return err
+	}
+
+	cnt := int32(len(receivers))
+	if err = s.quotaSvc.Deduct(ctx, template.BizId, template.Channel, cnt); err != nil {
+		return err
+	}
+
+	resp, err := smsClient.Send(client.SendReq{
+		PhoneNumbers:   receivers,
+		SignName:       variant.Signature,
+		TemplateId:     tplProvider.ProviderTplId,
+		TemplateParams: params,
+		ParamNames:     variant.ParamSchema.ParamNames(),
+	})
+	if err == nil {
+		for _, res := range resp.Results {
+			if !strings.EqualFold(res.Code, "OK") {
+				err = fmt.Errorf("code = %s, message = %s", res.Code, res.Message)
+				break
+			}
+		}
+	}
+	if err != nil {
+		// 发送失败回退配额
+		if refundErr := s.quotaSvc.Refund(ctx, template.BizId, template.Channel, cnt); refundErr != nil {
+			err = errors.Join(err, refundErr)
+		}
+		return fmt.Errorf("%w: %w", errs.ErrFailedToSendNotification, err)
+	}
+	return nil
+}
+
+// findActiveProvider 查找版本关联的、供应商侧审批通过且处于启用状态的供应商。
+// match 用于进一步筛选关联关系 ( 例如按变体 )，为 nil 时不筛选。
+func (s *DefaultService) findActiveProvider(
+	ctx context.Context, versionId uint64, match func(domain.ChannelTemplateProvider) bool,
+) (domain.Provider, domain.ChannelTemplateProvider, error) {
+	tplProviders, err := s.repo.FindProviderByVersionId(ctx, versionId)
+	if err != nil {
+		return domain.Provider{}, domain.ChannelTemplateProvider{}, err
+	}
+
+	for _, tplProvider := range tplProviders {
+		if !tplProvider.AuditStatus.IsApproved() || tplProvider.ProviderTplId == "" {
+			continue
+		}
+		if match != nil && !match(tplProvider) {
+			continue
+		}
+
+		provider, err := s.providerRepo.FindById(ctx, tplProvider.ProviderId)
+		if err != nil {
+			return domain.Provider{}, domain.ChannelTemplateProvider{}, err
+		}
+		if provider.ActiveStatus == domain.ActiveStatusActive {
+			return provider, tplProvider, nil
+		}
+	}
+	return domain.Provider{}, domain.ChannelTemplateProvider{}, fmt.Errorf(
+		"%w: cannot find approved provider for channel template version, version id = %d", errs.ErrRecordNotFound, versionId,
+	)
+}
+
 // SaveProviders 新增版本关联供应商。
-// TODO: 需要增加调度任务自动提交到供应商侧审批。
+// 每个变体需要单独关联供应商，关联的变体必须存在于版本中。
+// 保存成功后按版本发送关联事件，由同步任务提交到供应商侧审批。
 func (s *DefaultService) SaveProviders(ctx context.Context, providers []domain.ChannelTemplateProvider) error {
+	versions := make(map[uint64]domain.ChannelTemplateVersion)
 	for i := range providers {
 		if err := providers[i].Validate(); err != nil {
 			return err
 		}
+
+		version, ok := versions[providers[i].TplVersionId]
+		if !ok {
+			var err error
+			if version, err = s.repo.FindVersionById(ctx, providers[i].TplVersionId); err != nil {
+				return err
+			}
+			versions[version.Id] = version
+		}
+
+		providers[i].Locale = domain.NormalizeLocale(providers[i].Locale)
+		if _, ok = version.Variant(providers[i].Locale); !ok {
+			return fmt.Errorf("%w: variant [ %s ] not found in version, version id = %d", errs.ErrInvalidParam, providers[i].Locale, version.Id)
+		}
 	}
 
 	err := s.repo.SaveProviders(ctx, providers)
@@ -109,7 +454,25 @@ func (s *DefaultService) SaveProviders(ctx context.Context, providers []domain.C
 		return err
 	}
 
-	// TODO: 提交消息到 kafka，消费者端来提交模板到供应商侧进行审核
+	events := make(map[uint64]*domain.TplProviderBindEvent)
+	for _, provider := range providers {
+		event, ok := events[provider.TplVersionId]
+		if !ok {
+			event = &domain.TplProviderBindEvent{
+				TplId:        provider.TplId,
+				TplVersionId: provider.TplVersionId,
+			}
+			events[provider.TplVersionId] = event
+		}
+		event.ProviderIds = append(event.ProviderIds, provider.ProviderId)
+	}
+
+	// 这里发送失败不回滚，同步任务启动时会重新提交处于“待审核”状态的供应商。
+	for _, event := range events {
+		if err = s.bindProducer.Produce(ctx, *event); err != nil {
+			return fmt.Errorf("[kuryr] failed to produce template provider bind event: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -123,8 +486,22 @@ func (s *DefaultService) FindProviderByVersionId(ctx context.Context, versionId
 	return s.repo.FindProviderByVersionId(ctx, versionId)
 }
 
-func NewDefaultService(repo repository.ChannelTplRepo) *DefaultService {
+func NewDefaultService(
+	repo repository.ChannelTplRepo,
+	providerRepo repository.ProviderRepo,
+	quotaSvc quota.Service,
+	bindProducer mq.Producer[domain.TplProviderBindEvent],
+	smsClients *sms.ClientManager,
+	reviewCooldown time.Duration,
+	testReceivers []string,
+) *DefaultService {
 	return &DefaultService{
-		repo: repo,
+		repo:           repo,
+		providerRepo:   providerRepo,
+		quotaSvc:       quotaSvc,
+		bindProducer:   bindProducer,
+		smsClients:     smsClients,
+		reviewCooldown: reviewCooldown,
+		testReceivers:  testReceivers,
 	}
 }
@@ -0,0 +1,338 @@
+package tplsync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/JrMarcco/kuryr/internal/domain"
+	"github.com/JrMarcco/kuryr/internal/errs"
+	"github.com/JrMarcco/kuryr/internal/pkg/election"
+	"github.com/JrMarcco/kuryr/internal/repository"
+	"github.com/JrMarcco/kuryr/internal/service/provider/sms"
+	"github.com/JrMarcco/kuryr/internal/service/provider/sms/client"
+	"go.uber.org/zap"
+)
+
+// queryBatchSize 单次查询供应商侧模板状态的最大模板数。
+const queryBatchSize = 100
+
+// Worker 模板供应商同步任务。
+//
+// 处理模板版本关联供应商事件 ( 由 mq.Consumer 消费后调用 Submit )：
+// 调用供应商 CreateTemplate 接口提交审核，回填供应商侧模板 id 与审批请求 id。
+// 提交前以 CAS 的方式占用供应商的提交权，避免并发消费、重试以及补偿任务重复创建供应商侧模板。
+//
+// 多副本部署时通过 etcd 选主，只有 leader 定时执行补偿任务：
+//  1. 重新提交“待审核”的供应商 ( 例如提交失败或者提交过程中重启 )。
+//  2. 按供应商批量查询“审核中”的模板状态，直到审核通过或者拒绝，更新供应商侧审批状态。
+//     每个供应商模板按查询次数指数退避 ( interval * 2^n，最长 maxPollInterval )，避免长时间审核的模板被频繁查询。
+type Worker struct {
+	tplRepo repository.ChannelTplRepo
+	clients *sms.ClientManager
+
+	elector         *election.Elector
+	interval        time.Duration // 补偿任务执行间隔
+	submitLease     time.Duration // 提交权占用时长，超时后其他节点可以重新提交
+	maxPollInterval time.Duration // 审核状态查询的最大退避间隔
+
+	// polls 审核中的供应商模板的查询退避状态，只在 leader goroutine 中访问，成为 leader 时重置。
+	polls map[uint64]*pollState // binding id -> poll state
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	logger *zap.Logger
+}
+
+func (w *Worker) Start() error {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		w.elector.Run(w.ctx, w.lead)
+	}()
+	return nil
+}
+
+func (w *Worker) Stop() error {
+	w.cancel()
+	w.wg.Wait()
+	return nil
+}
+
+// lead 作为 leader 定时执行补偿任务，直到任务停止或者 session 失效。
+func (w *Worker) lead(ctx context.Context, sessionDone <-chan struct{}) {
+	w.polls = make(map[uint64]*pollState)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.reconcile(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-sessionDone:
+			w.logger.Warn("[kuryr] template sync worker lost leadership, etcd session expired")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// reconcile 重新提交待审核的供应商并同步审核中的供应商侧审批状态。
+func (w *Worker) reconcile(ctx context.Context) {
+	pendings, err := w.tplRepo.FindProviderByAuditStatus(ctx, domain.AuditStatusPending)
+	if err != nil {
+		w.logger.Error("[kuryr] failed to find pending template providers", zap.Error(err))
+	}
+	for _, pending := range pendings {
+		err = w.Submit(ctx, domain.TplProviderBindEvent{
+			TplId:        pending.TplId,
+			TplVersionId: pending.TplVersionId,
+			ProviderIds:  []uint64{pending.ProviderId},
+		})
+		if err != nil {
+			w.logger.Error("[kuryr] failed to resubmit template to provider", zap.Uint64("id", pending.Id), zap.Error(err))
+		}
+	}
+
+	auditings, err := w.tplRepo.FindProviderByAuditStatus(ctx, domain.AuditStatusAuditing)
+	if err != nil {
+		w.logger.Error("[kuryr] failed to find auditing template providers", zap.Error(err))
+		return
+	}
+
+	// 按供应商分组批量查询，跳过还在退避中的供应商模板。
+	now := time.Now()
+	auditingIds := make(map[uint64]struct{}, len(auditings))
+	providerGroup := make(map[uint64][]domain.ChannelTemplateProvider)
+	for _, auditing := range auditings {
+		if auditing.ProviderTplId == "" {
+			continue
+		}
+		auditingIds[auditing.Id] = struct{}{}
+
+		// 重新提交后供应商侧模板 id 变化，重新开始退避。
+		if state, ok := w.polls[auditing.Id]; ok && state.providerTplId == auditing.ProviderTplId && now.Before(state.nextPollAt) {
+			continue
+		}
+		providerGroup[auditing.ProviderId] = append(providerGroup[auditing.ProviderId], auditing)
+	}
+
+	// 清理已经结束审核的退避状态。
+	for id := range w.polls {
+		if _, ok := auditingIds[id]; !ok {
+			delete(w.polls, id)
+		}
+	}
+
+	for providerId, bindings := range providerGroup {
+		if ctx.Err() != nil {
+			return
+		}
+
+		smsClient, clientErr := w.clients.Get(ctx, providerId)
+		if clientErr != nil {
+			w.logger.Error("[kuryr] failed to get sms client", zap.Uint64("provider_id", providerId), zap.Error(clientErr))
+			continue
+		}
+
+		for batch := range slices.Chunk(bindings, queryBatchSize) {
+			w.syncAuditStatus(ctx, providerId, batch, smsClient)
+		}
+	}
+}
+
+// syncAuditStatus 查询同一个供应商下的模板审核状态，更新审核结束的供应商。
+func (w *Worker) syncAuditStatus(
+	ctx context.Context, providerId uint64, bindings []domain.ChannelTemplateProvider, smsClient client.SmsClient,
+) {
+	tplIds := make([]string, 0, len(bindings))
+	for _, binding := range bindings {
+		tplIds = append(tplIds, binding.ProviderTplId)
+	}
+
+	resp, err := smsClient.QueryTemplateStatus(client.QueryTplStatusReq{TemplateIds: tplIds})
+	if err != nil {
+		w.logger.Warn("[kuryr] failed to query template audit status", zap.Uint64("provider_id", providerId), zap.Error(err))
+		// 查询失败同样计入退避，避免供应商异常时持续请求。
+		for _, binding := range bindings {
+			w.backoff(binding)
+		}
+		return
+	}
+
+	for _, binding := range bindings {
+		status, ok := resp.Results[binding.ProviderTplId]
+		if !ok || (!status.AuditStatus.IsApproved() && !status.AuditStatus.IsRejected()) {
+			w.backoff(binding)
+			continue
+		}
+
+		binding.AuditStatus = status.AuditStatus
+		binding.RejectionReason = status.Reason
+		if err = w.tplRepo.UpdateProviderAudit(ctx, binding); err != nil {
+			w.logger.Error("[kuryr] failed to update template provider audit status", zap.Uint64("id", binding.Id), zap.Error(err))
+			continue
+		}
+
+		w.logger.Info(
+			"[kuryr] template provider audit finished",
+			zap.Uint64("id", binding.Id),
+			zap.String("provider_tpl_id", binding.ProviderTplId),
+			zap.String("audit_status", binding.AuditStatus.String()),
+		)
+	}
+}
+
+// pollState 审核中的供应商模板的查询退避状态。
+type pollState struct {
+	providerTplId string
+	attempts      int
+	nextPollAt    time.Time
+}
+
+// backoff 记录一次未结束审核的查询，计算下一次查询时间。
+func (w *Worker) backoff(binding domain.ChannelTemplateProvider) {
+	if w.polls == nil {
+		w.polls = make(map[uint64]*pollState)
+	}
+
+	state, ok := w.polls[binding.Id]
+	if !ok || state.providerTplId != binding.ProviderTplId {
+		state = &pollState{providerTplId: binding.ProviderTplId}
+		w.polls[binding.Id] = state
+	}
+
+	state.attempts++
+	state.nextPollAt = time.Now().Add(w.pollInterval(state.attempts))
+}
+
+// pollInterval 第 attempts 次查询后的退避间隔：interval * 2^attempts，最长 maxPollInterval。
+func (w *Worker) pollInterval(attempts int) time.Duration {
+	interval := w.interval
+	for range attempts {
+		if interval >= w.maxPollInterval/2 {
+			return max(w.maxPollInterval, w.interval)
+		}
+		interval *= 2
+	}
+	return interval
+}
+
+// Submit 把模板版本提交到供应商侧审核。
+func (w *Worker) Submit(ctx context.Context, event domain.TplProviderBindEvent) error {
+	tpl, err := w.tplRepo.FindTemplateById(ctx, event.TplId)
+	if err != nil {
+		return err
+	}
+
+	version, err := w.tplRepo.FindVersionById(ctx, event.TplVersionId)
+	if err != nil {
+		return err
+	}
+	if version.TplId != tpl.Id {
+		return fmt.Errorf("%w: template id and version id mismatch", errs.ErrInvalidParam)
+	}
+
+	bindings, err := w.tplRepo.FindProviderByVersionId(ctx, version.Id)
+	if err != nil {
+		return err
+	}
+
+	var errList []error
+	for _, binding := range bindings {
+		if !binding.AuditStatus.IsPending() {
+			continue
+		}
+		if len(event.ProviderIds) > 0 && !slices.Contains(event.ProviderIds, binding.ProviderId) {
+			continue
+		}
+
+		if err = w.submit(ctx, tpl, version, binding); err != nil {
+			errList = append(errList, fmt.Errorf("provider id = %d: %w", binding.ProviderId, err))
+		}
+	}
+	return errors.Join(errList...)
+}
+
+func (w *Worker) submit(
+	ctx context.Context,
+	tpl domain.ChannelTemplate,
+	version domain.ChannelTemplateVersion,
+	binding domain.ChannelTemplateProvider,
+) error {
+	// 每个变体在供应商侧单独创建模板
+	variant, ok := version.Variant(binding.Locale)
+	if !ok {
+		return fmt.Errorf("%w: variant [ %s ] not found in version, version id = %d", errs.ErrInvalidParam, binding.Locale, version.Id)
+	}
+
+	smsClient, err := w.clients.Get(ctx, binding.ProviderId)
+	if err != nil {
+		return err
+	}
+
+	// 占用失败说明其他节点正在提交或者已经提交完成，直接跳过。
+	acquired, err := w.tplRepo.AcquireProviderSubmit(ctx, binding.Id, w.submitLease.Milliseconds())
+	if err != nil {
+		return err
+	}
+	if !acquired {
+		return nil
+	}
+
+	tplName := fmt.Sprintf("%s_%s", tpl.TplName, version.VersionName)
+	if variant.Locale != domain.LocaleDefault {
+		tplName = fmt.Sprintf("%s_%s", tplName, variant.Locale)
+	}
+	resp, err := smsClient.CreateTemplate(client.CreateTplReq{
+		TplName:    tplName,
+		TplContent: variant.Content,
+		Remark:     version.ApplyRemark,
+	})
+	if err != nil {
+		// 释放提交权，让重试或者补偿任务可以立即重新提交。
+		if releaseErr := w.tplRepo.ReleaseProviderSubmit(ctx, binding.Id); releaseErr != nil {
+			w.logger.Warn("[kuryr] failed to release template provider submit", zap.Uint64("id", binding.Id), zap.Error(releaseErr))
+		}
+		return err
+	}
+
+	binding.ProviderTplId = resp.TemplateId
+	binding.AuditRequestId = resp.RequestId
+	binding.AuditStatus = domain.AuditStatusAuditing
+	binding.RejectionReason = ""
+	binding.LastReviewAt = time.Now().UnixMilli()
+	return w.tplRepo.UpdateProviderAudit(ctx, binding)
+}
+
+func NewWorker(
+	tplRepo repository.ChannelTplRepo,
+	clients *sms.ClientManager,
+	elector *election.Elector,
+	interval time.Duration,
+	submitLease time.Duration,
+	maxPollInterval time.Duration,
+	logger *zap.Logger,
+) *Worker {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Worker{
+		tplRepo:         tplRepo,
+		clients:         clients,
+		elector:         elector,
+		interval:        interval,
+		submitLease:     submitLease,
+		maxPollInterval: maxPollInterval,
+		polls:           make(map[uint64]*pollState),
+		ctx:             ctx,
+		cancel:          cancel,
+		logger:          logger,
+	}
+}
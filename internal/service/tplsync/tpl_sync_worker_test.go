@@ -0,0 +1,249 @@
+package tplsync
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JrMarcco/kuryr/internal/domain"
+	"github.com/JrMarcco/kuryr/internal/repository"
+	"github.com/JrMarcco/kuryr/internal/service/provider/sms"
+	"github.com/JrMarcco/kuryr/internal/service/provider/sms/client"
+	clientmock "github.com/JrMarcco/kuryr/internal/service/provider/sms/client/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+// stubTplRepo 内存实现，提交权的占用与数据库中的 CAS 语义一致。
+type stubTplRepo struct {
+	repository.ChannelTplRepo
+
+	mu       sync.Mutex
+	bindings map[uint64]domain.ChannelTemplateProvider
+}
+
+func (r *stubTplRepo) FindTemplateById(_ context.Context, id uint64) (domain.ChannelTemplate, error) {
+	return domain.ChannelTemplate{Id: id, TplName: "tpl"}, nil
+}
+
+func (r *stubTplRepo) FindVersionById(_ context.Context, id uint64) (domain.ChannelTemplateVersion, error) {
+	return domain.ChannelTemplateVersion{Id: id, TplId: 1, VersionName: "v1", Content: "验证码 {1}"}, nil
+}
+
+func (r *stubTplRepo) FindProviderByVersionId(_ context.Context, versionId uint64) ([]domain.ChannelTemplateProvider, error) {
+	return r.find(func(b domain.ChannelTemplateProvider) bool { return b.TplVersionId == versionId }), nil
+}
+
+func (r *stubTplRepo) FindProviderByAuditStatus(_ context.Context, auditStatus domain.AuditStatus) ([]domain.ChannelTemplateProvider, error) {
+	return r.find(func(b domain.ChannelTemplateProvider) bool { return b.AuditStatus == auditStatus }), nil
+}
+
+func (r *stubTplRepo) UpdateProviderAudit(_ context.Context, binding domain.ChannelTemplateProvider) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.bindings[binding.Id] = binding
+	return nil
+}
+
+func (r *stubTplRepo) AcquireProviderSubmit(_ context.Context, id uint64, leaseMillis int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now().UnixMilli()
+	binding := r.bindings[id]
+	if binding.AuditStatus != domain.AuditStatusPending || binding.LastReviewAt >= now-leaseMillis {
+		return false, nil
+	}
+	binding.LastReviewAt = now
+	r.bindings[id] = binding
+	return true, nil
+}
+
+func (r *stubTplRepo) ReleaseProviderSubmit(_ context.Context, id uint64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	binding := r.bindings[id]
+	if binding.AuditStatus == domain.AuditStatusPending {
+		binding.LastReviewAt = 0
+		r.bindings[id] = binding
+	}
+	return nil
+}
+
+func (r *stubTplRepo) find(pred func(b domain.ChannelTemplateProvider) bool) []domain.ChannelTemplateProvider {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var res []domain.ChannelTemplateProvider
+	for _, binding := range r.bindings {
+		if pred(binding) {
+			res = append(res, binding)
+		}
+	}
+	return res
+}
+
+type stubProviderRepo struct {
+	repository.ProviderRepo
+}
+
+func (r *stubProviderRepo) FindById(_ context.Context, id uint64) (domain.Provider, error) {
+	return domain.Provider{Id: id, ProviderName: client.ProviderNameTencent}, nil
+}
+
+func newTestWorker(tplRepo *stubTplRepo, smsClient client.SmsClient) *Worker {
+	clients := sms.NewClientManager(&stubProviderRepo{}, func(domain.Provider) (client.SmsClient, error) {
+		return smsClient, nil
+	})
+	return NewWorker(tplRepo, clients, nil, time.Minute, time.Minute, time.Hour, zap.NewNop())
+}
+
+func TestWorker_Submit(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name         string
+		lastReviewAt int64 // 为当前时间表示提交权已被占用
+		mockFunc     func(ctrl *gomock.Controller) *clientmock.MockSmsClient
+		wantErr      bool
+		wantBinding  domain.ChannelTemplateProvider
+	}{
+		{
+			name: "submitted",
+			mockFunc: func(ctrl *gomock.Controller) *clientmock.MockSmsClient {
+				mock := clientmock.NewMockSmsClient(ctrl)
+				mock.EXPECT().CreateTemplate(client.CreateTplReq{TplName: "tpl_v1", TplContent: "验证码 {1}"}).
+					Return(client.CreateTplResp{RequestId: "req-1", TemplateId: "1001"}, nil)
+				return mock
+			},
+			wantBinding: domain.ChannelTemplateProvider{
+				ProviderTplId:  "1001",
+				AuditRequestId: "req-1",
+				AuditStatus:    domain.AuditStatusAuditing,
+			},
+		}, {
+			name:         "submitting by other node",
+			lastReviewAt: time.Now().UnixMilli(),
+			mockFunc: func(ctrl *gomock.Controller) *clientmock.MockSmsClient {
+				return clientmock.NewMockSmsClient(ctrl)
+			},
+			wantBinding: domain.ChannelTemplateProvider{AuditStatus: domain.AuditStatusPending},
+		}, {
+			name: "release on failure",
+			mockFunc: func(ctrl *gomock.Controller) *clientmock.MockSmsClient {
+				mock := clientmock.NewMockSmsClient(ctrl)
+				mock.EXPECT().CreateTemplate(gomock.Any()).Return(client.CreateTplResp{}, errors.New("mock vendor error"))
+				return mock
+			},
+			wantErr:     true,
+			wantBinding: domain.ChannelTemplateProvider{AuditStatus: domain.AuditStatusPending},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			tplRepo := &stubTplRepo{bindings: map[uint64]domain.ChannelTemplateProvider{
+				1: {Id: 1, TplId: 1, TplVersionId: 10, ProviderId: 100, AuditStatus: domain.AuditStatusPending, LastReviewAt: tc.lastReviewAt},
+			}}
+			w := newTestWorker(tplRepo, tc.mockFunc(ctrl))
+
+			err := w.Submit(context.Background(), domain.TplProviderBindEvent{TplId: 1, TplVersionId: 10})
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			binding := tplRepo.bindings[1]
+			assert.Equal(t, tc.wantBinding.AuditStatus, binding.AuditStatus)
+			assert.Equal(t, tc.wantBinding.ProviderTplId, binding.ProviderTplId)
+			assert.Equal(t, tc.wantBinding.AuditRequestId, binding.AuditRequestId)
+			if tc.wantErr {
+				// 提交失败后释放提交权，可以立即重新提交。
+				assert.Zero(t, binding.LastReviewAt)
+			}
+		})
+	}
+}
+
+func TestWorker_Reconcile(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tplRepo := &stubTplRepo{bindings: map[uint64]domain.ChannelTemplateProvider{
+		// 提交中断的供应商重新提交
+		1: {Id: 1, TplId: 1, TplVersionId: 10, ProviderId: 100, AuditStatus: domain.AuditStatusPending},
+		// 审核中的供应商按供应商批量查询
+		2: {Id: 2, TplId: 1, TplVersionId: 11, ProviderId: 100, ProviderTplId: "2001", AuditStatus: domain.AuditStatusAuditing},
+		3: {Id: 3, TplId: 1, TplVersionId: 12, ProviderId: 100, ProviderTplId: "2002", AuditStatus: domain.AuditStatusAuditing},
+	}}
+
+	smsClient := clientmock.NewMockSmsClient(ctrl)
+	smsClient.EXPECT().CreateTemplate(gomock.Any()).Return(client.CreateTplResp{RequestId: "req-1", TemplateId: "1001"}, nil)
+	smsClient.EXPECT().QueryTemplateStatus(gomock.Any()).
+		DoAndReturn(func(req client.QueryTplStatusReq) (client.QueryTplStatusResp, error) {
+			// 刚提交的供应商也在审核中，一起查询
+			assert.ElementsMatch(t, []string{"1001", "2001", "2002"}, req.TemplateIds)
+			return client.QueryTplStatusResp{Results: map[string]client.TplStatus{
+				"1001": {TemplateId: "1001", AuditStatus: domain.AuditStatusPending},
+				"2001": {TemplateId: "2001", AuditStatus: domain.AuditStatusApproved},
+				"2002": {TemplateId: "2002", AuditStatus: domain.AuditStatusRejected, Reason: "签名不规范"},
+			}}, nil
+		})
+
+	w := newTestWorker(tplRepo, smsClient)
+	w.reconcile(context.Background())
+
+	assert.Equal(t, domain.AuditStatusAuditing, tplRepo.bindings[1].AuditStatus)
+	assert.Equal(t, domain.AuditStatusApproved, tplRepo.bindings[2].AuditStatus)
+	assert.Equal(t, domain.AuditStatusRejected, tplRepo.bindings[3].AuditStatus)
+	assert.Equal(t, "签名不规范", tplRepo.bindings[3].RejectionReason)
+
+	// 仍在审核中的供应商退避，未到查询时间不再查询
+	w.reconcile(context.Background())
+	assert.Equal(t, 1, w.polls[1].attempts)
+	assert.NotContains(t, w.polls, uint64(2))
+
+	// 到达查询时间后重新查询，并增加退避
+	w.polls[1].nextPollAt = time.Now().Add(-time.Second)
+	smsClient.EXPECT().QueryTemplateStatus(client.QueryTplStatusReq{TemplateIds: []string{"1001"}}).
+		Return(client.QueryTplStatusResp{Results: map[string]client.TplStatus{}}, nil)
+	w.reconcile(context.Background())
+	assert.Equal(t, 2, w.polls[1].attempts)
+}
+
+func TestWorker_PollInterval(t *testing.T) {
+	t.Parallel()
+
+	w := newTestWorker(&stubTplRepo{}, nil)
+
+	tcs := []struct {
+		name     string
+		attempts int
+		wantRes  time.Duration
+	}{
+		{name: "first attempt", attempts: 1, wantRes: 2 * time.Minute},
+		{name: "third attempt", attempts: 3, wantRes: 8 * time.Minute},
+		{name: "capped by max poll interval", attempts: 10, wantRes: time.Hour},
+		{name: "no overflow", attempts: 100, wantRes: time.Hour},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.wantRes, w.pollInterval(tc.attempts))
+		})
+	}
+}
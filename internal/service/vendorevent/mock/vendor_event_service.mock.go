@@ -0,0 +1,118 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./vendor_event_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./vendor_event_service.go -destination=./mock/vendor_event_service.mock.go -package=vendoreventmock -typed Service
+//
+
+// Package vendoreventmock is a generated GoMock package.
+package vendoreventmock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/JrMarcco/kuryr/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockService is a mock of Service interface.
+type MockService struct {
+	ctrl     *gomock.Controller
+	recorder *MockServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockServiceMockRecorder is the mock recorder for MockService.
+type MockServiceMockRecorder struct {
+	mock *MockService
+}
+
+// NewMockService creates a new mock instance.
+func NewMockService(ctrl *gomock.Controller) *MockService {
+	mock := &MockService{ctrl: ctrl}
+	mock.recorder = &MockServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockService) EXPECT() *MockServiceMockRecorder {
+	return m.recorder
+}
+
+// HandleDeliveryReceipt mocks base method.
+func (m *MockService) HandleDeliveryReceipt(ctx context.Context, events []domain.DeliveryReceiptEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleDeliveryReceipt", ctx, events)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HandleDeliveryReceipt indicates an expected call of HandleDeliveryReceipt.
+func (mr *MockServiceMockRecorder) HandleDeliveryReceipt(ctx, events any) *MockServiceHandleDeliveryReceiptCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleDeliveryReceipt", reflect.TypeOf((*MockService)(nil).HandleDeliveryReceipt), ctx, events)
+	return &MockServiceHandleDeliveryReceiptCall{Call: call}
+}
+
+// MockServiceHandleDeliveryReceiptCall wrap *gomock.Call
+type MockServiceHandleDeliveryReceiptCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceHandleDeliveryReceiptCall) Return(arg0 error) *MockServiceHandleDeliveryReceiptCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceHandleDeliveryReceiptCall) Do(f func(context.Context, []domain.DeliveryReceiptEvent) error) *MockServiceHandleDeliveryReceiptCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceHandleDeliveryReceiptCall) DoAndReturn(f func(context.Context, []domain.DeliveryReceiptEvent) error) *MockServiceHandleDeliveryReceiptCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// HandleTplAudit mocks base method.
+func (m *MockService) HandleTplAudit(ctx context.Context, events []domain.ProviderTplAuditEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleTplAudit", ctx, events)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HandleTplAudit indicates an expected call of HandleTplAudit.
+func (mr *MockServiceMockRecorder) HandleTplAudit(ctx, events any) *MockServiceHandleTplAuditCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleTplAudit", reflect.TypeOf((*MockService)(nil).HandleTplAudit), ctx, events)
+	return &MockServiceHandleTplAuditCall{Call: call}
+}
+
+// MockServiceHandleTplAuditCall wrap *gomock.Call
+type MockServiceHandleTplAuditCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceHandleTplAuditCall) Return(arg0 error) *MockServiceHandleTplAuditCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceHandleTplAuditCall) Do(f func(context.Context, []domain.ProviderTplAuditEvent) error) *MockServiceHandleTplAuditCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceHandleTplAuditCall) DoAndReturn(f func(context.Context, []domain.ProviderTplAuditEvent) error) *MockServiceHandleTplAuditCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
@@ -0,0 +1,108 @@
+package vendorevent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/JrMarcco/kuryr/internal/domain"
+	"github.com/JrMarcco/kuryr/internal/errs"
+	"github.com/JrMarcco/kuryr/internal/repository"
+	"go.uber.org/zap"
+)
+
+//go:generate mockgen -source=./vendor_event_service.go -destination=./mock/vendor_event_service.mock.go -package=vendoreventmock -typed Service
+
+// Service 供应商推送事件处理服务。
+type Service interface {
+	// HandleTplAudit 处理供应商侧模板审核结果。
+	HandleTplAudit(ctx context.Context, events []domain.ProviderTplAuditEvent) error
+	// HandleDeliveryReceipt 处理供应商侧消息送达回执。
+	HandleDeliveryReceipt(ctx context.Context, events []domain.DeliveryReceiptEvent) error
+}
+
+var _ Service = (*DefaultService)(nil)
+
+type DefaultService struct {
+	tplRepo     repository.ChannelTplRepo
+	receiptRepo repository.DeliveryReceiptRepo
+	logger      *zap.Logger
+}
+
+// HandleTplAudit 处理模板审核结果。
+// 非法的事件跳过并记录日志，不影响同一批次中其他事件的处理。
+func (s *DefaultService) HandleTplAudit(ctx context.Context, events []domain.ProviderTplAuditEvent) error {
+	for _, event := range events {
+		if err := s.validateTplAuditEvent(event); err != nil {
+			s.logger.Warn(
+				"[kuryr] invalid provider template audit event, skipped",
+				zap.Uint64("provider_id", event.ProviderId),
+				zap.String("provider_tpl_id", event.ProviderTplId),
+				zap.Error(err),
+			)
+			continue
+		}
+
+		if err := s.tplRepo.UpdateProviderAuditStatus(ctx, event); err != nil {
+			return err
+		}
+
+		s.logger.Info(
+			"[kuryr] provider template audit status updated",
+			zap.Uint64("provider_id", event.ProviderId),
+			zap.String("provider_tpl_id", event.ProviderTplId),
+			zap.String("audit_status", event.AuditStatus.String()),
+		)
+	}
+	return nil
+}
+
+func (s *DefaultService) validateTplAuditEvent(event domain.ProviderTplAuditEvent) error {
+	if event.ProviderTplId == "" {
+		return fmt.Errorf("%w: provider template id cannot be empty", errs.ErrInvalidParam)
+	}
+	if !event.AuditStatus.IsValid() {
+		return fmt.Errorf("%w: invalid audit status [ %s ]", errs.ErrInvalidParam, event.AuditStatus)
+	}
+	return nil
+}
+
+// HandleDeliveryReceipt 处理送达回执。
+// 缺少流水号的回执无法关联发送记录，跳过并记录日志，其余回执批量落库。
+func (s *DefaultService) HandleDeliveryReceipt(ctx context.Context, events []domain.DeliveryReceiptEvent) error {
+	receipts := make([]domain.DeliveryReceiptEvent, 0, len(events))
+	for _, event := range events {
+		if event.SerialNo == "" {
+			s.logger.Warn(
+				"[kuryr] delivery receipt without serial no, skipped",
+				zap.Uint64("provider_id", event.ProviderId),
+				zap.String("receiver", event.Receiver),
+			)
+			continue
+		}
+
+		if !event.Success {
+			s.logger.Warn(
+				"[kuryr] delivery receipt received with failure",
+				zap.Uint64("provider_id", event.ProviderId),
+				zap.String("serial_no", event.SerialNo),
+				zap.String("receiver", event.Receiver),
+				zap.String("err_code", event.ErrCode),
+				zap.String("err_msg", event.ErrMsg),
+			)
+		}
+		receipts = append(receipts, event)
+	}
+
+	if len(receipts) == 0 {
+		return nil
+	}
+	return s.receiptRepo.BatchSave(ctx, receipts)
+}
+
+func NewDefaultService(tplRepo repository.ChannelTplRepo, receiptRepo repository.DeliveryReceiptRepo, logger *zap.Logger) *DefaultService {
+	return &DefaultService{
+		tplRepo:     tplRepo,
+		receiptRepo: receiptRepo,
+		logger:      logger,
+	}
+}
@@ -0,0 +1,63 @@
+package vendorevent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/JrMarcco/kuryr/internal/domain"
+	"github.com/JrMarcco/kuryr/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type stubTplRepo struct {
+	repository.ChannelTplRepo
+
+	updated []domain.ProviderTplAuditEvent
+}
+
+func (r *stubTplRepo) UpdateProviderAuditStatus(_ context.Context, event domain.ProviderTplAuditEvent) error {
+	r.updated = append(r.updated, event)
+	return nil
+}
+
+type stubReceiptRepo struct {
+	saved []domain.DeliveryReceiptEvent
+}
+
+func (r *stubReceiptRepo) BatchSave(_ context.Context, events []domain.DeliveryReceiptEvent) error {
+	r.saved = append(r.saved, events...)
+	return nil
+}
+
+func TestDefaultService_HandleTplAudit(t *testing.T) {
+	t.Parallel()
+
+	tplRepo := &stubTplRepo{}
+	svc := NewDefaultService(tplRepo, &stubReceiptRepo{}, zap.NewNop())
+
+	events := []domain.ProviderTplAuditEvent{
+		{ProviderId: 1, ProviderTplId: "", AuditStatus: domain.AuditStatusApproved},
+		{ProviderId: 1, ProviderTplId: "1001", AuditStatus: "unknown"},
+		{ProviderId: 1, ProviderTplId: "1002", AuditStatus: domain.AuditStatusApproved},
+	}
+	require.NoError(t, svc.HandleTplAudit(context.Background(), events))
+	// 非法事件跳过，其余事件正常处理。
+	assert.Equal(t, events[2:], tplRepo.updated)
+}
+
+func TestDefaultService_HandleDeliveryReceipt(t *testing.T) {
+	t.Parallel()
+
+	receiptRepo := &stubReceiptRepo{}
+	svc := NewDefaultService(&stubTplRepo{}, receiptRepo, zap.NewNop())
+
+	events := []domain.DeliveryReceiptEvent{
+		{ProviderId: 1, SerialNo: "sn-1", Receiver: "13800000000", Success: true},
+		{ProviderId: 1, Receiver: "13800000001"},
+		{ProviderId: 1, SerialNo: "sn-2", Receiver: "13800000002", ErrCode: "MK:0001"},
+	}
+	require.NoError(t, svc.HandleDeliveryReceipt(context.Background(), events))
+	assert.Equal(t, []domain.DeliveryReceiptEvent{events[0], events[2]}, receiptRepo.saved)
+}
@@ -166,6 +166,8 @@ CREATE TABLE channel_template_version (
     version_name VARCHAR(128) NOT NULL,
     signature VARCHAR(128) NOT NULL,
     context TEXT NOT NULL,
+    param_schema JSONB,
+    variants JSONB,
     apply_remark VARCHAR(128) NOT NULL,
     auditor_id BIGINT NOT NULL,
     audit_id BIGINT NOT NULL,
@@ -183,6 +185,8 @@ COMMENT ON COLUMN channel_template_version.tpl_id IS '模板 id';
 COMMENT ON COLUMN channel_template_version.version_name IS '版本名';
 COMMENT ON COLUMN channel_template_version.signature IS '签名信息';
 COMMENT ON COLUMN channel_template_version.context IS '模板内容';
+COMMENT ON COLUMN channel_template_version.param_schema IS '模板参数定义 ( 从模板内容中解析的占位符及其约束 )';
+COMMENT ON COLUMN channel_template_version.variants IS '多语言变体 ( 每个变体包含语言区域、签名、内容及参数定义 )';
 COMMENT ON COLUMN channel_template_version.apply_remark IS '申请备注信息';
 COMMENT ON COLUMN channel_template_version.auditor_id IS '审批人 id';
 COMMENT ON COLUMN channel_template_version.audit_id IS '审批记录 id';
@@ -197,6 +201,60 @@ COMMENT ON COLUMN channel_template_version.updated_at IS '更新时间戳 ( Unix
 -- 查询场景： where tpl_id = ? / where tpl_id = ? and audit_status = ?
 CREATE INDEX idx_channel_template_version_tpl_audit ON channel_template_version(tpl_id, audit_status);
 
+-- 渠道模板版本审批记录表
+DROP TABLE IF EXISTS channel_template_audit_log;
+CREATE TABLE channel_template_audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    tpl_id BIGINT NOT NULL,
+    tpl_version_id BIGINT NOT NULL,
+    action VARCHAR(16) NOT NULL,
+    from_status audit_status_enum NOT NULL,
+    to_status audit_status_enum NOT NULL,
+    operator_id BIGINT NOT NULL,
+    remark VARCHAR(128) NOT NULL,
+    created_at BIGINT NOT NULL
+);
+
+COMMENT ON TABLE channel_template_audit_log IS '渠道模板版本审批记录表';
+COMMENT ON COLUMN channel_template_audit_log.id IS 'id';
+COMMENT ON COLUMN channel_template_audit_log.tpl_id IS '模板 id';
+COMMENT ON COLUMN channel_template_audit_log.tpl_version_id IS '模板版本 id';
+COMMENT ON COLUMN channel_template_audit_log.action IS '审批操作 ( submit / approve / reject )';
+COMMENT ON COLUMN channel_template_audit_log.from_status IS '操作前审批状态';
+COMMENT ON COLUMN channel_template_audit_log.to_status IS '操作后审批状态';
+COMMENT ON COLUMN channel_template_audit_log.operator_id IS '操作人 id';
+COMMENT ON COLUMN channel_template_audit_log.remark IS '备注信息 ( 拒绝时为拒绝理由 )';
+COMMENT ON COLUMN channel_template_audit_log.created_at IS '创建时间戳 ( Unix 毫秒值 )';
+
+-- 字段索引：版本 id
+-- 查询场景：where tpl_version_id = ?
+CREATE INDEX idx_channel_template_audit_log_version ON channel_template_audit_log(tpl_version_id);
+
+-- 渠道模板版本激活记录表
+DROP TABLE IF EXISTS channel_template_activation_log;
+CREATE TABLE channel_template_activation_log (
+    id BIGSERIAL PRIMARY KEY,
+    tpl_id BIGINT NOT NULL,
+    from_version_id BIGINT NOT NULL,
+    to_version_id BIGINT NOT NULL,
+    action VARCHAR(16) NOT NULL,
+    operator_id BIGINT NOT NULL,
+    created_at BIGINT NOT NULL
+);
+
+COMMENT ON TABLE channel_template_activation_log IS '渠道模板版本激活记录表';
+COMMENT ON COLUMN channel_template_activation_log.id IS 'id';
+COMMENT ON COLUMN channel_template_activation_log.tpl_id IS '模板 id';
+COMMENT ON COLUMN channel_template_activation_log.from_version_id IS '操作前激活版本 id ( 0 表示之前没有激活版本 )';
+COMMENT ON COLUMN channel_template_activation_log.to_version_id IS '操作后激活版本 id';
+COMMENT ON COLUMN channel_template_activation_log.action IS '激活操作 ( activate / rollback )';
+COMMENT ON COLUMN channel_template_activation_log.operator_id IS '操作人 id';
+COMMENT ON COLUMN channel_template_activation_log.created_at IS '创建时间戳 ( Unix 毫秒值 )';
+
+-- 字段索引：模板 id
+-- 查询场景：where tpl_id = ?
+CREATE INDEX idx_channel_template_activation_log_tpl ON channel_template_activation_log(tpl_id);
+
 -- 渠道模板供应商信息表
 DROP TABLE IF EXISTS channel_template_provider;
 CREATE TABLE channel_template_provider (
@@ -207,6 +265,7 @@ CREATE TABLE channel_template_provider (
     provider_name VARCHAR(128) NOT NULL,
     provider_channel channel_enum NOT NULL,
     provider_tpl_id VARCHAR(128) NOT NULL,
+    locale VARCHAR(32) NOT NULL DEFAULT '',
     audit_request_id VARCHAR(64) NOT NULL,
     audit_status audit_status_enum NOT NULL,
     rejection_reason VARCHAR(128) NOT NULL,
@@ -223,6 +282,7 @@ COMMENT ON COLUMN channel_template_provider.provider_id IS '供应商 id';
 COMMENT ON COLUMN channel_template_provider.provider_name IS '供应商名称';
 COMMENT ON COLUMN channel_template_provider.provider_channel IS '供应商渠道';
 COMMENT ON COLUMN channel_template_provider.provider_tpl_id IS '供应商侧模板 id';
+COMMENT ON COLUMN channel_template_provider.locale IS '关联变体的语言区域 ( 空字符串表示默认变体 )';
 COMMENT ON COLUMN channel_template_provider.audit_request_id IS '审批请求 id';
 COMMENT ON COLUMN channel_template_provider.audit_status IS '审批状态';
 COMMENT ON COLUMN channel_template_provider.rejection_reason IS '审批拒绝理由';
@@ -237,3 +297,30 @@ CREATE INDEX idx_channel_template_tpl_version ON channel_template_provider(tpl_v
 -- 组合索引：模板 id + 版本 id
 -- 查询场景：where tpl_id = ? and tpl_version_id in (?) / where tpl_id = ? and tpl_version_id = ?
 CREATE INDEX idx_channel_template_provider_tpl_version ON channel_template_provider(tpl_id, tpl_version_id);
+
+-- 回调重放记录表
+DROP TABLE IF EXISTS callback_replay_log;
+CREATE TABLE callback_replay_log (
+    id BIGSERIAL PRIMARY KEY,
+    biz_id BIGINT NOT NULL,
+    callback_log_ids JSONB,
+    start_time BIGINT NOT NULL DEFAULT 0,
+    end_time BIGINT NOT NULL DEFAULT 0,
+    replayed_count BIGINT NOT NULL,
+    operator_id BIGINT NOT NULL,
+    created_at BIGINT NOT NULL
+);
+
+COMMENT ON TABLE callback_replay_log IS '回调重放记录表';
+COMMENT ON COLUMN callback_replay_log.id IS 'id';
+COMMENT ON COLUMN callback_replay_log.biz_id IS '业务 id';
+COMMENT ON COLUMN callback_replay_log.callback_log_ids IS '指定重放的回调日志 id ( 按时间范围重放时为空 )';
+COMMENT ON COLUMN callback_replay_log.start_time IS '重放时间范围起始 ( Unix 毫秒值，按 id 重放时为 0 )';
+COMMENT ON COLUMN callback_replay_log.end_time IS '重放时间范围结束 ( Unix 毫秒值，按 id 重放时为 0 )';
+COMMENT ON COLUMN callback_replay_log.replayed_count IS '实际重放的回调日志数量';
+COMMENT ON COLUMN callback_replay_log.operator_id IS '操作人 id';
+COMMENT ON COLUMN callback_replay_log.created_at IS '创建时间戳 ( Unix 毫秒值 )';
+
+-- 字段索引：业务 id
+-- 查询场景：where biz_id = ?
+CREATE INDEX idx_callback_replay_log_biz ON callback_replay_log(biz_id);
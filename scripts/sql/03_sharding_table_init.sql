@@ -0,0 +1,47 @@
+-- 分库分表：在每个分库 ( kuryr_0 ... ) 中执行
+-- 分表数量与 sharding.callback_log.table_shard_count 保持一致
+
+-- 回调日志表
+DO $$
+DECLARE
+    table_shard_count INT := 4;
+    tbl TEXT;
+BEGIN
+    FOR i IN 0..table_shard_count - 1 LOOP
+        tbl := 'callback_log_' || i;
+
+        EXECUTE format('DROP TABLE IF EXISTS %I', tbl);
+        EXECUTE format('CREATE TABLE %I (
+            id BIGINT PRIMARY KEY,
+            biz_id BIGINT NOT NULL,
+            biz_key VARCHAR(64) NOT NULL,
+            notification_id VARCHAR(64) NOT NULL,
+            notification_status VARCHAR(32) NOT NULL,
+            retried_times INT NOT NULL DEFAULT 0,
+            next_retry_at BIGINT NOT NULL DEFAULT 0,
+            callback_status VARCHAR(32) NOT NULL,
+            created_at BIGINT NOT NULL,
+            updated_at BIGINT NOT NULL
+        )', tbl);
+
+        EXECUTE format('COMMENT ON TABLE %I IS %L', tbl, '回调日志表');
+        EXECUTE format('COMMENT ON COLUMN %I.notification_id IS %L', tbl, '消息 id ( MongoDB 的 ObjectID )');
+        EXECUTE format('COMMENT ON COLUMN %I.notification_status IS %L', tbl, '消息发送状态');
+        EXECUTE format('COMMENT ON COLUMN %I.callback_status IS %L', tbl, '回调状态');
+        EXECUTE format('COMMENT ON COLUMN %I.created_at IS %L', tbl, '创建时间戳 ( Unix 毫秒值 )');
+        EXECUTE format('COMMENT ON COLUMN %I.updated_at IS %L', tbl, '更新时间戳 ( Unix 毫秒值 )');
+
+        -- 唯一索引：消息 id
+        -- outbox relay 按消息 id 写入回调日志 ( on conflict (notification_id) do update ... where callback_status in ('prepare', 'pending') )，
+        -- 回调未结束时以最新的发送状态覆盖，回调已结束 ( 成功 / 失败 ) 的记录保持不变
+        EXECUTE format('CREATE UNIQUE INDEX %I ON %I(notification_id)', 'uk_' || tbl || '_notification', tbl);
+
+        -- 联合索引：回调状态 + 下次重试时间
+        -- 查询场景：where next_retry_at <= ? and callback_status in (?) and id > ?
+        EXECUTE format('CREATE INDEX %I ON %I(callback_status, next_retry_at)', 'idx_' || tbl || '_status_retry', tbl);
+
+        -- 联合索引：业务 id + 回调状态 + 创建时间
+        -- 查询场景：where biz_id = ? and callback_status = ? and created_at >= ? and created_at < ?
+        EXECUTE format('CREATE INDEX %I ON %I(biz_id, callback_status, created_at)', 'idx_' || tbl || '_biz_status_created', tbl);
+    END LOOP;
+END $$;